@@ -0,0 +1,179 @@
+// Package apperror gives errors a stable, machine-readable code clients can switch on.
+package apperror
+
+import (
+	"gin-go-testing/model/dto"
+	"net/http"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type Code string
+
+// Codes are part of the API contract: add new ones freely but never rename or reuse one.
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeInvalidQuery         Code = "invalid_query"
+	CodeInvalidSort          Code = "invalid_sort"
+	CodeInvalidCursor        Code = "invalid_cursor"
+	CodeInvalidBookId        Code = "invalid_book_id"
+	CodeInvalidAuthorId      Code = "invalid_author_id"
+	CodeInvalidTagId         Code = "invalid_tag_id"
+	CodeInvalidTenant        Code = "invalid_tenant"
+	CodeInvalidJSON          Code = "invalid_json"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeInvalidCredentials   Code = "invalid_credentials"
+	CodeTokenExpired         Code = "token_expired"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeRouteNotFound        Code = "route_not_found"
+	CodeConflict             Code = "conflict"
+	CodeAlreadyExists        Code = "already_exists"
+	CodeInvalidReference     Code = "invalid_reference"
+	CodeConcurrentUpdate     Code = "concurrent_update"
+	CodeInUse                Code = "in_use"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeUnprocessableEntity  Code = "unprocessable_entity"
+	CodeRateLimited          Code = "rate_limited"
+	CodeClientClosedRequest  Code = "client_closed_request"
+	CodeInternal             Code = "internal_error"
+	CodeServiceUnavailable   Code = "service_unavailable"
+	CodeTimeout              Code = "timeout"
+)
+
+// StatusClientClosedRequest is the non-standard status, borrowed from nginx, of requests
+// the client gave up on before they completed.
+const StatusClientClosedRequest = 499
+
+// statusCodes is the code of errors that were not given one.
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeUnprocessableEntity,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	StatusClientClosedRequest:        CodeClientClosedRequest,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+	http.StatusGatewayTimeout:        CodeTimeout,
+}
+
+// Error is an errs.CustomError carrying a Code and, for validation failures, field errors.
+// It is also an error, so it can be handed to gin with ctx.Error.
+type Error struct {
+	status      int
+	code        Code
+	message     string
+	fieldErrors []*dto.FieldError
+	// cause is what went wrong underneath, logged but never sent to the client
+	cause error
+	// extensions are extra members of the problem details
+	extensions map[string]any
+}
+
+func New(status int, code Code, message string) *Error {
+	return &Error{status: status, code: code, message: message}
+}
+
+// Wrap is New keeping the error that caused it, reachable with errors.Is and errors.As.
+func Wrap(status int, code Code, message string, cause error) *Error {
+	return &Error{status: status, code: code, message: message, cause: cause}
+}
+
+// NewValidationError reports every invalid field of a request at once.
+func NewValidationError(fieldErrors []*dto.FieldError) *Error {
+	return &Error{
+		status:      http.StatusUnprocessableEntity,
+		code:        CodeValidationFailed,
+		message:     "validation failed",
+		fieldErrors: fieldErrors,
+	}
+}
+
+// From turns any errs.CustomError into an *Error, keeping its code.
+func From(err errs.CustomError) *Error {
+	if appErr, ok := err.(*Error); ok {
+		return appErr
+	}
+
+	return &Error{status: err.StatusCode(), code: CodeOf(err), message: err.Message()}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return string(e.code) + ": " + e.message + ": " + e.cause.Error()
+	}
+
+	return string(e.code) + ": " + e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) StatusCode() int {
+	return e.status
+}
+
+func (e *Error) Status() string {
+	return http.StatusText(e.status)
+}
+
+func (e *Error) Message() string {
+	return e.message
+}
+
+func (e *Error) Code() Code {
+	return e.code
+}
+
+func (e *Error) FieldErrors() []*dto.FieldError {
+	return e.fieldErrors
+}
+
+// With adds a member to the problem details the error is rendered as, and returns e.
+func (e *Error) With(key string, value any) *Error {
+	if e.extensions == nil {
+		e.extensions = map[string]any{}
+	}
+
+	e.extensions[key] = value
+
+	return e
+}
+
+func (e *Error) Extensions() map[string]any {
+	return e.extensions
+}
+
+// CodeOf returns the code of err, derived from its status when it was not given one.
+func CodeOf(err errs.CustomError) Code {
+	if coded, ok := err.(*Error); ok {
+		return coded.code
+	}
+
+	if code, ok := statusCodes[err.StatusCode()]; ok {
+		return code
+	}
+
+	if err.StatusCode() >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+
+	return CodeBadRequest
+}
+
+// FieldErrorsOf returns the field errors of a validation error, nil for any other error.
+func FieldErrorsOf(err errs.CustomError) []*dto.FieldError {
+	if coded, ok := err.(*Error); ok {
+		return coded.fieldErrors
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package apperror
+
+import (
+	"errors"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"testing"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAppErrorSuite struct {
+	suite.Suite
+}
+
+func TestUnitTestAppError(t *testing.T) {
+	suite.Run(t, &unitTestAppErrorSuite{})
+}
+
+func (u *unitTestAppErrorSuite) TestCodeOf_GivenCode() {
+	err := New(http.StatusBadRequest, CodeInvalidCursor, "invalid cursor")
+
+	u.Equal(CodeInvalidCursor, CodeOf(err))
+	u.Equal(http.StatusBadRequest, err.StatusCode())
+	u.Equal("Bad Request", err.Status())
+	u.Equal("invalid cursor", err.Message())
+}
+
+func (u *unitTestAppErrorSuite) TestCodeOf_FromStatus() {
+	u.Equal(CodeNotFound, CodeOf(errs.NewNotFoundError("data not found")))
+	u.Equal(CodeInternal, CodeOf(errs.NewInternalServerError("something went wrong")))
+	u.Equal(CodeConflict, CodeOf(errs.NewConflictError("already exists")))
+}
+
+func (u *unitTestAppErrorSuite) TestFieldErrorsOf() {
+	fieldErrors := []*dto.FieldError{{Field: "title", Rule: "required", Message: "title is required"}}
+
+	err := NewValidationError(fieldErrors)
+
+	u.Equal(CodeValidationFailed, CodeOf(err))
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal(fieldErrors, FieldErrorsOf(err))
+	u.Nil(FieldErrorsOf(errs.NewBadRequestError("bad")))
+}
+
+func (u *unitTestAppErrorSuite) TestFrom() {
+	err := From(errs.NewNotFoundError("data not found"))
+
+	u.Equal(New(http.StatusNotFound, CodeNotFound, "data not found"), err)
+	u.EqualError(err, "not_found: data not found")
+
+	validationErr := NewValidationError(nil)
+	u.Same(validationErr, From(validationErr))
+}
+
+func (u *unitTestAppErrorSuite) TestWrap() {
+	cause := errors.New("pq: duplicate key")
+
+	err := Wrap(http.StatusConflict, CodeAlreadyExists, "book already exists", cause)
+
+	u.Equal(CodeAlreadyExists, CodeOf(err))
+	u.Equal("book already exists", err.Message())
+	u.EqualError(err, "already_exists: book already exists: pq: duplicate key")
+	u.ErrorIs(err, cause)
+	u.Same(err, From(err))
+}
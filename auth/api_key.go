@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// ApiKeyPrefix starts every API key, so leaked keys are easy to spot and scan for.
+const ApiKeyPrefix = "bk_"
+
+// apiKeyDisplayLength is how much of a key is kept in the clear to tell keys apart.
+const apiKeyDisplayLength = len(ApiKeyPrefix) + 8
+
+// GenerateApiKey returns a new random API key, the start of it to display and its hash to
+// store.
+func GenerateApiKey() (key string, prefix string, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = ApiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, key[:apiKeyDisplayLength], HashApiKey(key), nil
+}
+
+// HashApiKey is the hex SHA-256 of key. Keys are random enough that a salt or a slow hash
+// would add nothing, and a plain hash can be looked up.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// looksLikeApiKey weeds out values that cannot be keys before the database is asked.
+func looksLikeApiKey(key string) bool {
+	return strings.HasPrefix(key, ApiKeyPrefix) && len(key) > apiKeyDisplayLength
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/repository"
+	"net/http"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// Authenticator tells who credentials belong to. Credentials it refuses fail with 401.
+type Authenticator interface {
+	// ApiKey authenticates a key made with GenerateApiKey that has not been revoked.
+	ApiKey(ctx context.Context, key string) (*domain.Principal, errs.CustomError)
+	// BearerToken authenticates a JWT signed with a key of the configured key set.
+	BearerToken(ctx context.Context, token string) (*domain.Principal, errs.CustomError)
+}
+
+type authenticatorImpl struct {
+	ar repository.ApiKeyRepository
+	db *sql.DB
+	// keySet is nil when bearer tokens are not configured
+	keySet *KeySet
+	cfg    *config.AuthConfig
+	now    func() time.Time
+}
+
+func NewAuthenticatorImpl(ar repository.ApiKeyRepository, db *sql.DB, keySet *KeySet, cfg *config.Config) Authenticator {
+	return &authenticatorImpl{ar: ar, db: db, keySet: keySet, cfg: &cfg.Auth, now: time.Now}
+}
+
+func (a *authenticatorImpl) ApiKey(ctx context.Context, key string) (*domain.Principal, errs.CustomError) {
+	invalid := apperror.New(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "invalid API key")
+
+	if !looksLikeApiKey(key) {
+		return nil, invalid
+	}
+
+	apiKey, err := a.ar.FindByHash(ctx, a.db, HashApiKey(key))
+	if repoErr, ok := err.(*repository.Error); ok && errors.Is(repoErr, repository.ErrNotFound) {
+		return nil, invalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// revoked keys are refused like unknown ones, the client has no use for the difference
+	if apiKey.RevokedAt != nil {
+		return nil, invalid
+	}
+
+	return &domain.Principal{
+		Subject: fmt.Sprintf("api_key:%d", apiKey.Id),
+		Name:    apiKey.Name,
+		Method:  domain.AuthMethodApiKey,
+		Roles:   apiKey.Roles,
+		Tenant:  apiKey.Tenant,
+	}, nil
+}
+
+func (a *authenticatorImpl) BearerToken(ctx context.Context, token string) (*domain.Principal, errs.CustomError) {
+	if a.keySet == nil {
+		return nil, apperror.New(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "bearer tokens are not accepted")
+	}
+
+	claims, err := a.keySet.verifyToken(token)
+	if err == nil {
+		err = claims.validate(a.now(), a.cfg.ClockSkew, a.cfg.Issuer, a.cfg.Audience)
+	}
+
+	if errors.Is(err, errTokenExpired) {
+		return nil, apperror.Wrap(http.StatusUnauthorized, apperror.CodeTokenExpired, "the bearer token has expired", err)
+	}
+
+	if err != nil {
+		return nil, apperror.Wrap(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "invalid bearer token", err)
+	}
+
+	return &domain.Principal{
+		Subject: claims.Subject,
+		Name:    claims.Name,
+		Method:  domain.AuthMethodJWT,
+		Roles:   claims.Roles,
+		Tenant:  claims.Tenant,
+	}, nil
+}
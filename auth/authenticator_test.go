@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/repository"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAuthenticatorSuite struct {
+	suite.Suite
+	ctx context.Context
+	arm *mocks.ApiKeyRepository
+	cfg *config.Config
+	a   *authenticatorImpl
+	now time.Time
+}
+
+func TestUnitTestAuthenticator(t *testing.T) {
+	suite.Run(t, &unitTestAuthenticatorSuite{})
+}
+
+func (u *unitTestAuthenticatorSuite) SetupTest() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	u.Require().NoError(err)
+
+	keySet, err := ParseKeySet(testKeySet(&rsaKey.PublicKey))
+	u.Require().NoError(err)
+
+	u.arm = mocks.NewApiKeyRepository(u.T())
+	u.cfg = config.Default()
+	u.cfg.Auth.Audience = "books"
+	u.now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	u.a = NewAuthenticatorImpl(u.arm, nil, keySet, u.cfg).(*authenticatorImpl)
+	u.a.now = func() time.Time { return u.now }
+
+	u.ctx = context.Background()
+}
+
+func (u *unitTestAuthenticatorSuite) TestApiKey_Success() {
+	key, prefix, hash, err := GenerateApiKey()
+	u.Require().NoError(err)
+
+	u.True(len(key) > len(prefix))
+	u.Equal(key[:len(prefix)], prefix)
+	u.arm.On("FindByHash", u.ctx, mock.Anything, hash).Return(&domain.ApiKey{Id: 3, Name: "importer", Prefix: prefix, Hash: hash, Roles: []string{"editor"}, Tenant: "acme"}, nil)
+
+	principal, errAuth := u.a.ApiKey(u.ctx, key)
+
+	u.Nil(errAuth)
+	u.Equal(&domain.Principal{Subject: "api_key:3", Name: "importer", Method: domain.AuthMethodApiKey, Roles: []string{"editor"}, Tenant: "acme"}, principal)
+}
+
+func (u *unitTestAuthenticatorSuite) TestApiKey_Revoked() {
+	revokedAt := u.now
+	u.arm.On("FindByHash", u.ctx, mock.Anything, HashApiKey("bk_revokedkey")).Return(&domain.ApiKey{Id: 3, RevokedAt: &revokedAt}, nil)
+
+	principal, err := u.a.ApiKey(u.ctx, "bk_revokedkey")
+
+	u.Nil(principal)
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+	u.Equal(apperror.CodeInvalidCredentials, apperror.CodeOf(err))
+}
+
+func (u *unitTestAuthenticatorSuite) TestApiKey_Unknown() {
+	u.arm.On("FindByHash", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "FindApiKeyByHash", Kind: repository.ErrNotFound})
+
+	_, err := u.a.ApiKey(u.ctx, "bk_unknownkey")
+
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+}
+
+func (u *unitTestAuthenticatorSuite) TestApiKey_NotAKey() {
+	_, err := u.a.ApiKey(u.ctx, "password")
+
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+	u.arm.AssertNotCalled(u.T(), "FindByHash", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestAuthenticatorSuite) TestBearerToken_Success() {
+	token := signToken(AlgHS256, "hmac", testSecret, map[string]any{"sub": "user-1", "aud": "books", "roles": []string{"reader"}, "tenant": "acme", "exp": u.now.Add(time.Hour).Unix()})
+
+	principal, err := u.a.BearerToken(u.ctx, token)
+
+	u.Nil(err)
+	u.Equal(&domain.Principal{Subject: "user-1", Method: domain.AuthMethodJWT, Roles: []string{"reader"}, Tenant: "acme"}, principal)
+}
+
+func (u *unitTestAuthenticatorSuite) TestBearerToken_Expired() {
+	token := signToken(AlgHS256, "hmac", testSecret, map[string]any{"sub": "user-1", "aud": "books", "exp": u.now.Add(-time.Hour).Unix()})
+
+	_, err := u.a.BearerToken(u.ctx, token)
+
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+	u.Equal(apperror.CodeTokenExpired, apperror.CodeOf(err))
+}
+
+func (u *unitTestAuthenticatorSuite) TestBearerToken_WrongAudience() {
+	token := signToken(AlgHS256, "hmac", testSecret, map[string]any{"sub": "user-1", "aud": "other", "exp": u.now.Add(time.Hour).Unix()})
+
+	_, err := u.a.BearerToken(u.ctx, token)
+
+	u.Equal(apperror.CodeInvalidCredentials, apperror.CodeOf(err))
+}
+
+func (u *unitTestAuthenticatorSuite) TestBearerToken_NotConfigured() {
+	a := NewAuthenticatorImpl(u.arm, nil, nil, u.cfg)
+
+	_, err := a.BearerToken(u.ctx, "header.claims.signature")
+
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+}
@@ -0,0 +1,36 @@
+// Package authtest runs code as a principal with given roles, for tests.
+package authtest
+
+import (
+	"context"
+	"gin-go-testing/auth"
+	"gin-go-testing/model/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Principal returns an API key principal with roles, named after them.
+func Principal(roles ...string) *domain.Principal {
+	return &domain.Principal{
+		Subject: "test:" + strings.Join(roles, ","),
+		Name:    strings.Join(roles, ","),
+		Method:  domain.AuthMethodApiKey,
+		Roles:   roles,
+	}
+}
+
+// WithRoles returns a copy of ctx carrying a principal with roles, to call services with.
+func WithRoles(ctx context.Context, roles ...string) context.Context {
+	return auth.WithPrincipal(ctx, Principal(roles...))
+}
+
+// AsRoles stands in for middleware.Authenticate in front of handlers, every request runs as
+// a principal with roles.
+func AsRoles(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(WithRoles(ctx.Request.Context(), roles...))
+
+		ctx.Next()
+	}
+}
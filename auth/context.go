@@ -0,0 +1,22 @@
+// Package auth tells who a request is made on behalf of, from an API key or a JWT bearer
+// token, and carries the answer in the request context.
+package auth
+
+import (
+	"context"
+	"gin-go-testing/model/domain"
+)
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal *domain.Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx, false for anonymous requests.
+func PrincipalFrom(ctx context.Context) (*domain.Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*domain.Principal)
+
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Signing algorithms bearer tokens may use.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+const (
+	// minHMACKeyBytes is the minimum HS256 key size of RFC 7518, section 3.2
+	minHMACKeyBytes = 32
+	minRSAKeyBits   = 2048
+)
+
+// KeySet holds the keys bearer tokens are verified with, read from a JSON Web Key Set.
+type KeySet struct {
+	keys []*verificationKey
+}
+
+type verificationKey struct {
+	id  string
+	alg string
+	// secret is the key of HS256, public the key of RS256
+	secret []byte
+	public *rsa.PublicKey
+}
+
+// jsonWebKey is a key of a JWKS document, RFC 7517, with the members HS256 and RS256 use.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// K is the secret of an oct key
+	K string `json:"k"`
+	// N and E are the modulus and exponent of an RSA key
+	N string `json:"n"`
+	E string `json:"e"`
+}
+
+// LoadKeySet reads the JWKS document at path.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWKS file: %w", err)
+	}
+
+	keySet, err := ParseKeySet(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWKS file %s: %w", path, err)
+	}
+
+	return keySet, nil
+}
+
+// ParseKeySet parses a JWKS document. Keys for other uses or algorithms than signing with
+// HS256 or RS256 are skipped, a set left without keys is an error.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	keySet := &KeySet{}
+
+	for i, jwk := range document.Keys {
+		key, err := parseKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+
+		if key != nil {
+			keySet.keys = append(keySet.keys, key)
+		}
+	}
+
+	if len(keySet.keys) == 0 {
+		return nil, errors.New("no HS256 or RS256 signing key")
+	}
+
+	return keySet, nil
+}
+
+// parseKey returns nil for keys the key set has no use for.
+func parseKey(jwk *jsonWebKey) (*verificationKey, error) {
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return nil, nil
+	}
+
+	switch {
+	case jwk.Kty == "oct" && (jwk.Alg == "" || jwk.Alg == AlgHS256):
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+		if err != nil {
+			return nil, fmt.Errorf("invalid k: %w", err)
+		}
+
+		if len(secret) < minHMACKeyBytes {
+			return nil, fmt.Errorf("HS256 key must be at least %d bytes", minHMACKeyBytes)
+		}
+
+		return &verificationKey{id: jwk.Kid, alg: AlgHS256, secret: secret}, nil
+	case jwk.Kty == "RSA" && (jwk.Alg == "" || jwk.Alg == AlgRS256):
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid e: %w", err)
+		}
+
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RS256 key must be at least %d bits", minRSAKeyBits)
+		}
+
+		if public.E < 3 || public.E%2 == 0 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &verificationKey{id: jwk.Kid, alg: AlgRS256, public: public}, nil
+	}
+
+	return nil, nil
+}
+
+// find returns the key a token signed with alg was signed with. A token without a kid can
+// only be told apart when the set has a single key for alg.
+func (k *KeySet) find(kid string, alg string) (*verificationKey, error) {
+	var found *verificationKey
+
+	for _, key := range k.keys {
+		if key.alg != alg || (kid != "" && key.id != kid) {
+			continue
+		}
+
+		if found != nil {
+			return nil, errAmbiguousKey
+		}
+
+		found = key
+	}
+
+	if found == nil {
+		return nil, errUnknownKey
+	}
+
+	return found, nil
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Why a bearer token was refused, logged as the cause of the error the client is given.
+var (
+	errMalformedToken       = errors.New("malformed token")
+	errUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	errUnknownKey           = errors.New("no key matches the token")
+	errAmbiguousKey         = errors.New("the token has no kid and several keys match it")
+	errInvalidSignature     = errors.New("invalid signature")
+	errTokenExpired         = errors.New("token expired")
+	errTokenNotYetValid     = errors.New("token not valid yet")
+	errMissingClaim         = errors.New("missing claim")
+	errInvalidIssuer        = errors.New("invalid issuer")
+	errInvalidAudience      = errors.New("invalid audience")
+)
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// tokenClaims are the claims of a bearer token the server looks at.
+type tokenClaims struct {
+	Subject  string   `json:"sub"`
+	Name     string   `json:"name"`
+	Issuer   string   `json:"iss"`
+	Audience audience `json:"aud"`
+	Roles    []string `json:"roles"`
+	Tenant   string   `json:"tenant"`
+	// ExpiresAt and NotBefore are NumericDates, seconds since the epoch
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// audience is the aud claim, a single string or an array of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var several []string
+	if err := json.Unmarshal(data, &several); err != nil {
+		return err
+	}
+
+	*a = several
+
+	return nil
+}
+
+// verifyToken checks the signature of the compact JWS token with the key set and returns its
+// claims, which are not validated yet.
+func (k *KeySet) verifyToken(token string) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+
+	header := &tokenHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, err
+	}
+
+	// the algorithm comes from the token but must be one the key was made for, so a token
+	// cannot pass an RSA public key off as an HMAC secret
+	if header.Alg != AlgHS256 && header.Alg != AlgRS256 {
+		return nil, errUnsupportedAlgorithm
+	}
+
+	key, err := k.find(header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	switch key.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(signed)
+
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, errInvalidSignature
+		}
+	case AlgRS256:
+		digest := sha256.Sum256(signed)
+
+		if rsa.VerifyPKCS1v15(key.public, crypto.SHA256, digest[:], signature) != nil {
+			return nil, errInvalidSignature
+		}
+	}
+
+	claims := &tokenClaims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errMalformedToken
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return errMalformedToken
+	}
+
+	return nil
+}
+
+// validate checks the claims at now. Tokens must have a subject and an expiry, issuer and
+// audience are only checked when they are given.
+func (c *tokenClaims) validate(now time.Time, clockSkew time.Duration, issuer string, audience string) error {
+	if c.Subject == "" || c.ExpiresAt == nil {
+		return errMissingClaim
+	}
+
+	if !now.Add(-clockSkew).Before(numericDate(*c.ExpiresAt)) {
+		return errTokenExpired
+	}
+
+	if c.NotBefore != nil && now.Add(clockSkew).Before(numericDate(*c.NotBefore)) {
+		return errTokenNotYetValid
+	}
+
+	if issuer != "" && c.Issuer != issuer {
+		return errInvalidIssuer
+	}
+
+	if audience != "" && !slices.Contains(c.Audience, audience) {
+		return errInvalidAudience
+	}
+
+	return nil
+}
+
+func numericDate(seconds float64) time.Time {
+	whole := math.Floor(seconds)
+
+	return time.Unix(int64(whole), int64((seconds-whole)*float64(time.Second)))
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// testSecret is the HS256 key of the test key set, kid "hmac".
+var testSecret = []byte(strings.Repeat("s", 32))
+
+type unitTestJWTSuite struct {
+	suite.Suite
+	rsaKey *rsa.PrivateKey
+	keySet *KeySet
+	now    time.Time
+}
+
+func TestUnitTestJWT(t *testing.T) {
+	suite.Run(t, &unitTestJWTSuite{})
+}
+
+func (u *unitTestJWTSuite) SetupSuite() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	u.Require().NoError(err)
+
+	u.rsaKey = rsaKey
+}
+
+func (u *unitTestJWTSuite) SetupTest() {
+	keySet, err := ParseKeySet(testKeySet(&u.rsaKey.PublicKey))
+	u.Require().NoError(err)
+
+	u.keySet = keySet
+	u.now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+}
+
+// testKeySet is a JWKS document with an HS256 key, an RS256 key and an encryption key that
+// is skipped.
+func testKeySet(public *rsa.PublicKey) []byte {
+	return []byte(fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": %q},
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(testSecret),
+		base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	))
+}
+
+// signToken signs claims with key, a secret for HS256 or an *rsa.PrivateKey for RS256.
+func signToken(alg string, kid string, key any, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (u *unitTestJWTSuite) claims() map[string]any {
+	return map[string]any{"sub": "user-1", "name": "Ada", "exp": u.now.Add(time.Hour).Unix()}
+}
+
+func (u *unitTestJWTSuite) TestVerify_HS256() {
+	claims, err := u.keySet.verifyToken(signToken(AlgHS256, "hmac", testSecret, u.claims()))
+
+	u.NoError(err)
+	u.Equal("user-1", claims.Subject)
+	u.Equal("Ada", claims.Name)
+	u.NoError(claims.validate(u.now, 0, "", ""))
+}
+
+func (u *unitTestJWTSuite) TestVerify_RS256WithoutKid() {
+	// the set has a single RS256 signing key, so the token does not need to name it
+	claims, err := u.keySet.verifyToken(signToken(AlgRS256, "", u.rsaKey, u.claims()))
+
+	u.NoError(err)
+	u.Equal("user-1", claims.Subject)
+}
+
+func (u *unitTestJWTSuite) TestVerify_Refused() {
+	public := &u.rsaKey.PublicKey
+	otherSecret := []byte(strings.Repeat("o", 32))
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"malformed", "not-a-token", errMalformedToken},
+		{"none", signToken("none", "hmac", testSecret, u.claims()), errUnsupportedAlgorithm},
+		{"unknown kid", signToken(AlgHS256, "other", testSecret, u.claims()), errUnknownKey},
+		{"wrong secret", signToken(AlgHS256, "hmac", otherSecret, u.claims()), errInvalidSignature},
+		// an HS256 token signed with the public RSA key must not verify against it
+		{"algorithm confusion", signToken(AlgHS256, "rsa", public.N.Bytes(), u.claims()), errUnknownKey},
+		{"tampered", signToken(AlgHS256, "hmac", testSecret, u.claims()) + "x", errInvalidSignature},
+	}
+
+	for _, test := range tests {
+		_, err := u.keySet.verifyToken(test.token)
+
+		u.ErrorIs(err, test.err, test.name)
+	}
+}
+
+func (u *unitTestJWTSuite) TestValidate() {
+	exp := float64(u.now.Add(-30 * time.Second).Unix())
+	nbf := float64(u.now.Add(time.Hour).Unix())
+	future := float64(u.now.Add(time.Hour).Unix())
+
+	tests := []struct {
+		name   string
+		claims tokenClaims
+		err    error
+	}{
+		{"valid", tokenClaims{Subject: "user-1", ExpiresAt: &future, Issuer: "issuer", Audience: audience{"other", "books"}}, nil},
+		{"expired", tokenClaims{Subject: "user-1", ExpiresAt: &exp}, errTokenExpired},
+		{"not yet valid", tokenClaims{Subject: "user-1", ExpiresAt: &future, NotBefore: &nbf}, errTokenNotYetValid},
+		{"no expiry", tokenClaims{Subject: "user-1"}, errMissingClaim},
+		{"no subject", tokenClaims{ExpiresAt: &future}, errMissingClaim},
+		{"issuer", tokenClaims{Subject: "user-1", ExpiresAt: &future, Issuer: "someone-else", Audience: audience{"books"}}, errInvalidIssuer},
+		{"audience", tokenClaims{Subject: "user-1", ExpiresAt: &future, Issuer: "issuer", Audience: audience{"other"}}, errInvalidAudience},
+	}
+
+	for _, test := range tests {
+		err := test.claims.validate(u.now, 0, "issuer", "books")
+
+		u.ErrorIs(err, test.err, test.name)
+	}
+
+	// within the clock skew the expired token is still accepted
+	u.NoError((&tokenClaims{Subject: "user-1", ExpiresAt: &exp}).validate(u.now, time.Minute, "", ""))
+}
+
+func (u *unitTestJWTSuite) TestAudience_StringOrArray() {
+	var claims tokenClaims
+
+	u.NoError(json.Unmarshal([]byte(`{"aud": "books"}`), &claims))
+	u.Equal(audience{"books"}, claims.Audience)
+
+	u.NoError(json.Unmarshal([]byte(`{"aud": ["books", "other"]}`), &claims))
+	u.Equal(audience{"books", "other"}, claims.Audience)
+}
+
+func (u *unitTestJWTSuite) TestParseKeySet_Invalid() {
+	tests := map[string]string{
+		"short secret":  `{"keys": [{"kty": "oct", "k": "c2hvcnQ"}]}`,
+		"small modulus": `{"keys": [{"kty": "RSA", "n": "` + base64.RawURLEncoding.EncodeToString(make([]byte, 64)) + `", "e": "AQAB"}]}`,
+		"no usable key": `{"keys": [{"kty": "EC", "crv": "P-256"}]}`,
+		"not json":      `keys`,
+	}
+
+	for name, document := range tests {
+		_, err := ParseKeySet([]byte(document))
+
+		u.Error(err, name)
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"net/http"
+	"slices"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// Permission is an operation roles can be allowed to do.
+type Permission string
+
+const (
+	PermissionReadBooks   Permission = "books:read"
+	PermissionWriteBooks  Permission = "books:write"
+	PermissionDeleteBooks Permission = "books:delete"
+)
+
+// permissions are the permissions roles can be granted.
+var permissions = []Permission{PermissionReadBooks, PermissionWriteBooks, PermissionDeleteBooks}
+
+// Policy decides what the principal of a request may do. Services consult it before each
+// operation, so the rules hold whichever way the service is called.
+type Policy interface {
+	// Authorize fails with 403 when the principal carried by ctx lacks permission, and with
+	// 401 when the request is anonymous and anonymous requests lack it.
+	Authorize(ctx context.Context, permission Permission) errs.CustomError
+}
+
+type policyImpl struct {
+	// grants are the permissions of each role
+	grants        map[string][]Permission
+	anonymousRole string
+}
+
+// NewPolicyImpl creates the policy granting the permissions of the configured roles. It
+// fails on permissions it does not know, which would otherwise never be granted.
+func NewPolicyImpl(cfg *config.Config) (Policy, error) {
+	grants := make(map[string][]Permission, len(cfg.Auth.Roles))
+
+	for role, granted := range cfg.Auth.Roles {
+		for _, name := range granted {
+			permission := Permission(name)
+
+			if !slices.Contains(permissions, permission) {
+				return nil, fmt.Errorf("role %s: unknown permission %q", role, name)
+			}
+
+			grants[role] = append(grants[role], permission)
+		}
+	}
+
+	return &policyImpl{grants: grants, anonymousRole: cfg.Auth.AnonymousRole}, nil
+}
+
+func (p *policyImpl) Authorize(ctx context.Context, permission Permission) errs.CustomError {
+	principal, ok := PrincipalFrom(ctx)
+
+	if !ok {
+		if p.allows([]string{p.anonymousRole}, permission) {
+			return nil
+		}
+
+		return apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "authentication required, send an API key or a bearer token").
+			With("permission", permission)
+	}
+
+	if p.allows(principal.Roles, permission) {
+		return nil
+	}
+
+	return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "you are not allowed to do this").
+		With("permission", permission)
+}
+
+func (p *policyImpl) allows(roles []string, permission Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(p.grants[role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestPolicySuite struct {
+	suite.Suite
+	cfg *config.Config
+}
+
+func TestUnitTestPolicy(t *testing.T) {
+	suite.Run(t, &unitTestPolicySuite{})
+}
+
+func (u *unitTestPolicySuite) SetupTest() {
+	u.cfg = config.Default()
+}
+
+func (u *unitTestPolicySuite) policy() Policy {
+	p, err := NewPolicyImpl(u.cfg)
+	u.Require().NoError(err)
+
+	return p
+}
+
+func (u *unitTestPolicySuite) as(roles ...string) context.Context {
+	return WithPrincipal(context.Background(), &domain.Principal{Subject: "user-1", Roles: roles})
+}
+
+func (u *unitTestPolicySuite) TestAuthorize_RolesAddUp() {
+	u.cfg.Auth.Roles = map[string][]string{"reader": {"books:read"}, "remover": {"books:delete"}}
+	p := u.policy()
+
+	u.Nil(p.Authorize(u.as("reader", "remover"), PermissionReadBooks))
+	u.Nil(p.Authorize(u.as("reader", "remover"), PermissionDeleteBooks))
+
+	err := p.Authorize(u.as("reader", "remover"), PermissionWriteBooks)
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.Equal(apperror.CodeForbidden, apperror.CodeOf(err))
+}
+
+func (u *unitTestPolicySuite) TestAuthorize_NoRoles() {
+	err := u.policy().Authorize(u.as(), PermissionReadBooks)
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+}
+
+func (u *unitTestPolicySuite) TestAuthorize_Anonymous() {
+	u.Nil(u.policy().Authorize(context.Background(), PermissionReadBooks))
+
+	u.cfg.Auth.AnonymousRole = ""
+
+	err := u.policy().Authorize(context.Background(), PermissionReadBooks)
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+	u.Equal(apperror.CodeUnauthorized, apperror.CodeOf(err))
+}
+
+func (u *unitTestPolicySuite) TestNewPolicyImpl_UnknownPermission() {
+	u.cfg.Auth.Roles["editor"] = []string{"books:read", "books:publish"}
+
+	_, err := NewPolicyImpl(u.cfg)
+
+	u.ErrorContains(err, `unknown permission "books:publish"`)
+}
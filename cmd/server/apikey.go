@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/repository"
+	"gin-go-testing/tenant"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func apikey(args []string) error {
+	cfg, args, err := config.Load("apikey", args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing apikey action\n\n%s", usage)
+	}
+
+	if cfg.Database.Driver == "memory" {
+		return fmt.Errorf("the memory driver keeps no API keys between runs")
+	}
+
+	db, err := openDB(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	apiKeyRepository, err := repository.NewApiKeyRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch action := args[0]; {
+	case action == "create" && len(args) >= 3:
+		name := strings.TrimSpace(args[1])
+		if name == "" || len(name) > 100 {
+			return fmt.Errorf("the name must be 1 to 100 characters long")
+		}
+
+		tenantId := args[2]
+		if !tenant.Valid(tenantId) {
+			return fmt.Errorf("%q is not a valid tenant id, use %s when tenancy is off", tenantId, tenant.Default)
+		}
+
+		roles := args[3:]
+		for _, role := range roles {
+			if _, ok := cfg.Auth.Roles[role]; !ok {
+				return fmt.Errorf("unknown role %q, the roles are configured with auth.roles", role)
+			}
+		}
+
+		key, prefix, hash, err := auth.GenerateApiKey()
+		if err != nil {
+			return fmt.Errorf("failed to generate API key: %w", err)
+		}
+
+		created, errCreate := apiKeyRepository.Create(ctx, db, &domain.ApiKey{Name: name, Prefix: prefix, Hash: hash, Roles: roles, Tenant: tenantId, CreatedAt: time.Now().UTC()})
+		if errCreate != nil {
+			return fmt.Errorf("failed to create API key: %v", errCreate)
+		}
+
+		fmt.Printf("created API key %d, it is not shown again:\n%s\n", created.Id, key)
+
+		return nil
+	case action == "revoke" && len(args) == 2:
+		keyId, err := strconv.ParseUint(args[1], 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid API key id %q", args[1])
+		}
+
+		errRevoke := apiKeyRepository.Revoke(ctx, db, uint(keyId), time.Now().UTC())
+		if repoErr, ok := errRevoke.(*repository.Error); ok && errors.Is(repoErr, repository.ErrNotFound) {
+			return fmt.Errorf("there is no API key %d, or it is revoked already", keyId)
+		}
+
+		if errRevoke != nil {
+			return fmt.Errorf("failed to revoke API key %d: %v", keyId, errRevoke)
+		}
+
+		return nil
+	case action == "list" && len(args) == 1:
+		keys, errFind := apiKeyRepository.FindAll(ctx, db)
+		if errFind != nil {
+			return fmt.Errorf("failed to list API keys: %v", errFind)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "ID\tNAME\tPREFIX\tROLES\tTENANT\tCREATED AT\tREVOKED AT")
+
+		for _, key := range keys {
+			revokedAt := "-"
+			if key.RevokedAt != nil {
+				revokedAt = key.RevokedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.Id, key.Name, key.Prefix, strings.Join(key.Roles, ","), key.Tenant, key.CreatedAt.Format(time.RFC3339), revokedAt)
+		}
+
+		return writer.Flush()
+	default:
+		return fmt.Errorf("invalid apikey action %q\n\n%s", args, usage)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const usage = `Usage:
+  server [serve] [flags]                 run the HTTP server
+  server migrate [flags] up              apply every pending migration
+  server migrate [flags] down            revert the latest migration
+  server migrate [flags] to VERSION      migrate up or down to VERSION
+  server migrate [flags] status          list migrations and when they were applied
+  server apikey [flags] create NAME TENANT [ROLE...]
+                                         create an API key acting for TENANT with roles,
+                                         printed once
+  server apikey [flags] revoke ID        revoke an API key
+  server apikey [flags] list             list API keys without the keys themselves
+
+Run "server serve -h" for the flags.
+`
+
+func main() {
+	args := os.Args[1:]
+	command := "serve"
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+
+	switch command {
+	case "serve":
+		err = serve(args)
+	case "migrate":
+		err = migrate(args)
+	case "apikey":
+		err = apikey(args)
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[Server] %s\n", err.Error())
+		os.Exit(1)
+	}
+}
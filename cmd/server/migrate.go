@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/config"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func migrate(args []string) error {
+	cfg, args, err := config.Load("migrate", args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate action\n\n%s", usage)
+	}
+
+	if cfg.Database.Driver == "memory" {
+		return fmt.Errorf("the memory driver has no migrations")
+	}
+
+	db, err := openDB(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := newMigrator(db, cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch action := args[0]; {
+	case action == "up" && len(args) == 1:
+		return migrator.Up(ctx)
+	case action == "down" && len(args) == 1:
+		return migrator.Down(ctx)
+	case action == "to" && len(args) == 2:
+		version, err := strconv.ParseUint(args[1], 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+
+		return migrator.To(ctx, uint(version))
+	case action == "status" && len(args) == 1:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(writer, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return writer.Flush()
+	default:
+		return fmt.Errorf("invalid migrate action %q\n\n%s", args, usage)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/handler"
+	"gin-go-testing/logger"
+	"gin-go-testing/migration"
+	"gin-go-testing/ratelimit"
+	"gin-go-testing/repository"
+	"gin-go-testing/routes"
+	"gin-go-testing/service"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func serve(args []string) error {
+	cfg, _, err := config.Load("serve", args)
+	if err != nil {
+		return err
+	}
+
+	appLogger := logger.New(&cfg.Log, os.Stdout)
+	slog.SetDefault(appLogger)
+
+	if cfg.Pagination.CursorSecret == "" {
+		appLogger.Warn("CURSOR_SECRET is not set, using a random secret")
+
+		cfg.Pagination.CursorSecret, err = randomSecret()
+		if err != nil {
+			return fmt.Errorf("failed to generate cursor secret: %w", err)
+		}
+	}
+
+	gin.SetMode(cfg.App.GinMode)
+
+	bookRepository, err := repository.NewBookRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	authorRepository, err := repository.NewAuthorRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	tagRepository, err := repository.NewTagRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	bookSearcher, err := repository.NewBookSearcher(cfg)
+	if err != nil {
+		return err
+	}
+
+	// the in-memory repository does not use a database
+	var db *sql.DB
+	txManager := repository.NewMemoryTxManager()
+
+	if cfg.Database.Driver != "memory" {
+		db, err = openDB(&cfg.Database)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		if cfg.Database.AutoMigrate {
+			migrator, err := newMigrator(db, cfg.Database.Driver)
+			if err != nil {
+				return err
+			}
+
+			if err := migrator.Up(context.Background()); err != nil {
+				return fmt.Errorf("failed to migrate database: %w", err)
+			}
+		}
+
+		txManager = repository.NewTxManager(db, cfg)
+	}
+
+	// the in-memory index of SQLite starts empty, Postgres indexes books itself
+	if err := bookSearcher.Rebuild(context.Background(), db, bookRepository); err != nil {
+		return fmt.Errorf("failed to build search index: %s", err.Message())
+	}
+
+	apiKeyRepository, err := repository.NewApiKeyRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	// bearer tokens are only accepted once a key set is configured
+	var keySet *auth.KeySet
+
+	if cfg.Auth.JWKSFile != "" {
+		keySet, err = auth.LoadKeySet(cfg.Auth.JWKSFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.Auth.AllowAnonymous {
+		appLogger.Warn("anonymous requests are allowed")
+	}
+
+	authenticator := auth.NewAuthenticatorImpl(apiKeyRepository, db, keySet, cfg)
+
+	policy, err := auth.NewPolicyImpl(cfg)
+	if err != nil {
+		return err
+	}
+
+	bookService := service.NewBookServiceImpl(bookRepository, authorRepository, tagRepository, bookSearcher, db, txManager, policy, cfg)
+	bookHandler := handler.NewBookHandlerImpl(bookService, cfg)
+	authorService := service.NewAuthorServiceImpl(authorRepository, bookRepository, bookSearcher, db, txManager, policy, cfg)
+	authorHandler := handler.NewAuthorHandlerImpl(authorService, cfg)
+	tagService := service.NewTagServiceImpl(tagRepository, bookRepository, db, txManager, policy, cfg)
+	tagHandler := handler.NewTagHandlerImpl(tagService, cfg)
+
+	server := &http.Server{
+		Addr:         cfg.App.Addr,
+		Handler:      routes.NewRouter(bookHandler, authorHandler, tagHandler, authenticator, ratelimit.NewMemoryStoreImpl(), cfg, appLogger),
+		ReadTimeout:  cfg.App.ReadTimeout,
+		WriteTimeout: cfg.App.WriteTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		appLogger.Info("listening", "addr", server.Addr)
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	appLogger.Info("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("forced to shutdown: %w", err)
+	}
+
+	return nil
+}
+
+// sqlDrivers maps the configured driver to the name its database/sql driver registers.
+var sqlDrivers = map[string]string{
+	"postgres": "postgres",
+	"sqlite":   "sqlite3",
+}
+
+func openDB(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	driverName, ok := sqlDrivers[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("driver %q does not use a database", cfg.Driver)
+	}
+
+	db, err := sql.Open(driverName, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func newMigrator(db *sql.DB, driver string) (*migration.Migrator, error) {
+	source, err := migration.Source(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return migration.New(db, driver, source)
+}
+
+// randomSecret is used when no cursor secret is configured, so cursors do not
+// survive a restart and are not shared between instances.
+func randomSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
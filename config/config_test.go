@@ -0,0 +1,217 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestConfigSuite struct {
+	suite.Suite
+	file string
+}
+
+func TestUnitTestConfig(t *testing.T) {
+	suite.Run(t, &unitTestConfigSuite{})
+}
+
+func (u *unitTestConfigSuite) SetupTest() {
+	u.file = filepath.Join(u.T().TempDir(), "config.yaml")
+
+	content := `
+app:
+  addr: ":9000"
+  gin_mode: debug
+database:
+  dsn: postgres://file
+  query_timeout: 2s
+log:
+  level: warn
+`
+	u.Require().NoError(os.WriteFile(u.file, []byte(content), 0o600))
+}
+
+func (u *unitTestConfigSuite) TestLoad_Defaults() {
+	cfg, _, err := Load("server", nil)
+
+	u.NoError(err)
+	u.Equal(Default(), cfg)
+}
+
+func (u *unitTestConfigSuite) TestLoad_File() {
+	cfg, _, err := Load("server", []string{"-config", u.file})
+
+	u.NoError(err)
+	u.Equal(":9000", cfg.App.Addr)
+	u.Equal("debug", cfg.App.GinMode)
+	u.Equal("postgres://file", cfg.Database.DSN)
+	u.Equal(2*time.Second, cfg.Database.QueryTimeout)
+	u.Equal("warn", cfg.Log.Level)
+
+	// settings missing from the file keep their defaults
+	u.Equal(Default().App.ReadTimeout, cfg.App.ReadTimeout)
+}
+
+func (u *unitTestConfigSuite) TestLoad_EnvOverridesFile() {
+	u.T().Setenv("CONFIG_FILE", u.file)
+	u.T().Setenv("DATABASE_URL", "postgres://env")
+	u.T().Setenv("DATABASE_QUERY_TIMEOUT", "3s")
+
+	cfg, _, err := Load("server", nil)
+
+	u.NoError(err)
+	u.Equal(":9000", cfg.App.Addr)
+	u.Equal("postgres://env", cfg.Database.DSN)
+	u.Equal(3*time.Second, cfg.Database.QueryTimeout)
+}
+
+func (u *unitTestConfigSuite) TestLoad_FlagsOverrideEnv() {
+	u.T().Setenv("DATABASE_URL", "postgres://env")
+	u.T().Setenv("LOG_LEVEL", "error")
+
+	cfg, _, err := Load("server", []string{"-config", u.file, "-database-url", "postgres://flag", "-max-page-size", "50"})
+
+	u.NoError(err)
+	u.Equal("postgres://flag", cfg.Database.DSN)
+	u.Equal("error", cfg.Log.Level)
+	u.Equal(uint(50), cfg.Pagination.MaxPageSize)
+}
+
+func (u *unitTestConfigSuite) TestLoad_RemainingArgs() {
+	cfg, args, err := Load("migrate", []string{"-database-auto-migrate=false", "to", "3"})
+
+	u.NoError(err)
+	u.False(cfg.Database.AutoMigrate)
+	u.Equal([]string{"to", "3"}, args)
+}
+
+func (u *unitTestConfigSuite) TestLoad_Roles() {
+	u.T().Setenv("AUTH_ROLES", "viewer=books:read; curator=books:read,books:write")
+
+	cfg, _, err := Load("server", []string{"-auth-anonymous-role", "viewer"})
+
+	u.NoError(err)
+	u.Equal(map[string][]string{"viewer": {"books:read"}, "curator": {"books:read", "books:write"}}, cfg.Auth.Roles)
+
+	// the default anonymous role is gone with the default roles
+	u.T().Setenv("AUTH_ROLES", "viewer=books:read")
+
+	_, _, err = Load("server", nil)
+
+	u.ErrorContains(err, "auth.anonymous_role")
+}
+
+func (u *unitTestConfigSuite) TestLoad_FileRolesReplaceDefaults() {
+	content := `
+auth:
+  anonymous_role: viewer
+  roles:
+    viewer: [books:read]
+`
+	u.Require().NoError(os.WriteFile(u.file, []byte(content), 0o600))
+
+	cfg, _, err := Load("server", []string{"-config", u.file})
+
+	u.NoError(err)
+	u.Equal(map[string][]string{"viewer": {"books:read"}}, cfg.Auth.Roles)
+
+	// a file without roles keeps the default ones
+	u.Require().NoError(os.WriteFile(u.file, []byte("log:\n  level: warn\n"), 0o600))
+
+	cfg, _, err = Load("server", []string{"-config", u.file})
+
+	u.NoError(err)
+	u.Equal(Default().Auth.Roles, cfg.Auth.Roles)
+}
+
+func (u *unitTestConfigSuite) TestLoad_Tenancy() {
+	cfg, _, err := Load("server", []string{"-tenancy-source", "subdomain", "-tenancy-domain", "books.example.com"})
+
+	u.NoError(err)
+	u.Equal(TenancyConfig{Source: "subdomain", Header: "X-Tenant-Id", Domain: "books.example.com"}, cfg.Tenancy)
+
+	_, _, err = Load("server", []string{"-tenancy-source", "subdomain"})
+
+	u.ErrorContains(err, "tenancy.domain")
+}
+
+func (u *unitTestConfigSuite) TestLoad_RateLimit() {
+	u.T().Setenv("RATE_LIMIT_ROUTES", "GET /books=60/1m; POST /books=0")
+	u.T().Setenv("APP_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+
+	cfg, _, err := Load("server", []string{"-rate-limit-default", "100/30s"})
+
+	u.NoError(err)
+	u.Equal(RateLimitConfig{
+		Default: RateLimit{Requests: 100, Period: 30 * time.Second},
+		Routes: map[string]RateLimit{
+			"GET /books":  {Requests: 60, Period: time.Minute},
+			"POST /books": {},
+		},
+	}, cfg.RateLimit)
+	u.Equal([]string{"10.0.0.0/8", "192.168.1.1"}, cfg.App.TrustedProxies)
+
+	u.T().Setenv("RATE_LIMIT_ROUTES", "/books=60/1m")
+	_, _, err = Load("server", nil)
+
+	u.ErrorContains(err, "rate_limit.routes")
+
+	u.T().Setenv("RATE_LIMIT_ROUTES", "GET /books=60")
+	_, _, err = Load("server", nil)
+
+	u.ErrorContains(err, "RATE_LIMIT_ROUTES")
+}
+
+func (u *unitTestConfigSuite) TestLoad_InvalidEnv() {
+	u.T().Setenv("APP_READ_TIMEOUT", "soon")
+
+	cfg, _, err := Load("server", nil)
+
+	u.Nil(cfg)
+	u.ErrorContains(err, "APP_READ_TIMEOUT")
+}
+
+func (u *unitTestConfigSuite) TestLoad_UnknownFileField() {
+	u.Require().NoError(os.WriteFile(u.file, []byte("app:\n  adress: \":9000\"\n"), 0o600))
+
+	cfg, _, err := Load("server", []string{"-config", u.file})
+
+	u.Nil(cfg)
+	u.Error(err)
+}
+
+func (u *unitTestConfigSuite) TestValidate_ReportsEveryError() {
+	cfg := Default()
+	cfg.App.GinMode = "production"
+	cfg.Database.DSN = ""
+	cfg.Database.TxIsolation = "snapshot"
+	cfg.Pagination.DefaultPageSize = 500
+	cfg.Log.Format = "xml"
+	cfg.Books.UniqueBy = "title"
+	cfg.Pagination.CursorSecret = "change-me"
+
+	err := cfg.Validate()
+
+	u.ErrorContains(err, "app.gin_mode")
+	u.ErrorContains(err, "database.dsn")
+	u.ErrorContains(err, "database.tx_isolation")
+	u.ErrorContains(err, "pagination.default_page_size")
+	u.ErrorContains(err, "log.format")
+	u.ErrorContains(err, "books.unique_by")
+	u.ErrorContains(err, "pagination.cursor_secret")
+}
+
+func (u *unitTestConfigSuite) TestValidate_Driver() {
+	cfg := Default()
+	cfg.Database.Driver = "mysql"
+
+	u.ErrorContains(cfg.Validate(), "database.driver")
+
+	cfg.Database.Driver = "memory"
+	cfg.Database.DSN = ""
+
+	u.NoError(cfg.Validate())
+}
@@ -0,0 +1,380 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setting binds one config field to its environment variable and command-line flag.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	value flag.Value
+}
+
+func settings(c *Config) []setting {
+	return []setting{
+		{"APP_ADDR", "addr", "address the HTTP server listens on", (*stringValue)(&c.App.Addr)},
+		{"GIN_MODE", "gin-mode", "gin mode: debug, release or test", (*stringValue)(&c.App.GinMode)},
+		{"APP_READ_TIMEOUT", "read-timeout", "maximum duration for reading a request", (*durationValue)(&c.App.ReadTimeout)},
+		{"APP_WRITE_TIMEOUT", "write-timeout", "maximum duration for writing a response", (*durationValue)(&c.App.WriteTimeout)},
+		{"APP_SHUTDOWN_TIMEOUT", "shutdown-timeout", "grace period for in-flight requests on shutdown", (*durationValue)(&c.App.ShutdownTimeout)},
+		{"APP_MAX_BODY_BYTES", "max-body-bytes", "maximum size of a request body in bytes", (*int64Value)(&c.App.MaxBodyBytes)},
+		{"APP_TRUSTED_PROXIES", "trusted-proxies", "comma separated addresses or CIDR ranges of the proxies whose X-Forwarded-For is believed", (*listValue)(&c.App.TrustedProxies)},
+		{"DATABASE_DRIVER", "database-driver", "storage backend: postgres, sqlite or memory", (*stringValue)(&c.Database.Driver)},
+		{"DATABASE_URL", "database-url", "database connection string", (*stringValue)(&c.Database.DSN)},
+		{"DATABASE_MAX_OPEN_CONNS", "database-max-open-conns", "maximum number of open database connections", (*intValue)(&c.Database.MaxOpenConns)},
+		{"DATABASE_MAX_IDLE_CONNS", "database-max-idle-conns", "maximum number of idle database connections", (*intValue)(&c.Database.MaxIdleConns)},
+		{"DATABASE_CONN_MAX_LIFETIME", "database-conn-max-lifetime", "maximum lifetime of a database connection", (*durationValue)(&c.Database.ConnMaxLifetime)},
+		{"DATABASE_AUTO_MIGRATE", "database-auto-migrate", "apply pending migrations when the server starts", (*boolValue)(&c.Database.AutoMigrate)},
+		{"DATABASE_QUERY_TIMEOUT", "database-query-timeout", "timeout of a single database query, 0 disables it", (*durationValue)(&c.Database.QueryTimeout)},
+		{"DATABASE_TX_ISOLATION", "database-tx-isolation", "isolation level of transactions: default, read_uncommitted, read_committed, repeatable_read or serializable", (*stringValue)(&c.Database.TxIsolation)},
+		{"BOOKS_UNIQUE_BY", "books-unique-by", "what makes two books duplicates: title_author, isbn or none", (*stringValue)(&c.Books.UniqueBy)},
+		{"LOG_LEVEL", "log-level", "log level: debug, info, warn or error", (*stringValue)(&c.Log.Level)},
+		{"LOG_FORMAT", "log-format", "log format: json or text", (*stringValue)(&c.Log.Format)},
+		{"PAGINATION_DEFAULT_PAGE_SIZE", "default-page-size", "page size used when a listing does not ask for one", (*uintValue)(&c.Pagination.DefaultPageSize)},
+		{"PAGINATION_MAX_PAGE_SIZE", "max-page-size", "largest page size a listing may ask for", (*uintValue)(&c.Pagination.MaxPageSize)},
+		{"CURSOR_SECRET", "cursor-secret", "secret signing the pagination cursors", (*stringValue)(&c.Pagination.CursorSecret)},
+		{"AUTH_ALLOW_ANONYMOUS", "auth-allow-anonymous", "let requests without credentials through", (*boolValue)(&c.Auth.AllowAnonymous)},
+		{"AUTH_JWKS_FILE", "auth-jwks-file", "JSON Web Key Set file bearer tokens are verified with, empty disables them", (*stringValue)(&c.Auth.JWKSFile)},
+		{"AUTH_ISSUER", "auth-issuer", "issuer bearer tokens must carry in their iss claim", (*stringValue)(&c.Auth.Issuer)},
+		{"AUTH_AUDIENCE", "auth-audience", "audience bearer tokens must carry in their aud claim", (*stringValue)(&c.Auth.Audience)},
+		{"AUTH_CLOCK_SKEW", "auth-clock-skew", "how far the exp and nbf claims of bearer tokens may be off", (*durationValue)(&c.Auth.ClockSkew)},
+		{"AUTH_ROLES", "auth-roles", "permissions of each role, e.g. reader=books:read;editor=books:read,books:write", (*rolesValue)(&c.Auth.Roles)},
+		{"AUTH_ANONYMOUS_ROLE", "auth-anonymous-role", "role of requests without credentials, empty grants them nothing", (*stringValue)(&c.Auth.AnonymousRole)},
+		{"TENANCY_SOURCE", "tenancy-source", "where the tenant of a request comes from: header, subdomain or claim, empty disables tenancy", (*stringValue)(&c.Tenancy.Source)},
+		{"TENANCY_HEADER", "tenancy-header", "header carrying the tenant id when the source is header", (*stringValue)(&c.Tenancy.Header)},
+		{"TENANCY_DOMAIN", "tenancy-domain", "domain tenants are subdomains of when the source is subdomain", (*stringValue)(&c.Tenancy.Domain)},
+		{"RATE_LIMIT_DEFAULT", "rate-limit-default", "requests a client may make per period on the routes without a limit of their own, e.g. 600/1m, 0 disables it", (*rateLimitValue)(&c.RateLimit.Default)},
+		{"RATE_LIMIT_ROUTES", "rate-limit-routes", "limits of single routes, e.g. GET /books=60/1m;POST /books=10/1m", (*routeLimitsValue)(&c.RateLimit.Routes)},
+	}
+}
+
+// Load builds the config from, in increasing order of precedence, the defaults, the
+// YAML file given by -config or CONFIG_FILE, environment variables and the flags in args.
+// The arguments left after the flags are returned for the command to use.
+func Load(name string, args []string) (*Config, []string, error) {
+	flagConfig := Default()
+	flagSettings := settings(flagConfig)
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+
+	for _, s := range flagSettings {
+		fs.Var(s.value, s.flag, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := Default()
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cfgSettings := settings(cfg)
+
+	for _, s := range cfgSettings {
+		if value, ok := os.LookupEnv(s.env); ok {
+			if err := s.value.Set(value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	// only the flags given on the command line override the other sources
+	var errFlag error
+	fs.Visit(func(f *flag.Flag) {
+		for i, s := range flagSettings {
+			if s.flag == f.Name && errFlag == nil {
+				errFlag = cfgSettings[i].value.Set(s.value.String())
+			}
+		}
+	})
+
+	if errFlag != nil {
+		return nil, nil, errFlag
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	// the decoder merges into maps, the roles of the file replace the default ones like
+	// those of AUTH_ROLES do
+	defaultRoles := cfg.Auth.Roles
+	cfg.Auth.Roles = nil
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	if cfg.Auth.Roles == nil {
+		cfg.Auth.Roles = defaultRoles
+	}
+
+	return nil
+}
+
+type stringValue string
+
+func (s *stringValue) Set(value string) error {
+	*s = stringValue(value)
+	return nil
+}
+
+func (s *stringValue) String() string {
+	return string(*s)
+}
+
+type durationValue time.Duration
+
+func (d *durationValue) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = durationValue(parsed)
+	return nil
+}
+
+func (d *durationValue) String() string {
+	return time.Duration(*d).String()
+}
+
+type intValue int
+
+func (i *intValue) Set(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	*i = intValue(parsed)
+	return nil
+}
+
+func (i *intValue) String() string {
+	return strconv.Itoa(int(*i))
+}
+
+type int64Value int64
+
+func (i *int64Value) Set(value string) error {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*i = int64Value(parsed)
+	return nil
+}
+
+func (i *int64Value) String() string {
+	return strconv.FormatInt(int64(*i), 10)
+}
+
+type uintValue uint
+
+func (u *uintValue) Set(value string) error {
+	parsed, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return err
+	}
+
+	*u = uintValue(parsed)
+	return nil
+}
+
+func (u *uintValue) String() string {
+	return strconv.FormatUint(uint64(*u), 10)
+}
+
+type boolValue bool
+
+func (b *boolValue) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+
+	*b = boolValue(parsed)
+	return nil
+}
+
+func (b *boolValue) String() string {
+	return strconv.FormatBool(bool(*b))
+}
+
+// IsBoolFlag lets the flag be given without a value, as in -database-auto-migrate.
+func (b *boolValue) IsBoolFlag() bool {
+	return true
+}
+
+// rolesValue is a role mapping written role=permission,permission;role=permission.
+type rolesValue map[string][]string
+
+func (r *rolesValue) Set(value string) error {
+	roles := rolesValue{}
+
+	for _, grant := range strings.Split(value, ";") {
+		if strings.TrimSpace(grant) == "" {
+			continue
+		}
+
+		role, permissions, ok := strings.Cut(grant, "=")
+		role = strings.TrimSpace(role)
+
+		if !ok || role == "" {
+			return fmt.Errorf("invalid role %q, want role=permission,permission", grant)
+		}
+
+		roles[role] = []string{}
+
+		for _, permission := range strings.Split(permissions, ",") {
+			if permission = strings.TrimSpace(permission); permission != "" {
+				roles[role] = append(roles[role], permission)
+			}
+		}
+	}
+
+	*r = roles
+	return nil
+}
+
+func (r *rolesValue) String() string {
+	grants := make([]string, 0, len(*r))
+
+	for role, permissions := range *r {
+		grants = append(grants, role+"="+strings.Join(permissions, ","))
+	}
+
+	slices.Sort(grants)
+
+	return strings.Join(grants, ";")
+}
+
+// listValue is a comma separated list.
+type listValue []string
+
+func (l *listValue) Set(value string) error {
+	list := listValue{}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	*l = list
+	return nil
+}
+
+func (l *listValue) String() string {
+	return strings.Join(*l, ",")
+}
+
+// rateLimitValue is a rate limit written requests/period, or 0 for none.
+type rateLimitValue RateLimit
+
+func (r *rateLimitValue) Set(value string) error {
+	requests, period, hasPeriod := strings.Cut(strings.TrimSpace(value), "/")
+
+	parsedRequests, err := strconv.ParseUint(requests, 10, 0)
+	if err != nil {
+		return fmt.Errorf("invalid rate limit %q, want requests/period", value)
+	}
+
+	var parsedPeriod time.Duration
+
+	if hasPeriod {
+		if parsedPeriod, err = time.ParseDuration(period); err != nil {
+			return fmt.Errorf("invalid rate limit %q, want requests/period", value)
+		}
+	} else if parsedRequests != 0 {
+		return fmt.Errorf("invalid rate limit %q, want requests/period", value)
+	}
+
+	*r = rateLimitValue{Requests: uint(parsedRequests), Period: parsedPeriod}
+	return nil
+}
+
+func (r *rateLimitValue) String() string {
+	if r.Requests == 0 {
+		return "0"
+	}
+
+	return strconv.FormatUint(uint64(r.Requests), 10) + "/" + r.Period.String()
+}
+
+// routeLimitsValue are rate limits of routes written route=limit;route=limit, as in
+// GET /books=60/1m.
+type routeLimitsValue map[string]RateLimit
+
+func (r *routeLimitsValue) Set(value string) error {
+	limits := routeLimitsValue{}
+
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+
+		if !ok || route == "" {
+			return fmt.Errorf("invalid route limit %q, want route=requests/period", entry)
+		}
+
+		var parsed rateLimitValue
+		if err := parsed.Set(limit); err != nil {
+			return err
+		}
+
+		limits[route] = RateLimit(parsed)
+	}
+
+	*r = limits
+	return nil
+}
+
+func (r *routeLimitsValue) String() string {
+	entries := make([]string, 0, len(*r))
+
+	for route, limit := range *r {
+		value := rateLimitValue(limit)
+		entries = append(entries, route+"="+value.String())
+	}
+
+	slices.Sort(entries)
+
+	return strings.Join(entries, ";")
+}
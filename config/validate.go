@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// exampleCursorSecret is the cursor secret older copies of config.example.yaml set.
+const exampleCursorSecret = "change-me"
+
+// routeMethods are the methods the keys of rate_limit.routes may start with.
+var routeMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+
+// Validate reports every invalid setting at once, so a bad deployment fails at startup.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.App.Addr == "" {
+		errs = append(errs, errors.New("app.addr is required"))
+	}
+
+	if !slices.Contains([]string{"debug", "release", "test"}, c.App.GinMode) {
+		errs = append(errs, fmt.Errorf("app.gin_mode must be debug, release or test, got %q", c.App.GinMode))
+	}
+
+	if c.App.ReadTimeout <= 0 || c.App.WriteTimeout <= 0 || c.App.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("app timeouts must be positive"))
+	}
+
+	if c.App.MaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("app.max_body_bytes must be positive"))
+	}
+
+	if !slices.Contains([]string{"postgres", "sqlite", "memory"}, c.Database.Driver) {
+		errs = append(errs, fmt.Errorf("database.driver must be postgres, sqlite or memory, got %q", c.Database.Driver))
+	}
+
+	if c.Database.DSN == "" && c.Database.Driver != "memory" {
+		errs = append(errs, errors.New("database.dsn is required"))
+	}
+
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database connection limits must not be negative"))
+	}
+
+	if !slices.Contains([]string{"default", "read_uncommitted", "read_committed", "repeatable_read", "serializable"}, c.Database.TxIsolation) {
+		errs = append(errs, fmt.Errorf("database.tx_isolation must be default, read_uncommitted, read_committed, repeatable_read or serializable, got %q", c.Database.TxIsolation))
+	}
+
+	if c.Database.QueryTimeout < 0 {
+		errs = append(errs, errors.New("database.query_timeout must not be negative"))
+	}
+
+	if !slices.Contains([]string{"title_author", "isbn", "none"}, c.Books.UniqueBy) {
+		errs = append(errs, fmt.Errorf("books.unique_by must be title_author, isbn or none, got %q", c.Books.UniqueBy))
+	}
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.Log.Level) {
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+
+	if !slices.Contains([]string{"json", "text"}, c.Log.Format) {
+		errs = append(errs, fmt.Errorf("log.format must be json or text, got %q", c.Log.Format))
+	}
+
+	if c.Pagination.DefaultPageSize == 0 || c.Pagination.DefaultPageSize > c.Pagination.MaxPageSize {
+		errs = append(errs, errors.New("pagination.default_page_size must be between 1 and pagination.max_page_size"))
+	}
+
+	// anyone knowing the secret can forge cursors, the one of the example config is public
+	if c.Pagination.CursorSecret == exampleCursorSecret {
+		errs = append(errs, fmt.Errorf("pagination.cursor_secret must not be %q, set a random secret or leave it empty", exampleCursorSecret))
+	}
+
+	if c.Auth.ClockSkew < 0 {
+		errs = append(errs, errors.New("auth.clock_skew must not be negative"))
+	}
+
+	if _, ok := c.Auth.Roles[c.Auth.AnonymousRole]; c.Auth.AnonymousRole != "" && !ok {
+		errs = append(errs, fmt.Errorf("auth.anonymous_role must be one of auth.roles, got %q", c.Auth.AnonymousRole))
+	}
+
+	if !slices.Contains([]string{"", "header", "subdomain", "claim"}, c.Tenancy.Source) {
+		errs = append(errs, fmt.Errorf("tenancy.source must be empty, header, subdomain or claim, got %q", c.Tenancy.Source))
+	}
+
+	if c.Tenancy.Source == "header" && c.Tenancy.Header == "" {
+		errs = append(errs, errors.New("tenancy.header is required when tenancy.source is header"))
+	}
+
+	if c.Tenancy.Source == "subdomain" && c.Tenancy.Domain == "" {
+		errs = append(errs, errors.New("tenancy.domain is required when tenancy.source is subdomain"))
+	}
+
+	for _, proxy := range c.App.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("app.trusted_proxies must hold addresses or CIDR ranges, got %q", proxy))
+		}
+	}
+
+	if c.RateLimit.Default.Requests > 0 && c.RateLimit.Default.Period <= 0 {
+		errs = append(errs, errors.New("rate_limit.default.period must be positive"))
+	}
+
+	for route, limit := range c.RateLimit.Routes {
+		method, path, _ := strings.Cut(route, " ")
+
+		if !slices.Contains(routeMethods, method) || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("rate_limit.routes must be keyed by method and path, as in GET /books, got %q", route))
+		}
+
+		if limit.Requests > 0 && limit.Period <= 0 {
+			errs = append(errs, fmt.Errorf("the period of rate_limit.routes %q must be positive", route))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type AuthorHandler interface {
+	Create(ctx *gin.Context)
+	FindOneById(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
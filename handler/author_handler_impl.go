@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type authorHandlerImpl struct {
+	as  service.AuthorService
+	cfg *config.Config
+}
+
+func NewAuthorHandlerImpl(as service.AuthorService, cfg *config.Config) AuthorHandler {
+	return &authorHandlerImpl{as, cfg}
+}
+
+func (a *authorHandlerImpl) Create(ctx *gin.Context) {
+	authorDto := new(dto.NewAuthorRequest)
+	if err := bindJSON(ctx, a.cfg.App.MaxBodyBytes, authorDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := a.as.Create(ctx.Request.Context(), authorDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, result, nil)
+}
+
+func (a *authorHandlerImpl) FindOneById(ctx *gin.Context) {
+	authorId, errParam := getAuthorIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	result, err := a.as.FindOneById(ctx.Request.Context(), authorId)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (a *authorHandlerImpl) FindAll(ctx *gin.Context) {
+	req := new(dto.FindAllAuthorRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		fail(ctx, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters"))
+		return
+	}
+
+	result, meta, err := a.as.FindAll(ctx.Request.Context(), req)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	meta.Links = buildPaginationLinks(ctx.Request.URL, meta)
+
+	respond(ctx, http.StatusOK, result, meta)
+}
+
+func (a *authorHandlerImpl) Update(ctx *gin.Context) {
+	authorId, errParam := getAuthorIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	authorDto := new(dto.NewAuthorRequest)
+	if err := bindJSON(ctx, a.cfg.App.MaxBodyBytes, authorDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := a.as.Update(ctx.Request.Context(), authorId, authorDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (a *authorHandlerImpl) Delete(ctx *gin.Context) {
+	authorId, errParam := getAuthorIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	if err := a.as.Delete(ctx.Request.Context(), authorId); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, nil, nil)
+}
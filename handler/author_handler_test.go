@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAuthorHandlerSuite struct {
+	handlerSuite
+	ah  AuthorHandler
+	asm *mocks.AuthorService
+}
+
+func TestUnitTestAuthorHandler(t *testing.T) {
+	suite.Run(t, &unitTestAuthorHandlerSuite{})
+}
+
+func (u *unitTestAuthorHandlerSuite) SetupTest() {
+	u.asm = mocks.NewAuthorService(u.T())
+	u.ah = NewAuthorHandlerImpl(u.asm, config.Default())
+
+	u.setupContext()
+}
+
+func (u *unitTestAuthorHandlerSuite) TestCreate_Success() {
+	u.asm.On("Create", u.requestContext(), &dto.NewAuthorRequest{Name: "James Clear"}).Return(&dto.AuthorResponse{Id: 3, Name: "James Clear"}, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":" James Clear "}`))
+
+	u.ah.Create(u.ctx)
+
+	u.Equal(http.StatusCreated, u.writer.Code)
+	u.JSONEq(`{"status_code":201,"status":"Created","message":"success","data":{"id":3,"name":"James Clear"}}`, u.writer.Body.String())
+}
+
+func (u *unitTestAuthorHandlerSuite) TestCreate_ValidationFailed() {
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"  "}`))
+
+	u.ah.Create(u.ctx)
+
+	u.Equal([]*dto.FieldError{{Field: "name", Rule: "required", Message: "name is required"}}, u.lastError().FieldErrors())
+
+	u.asm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (u *unitTestAuthorHandlerSuite) TestFindOneById_InvalidId() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidAuthorId, "authorId param must be a valid number")
+
+	u.ctx.Params = gin.Params{{Key: "authorId", Value: "abc"}}
+
+	u.ah.FindOneById(u.ctx)
+
+	u.Equal(expected, u.lastError())
+}
+
+func (u *unitTestAuthorHandlerSuite) TestFindAll_Success() {
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 1, TotalCount: 2, TotalPages: 2}
+
+	u.asm.On("FindAll", u.requestContext(), &dto.FindAllAuthorRequest{PageSize: 1, NameContains: "ne"}).Return([]*dto.AuthorResponse{{Id: 2, Name: "Neil Gaiman"}}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/authors?page_size=1&name_contains=ne", nil)
+
+	u.ah.FindAll(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.JSONEq(`{
+		"status_code":200,"status":"OK","message":"success",
+		"data":[{"id":2,"name":"Neil Gaiman"}],
+		"meta":{"page":1,"page_size":1,"total_count":2,"total_pages":2,"links":{"next":"/authors?name_contains=ne&page=2&page_size=1"}}
+	}`, u.writer.Body.String())
+}
+
+func (u *unitTestAuthorHandlerSuite) TestUpdate_Success() {
+	u.asm.On("Update", u.requestContext(), uint(2), &dto.NewAuthorRequest{Name: "Neil R. Gaiman"}).Return(&dto.AuthorResponse{Id: 2, Name: "Neil R. Gaiman"}, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Neil R. Gaiman"}`))
+	u.ctx.Params = gin.Params{{Key: "authorId", Value: "2"}}
+
+	u.ah.Update(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+}
+
+func (u *unitTestAuthorHandlerSuite) TestDelete_InUse() {
+	expected := apperror.New(http.StatusConflict, apperror.CodeInUse, "the author is credited on books, credit them to someone else first")
+
+	u.asm.On("Delete", u.requestContext(), uint(2)).Return(expected)
+
+	u.ctx.Params = gin.Params{{Key: "authorId", Value: "2"}}
+
+	u.ah.Delete(u.ctx)
+
+	u.Equal(expected, u.lastError())
+}
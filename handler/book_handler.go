@@ -6,4 +6,10 @@ type BookHandler interface {
 	Create(ctx *gin.Context)
 	FindOneById(ctx *gin.Context)
 	FindAll(ctx *gin.Context)
+	Search(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Patch(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	AddTag(ctx *gin.Context)
+	RemoveTag(ctx *gin.Context)
 }
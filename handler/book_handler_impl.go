@@ -1,86 +1,220 @@
 package handler
 
 import (
+	"context"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
 	"gin-go-testing/model/dto"
 	"gin-go-testing/service"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/rulyadhika/go-custom-err/errs"
 )
 
 type bookHandlerImpl struct {
-	bs service.BookService
+	bs  service.BookService
+	cfg *config.Config
 }
 
-func NewBookHandlerImpl(bs service.BookService) BookHandler {
-	return &bookHandlerImpl{bs}
+func NewBookHandlerImpl(bs service.BookService, cfg *config.Config) BookHandler {
+	return &bookHandlerImpl{bs, cfg}
 }
 
 func (b *bookHandlerImpl) Create(ctx *gin.Context) {
+	query := new(dto.CreateBookQuery)
+
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		fail(ctx, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters"))
+		return
+	}
+
 	bookDto := new(dto.NewBookRequest)
 
-	if err := ctx.ShouldBindJSON(bookDto); err != nil {
-		unprocessableEntityError := errs.NewUnprocessableEntityError("invalid json request body")
-		ctx.AbortWithStatusJSON(unprocessableEntityError.StatusCode(), unprocessableEntityError)
+	if err := bindJSON(ctx, b.cfg.App.MaxBodyBytes, bookDto); err != nil {
+		fail(ctx, err)
 		return
 	}
 
-	result, err := b.bs.Create(ctx, bookDto)
+	result, created, err := b.bs.Create(ctx.Request.Context(), bookDto, query.Upsert)
 	if err != nil {
-		ctx.AbortWithStatusJSON(err.StatusCode(), err)
+		fail(ctx, err)
 		return
 	}
 
-	response := &dto.APIResponse{
-		Status:     http.StatusText(http.StatusCreated),
-		StatusCode: http.StatusCreated,
-		Message:    "success",
-		Data:       result,
+	// an upsert finding the book already there changed nothing
+	if !created {
+		respond(ctx, http.StatusOK, result, nil)
+		return
 	}
 
-	ctx.JSON(http.StatusCreated, response)
+	respond(ctx, http.StatusCreated, result, nil)
 }
 
 func (b *bookHandlerImpl) FindOneById(ctx *gin.Context) {
-	bookId, errConv := strconv.Atoi(ctx.Param("bookId"))
-	if errConv != nil {
-		unprocessableEntityErr := errs.NewUnprocessableEntityError("bookId param must be a valid number")
-		ctx.AbortWithStatusJSON(unprocessableEntityErr.StatusCode(), unprocessableEntityErr)
+	bookId, errParam := getBookIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
 		return
 	}
 
-	result, err := b.bs.FindOneById(ctx, uint(bookId))
+	result, err := b.bs.FindOneById(ctx.Request.Context(), bookId)
 	if err != nil {
-		ctx.AbortWithStatusJSON(err.StatusCode(), err)
+		fail(ctx, err)
 		return
 	}
 
-	response := &dto.APIResponse{
-		Status:     http.StatusText(http.StatusOK),
-		StatusCode: http.StatusOK,
-		Message:    "success",
-		Data:       result,
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (b *bookHandlerImpl) FindAll(ctx *gin.Context) {
+	req := new(dto.FindAllBookRequest)
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		fail(ctx, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters"))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	// the presence of the cursor param, even empty, switches to keyset pagination
+	if req.Cursor != nil {
+		b.findAllByCursor(ctx, req)
+		return
+	}
+
+	result, meta, err := b.bs.FindAll(ctx.Request.Context(), req)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
 
+	meta.Links = buildPaginationLinks(ctx.Request.URL, meta)
+
+	respond(ctx, http.StatusOK, result, meta)
 }
 
-func (b *bookHandlerImpl) FindAll(ctx *gin.Context) {
-	result, err := b.bs.FindAll(ctx)
+func (b *bookHandlerImpl) findAllByCursor(ctx *gin.Context, req *dto.FindAllBookRequest) {
+	result, meta, err := b.bs.FindAllByCursor(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.AbortWithStatusJSON(err.StatusCode(), err)
+		fail(ctx, err)
 		return
 	}
 
-	response := &dto.APIResponse{
-		Status:     http.StatusText(http.StatusOK),
-		StatusCode: http.StatusOK,
-		Message:    "success",
-		Data:       result,
+	respond(ctx, http.StatusOK, result, meta)
+}
+
+func (b *bookHandlerImpl) Search(ctx *gin.Context) {
+	req := new(dto.SearchBookRequest)
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		fail(ctx, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters"))
+		return
+	}
+
+	result, meta, err := b.bs.Search(ctx.Request.Context(), req)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	meta.Links = buildPaginationLinks(ctx.Request.URL, meta)
+
+	respond(ctx, http.StatusOK, result, meta)
+}
+
+func (b *bookHandlerImpl) Update(ctx *gin.Context) {
+	bookId, errParam := getBookIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	bookDto := new(dto.NewBookRequest)
+
+	if err := bindJSON(ctx, b.cfg.App.MaxBodyBytes, bookDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := b.bs.Update(ctx.Request.Context(), bookId, bookDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (b *bookHandlerImpl) Patch(ctx *gin.Context) {
+	bookId, errParam := getBookIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	if contentType := ctx.ContentType(); contentType != mergePatchContentType && contentType != binding.MIMEJSON {
+		fail(ctx, apperror.New(http.StatusUnsupportedMediaType, apperror.CodeUnsupportedMediaType, "content type must be "+mergePatchContentType))
+		return
+	}
+
+	patchDto := new(dto.PatchBookRequest)
+
+	if err := bindJSON(ctx, b.cfg.App.MaxBodyBytes, patchDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := b.bs.Patch(ctx.Request.Context(), bookId, patchDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (b *bookHandlerImpl) Delete(ctx *gin.Context) {
+	bookId, errParam := getBookIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	if err := b.bs.Delete(ctx.Request.Context(), bookId); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, nil, nil)
+}
+
+func (b *bookHandlerImpl) AddTag(ctx *gin.Context) {
+	b.changeTag(ctx, b.bs.AddTag)
+}
+
+func (b *bookHandlerImpl) RemoveTag(ctx *gin.Context) {
+	b.changeTag(ctx, b.bs.RemoveTag)
+}
+
+// changeTag serves both tag endpoints, which differ only in the service call.
+func (b *bookHandlerImpl) changeTag(ctx *gin.Context, change func(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError)) {
+	bookId, errParam := getBookIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	tagId, errParam := getTagIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	result, err := change(ctx.Request.Context(), bookId, tagId)
+	if err != nil {
+		fail(ctx, err)
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respond(ctx, http.StatusOK, result, nil)
 }
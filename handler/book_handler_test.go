@@ -2,12 +2,17 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
 	"gin-go-testing/mocks"
 	"gin-go-testing/model/dto"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -17,11 +22,9 @@ import (
 )
 
 type unitTestBookHandlerSuite struct {
-	suite.Suite
-	bh     BookHandler
-	bsm    *mocks.BookService
-	ctx    *gin.Context
-	writer *httptest.ResponseRecorder
+	handlerSuite
+	bh  BookHandler
+	bsm *mocks.BookService
 }
 
 func TestUnitTestBookHandler(t *testing.T) {
@@ -32,15 +35,31 @@ func (u *unitTestBookHandlerSuite) SetupTest() {
 	bookServMock := mocks.NewBookService(u.T())
 	u.bsm = bookServMock
 
-	u.bh = NewBookHandlerImpl(bookServMock)
+	u.bh = NewBookHandlerImpl(bookServMock, config.Default())
 
-	// set context and response recorder
-	gin.SetMode(gin.TestMode)
+	u.setupContext()
+}
+
+// authorsJSON is authors the way a response body decodes them.
+func authorsJSON(authors []*dto.AuthorResponse) []any {
+	result := []any{}
+
+	for _, author := range authors {
+		result = append(result, map[string]any{"id": float64(author.Id), "name": author.Name})
+	}
 
-	writer := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(writer)
-	u.ctx = ctx
-	u.writer = writer
+	return result
+}
+
+// tagsJSON is tags the way a response body decodes them.
+func tagsJSON(tags []*dto.TagResponse) []any {
+	result := []any{}
+
+	for _, tag := range tags {
+		result = append(result, map[string]any{"id": float64(tag.Id), "name": tag.Name})
+	}
+
+	return result
 }
 
 func (u *unitTestBookHandlerSuite) TestFindOneById_Success() {
@@ -48,15 +67,19 @@ func (u *unitTestBookHandlerSuite) TestFindOneById_Success() {
 	bookId := uint(1)
 
 	data := &dto.BookResponse{
-		Id:     bookId,
-		Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
-		Author: "James Clear",
+		Id:      bookId,
+		Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author:  "James Clear",
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}},
+		Tags:    []*dto.TagResponse{{Id: 5, Name: "Self-help"}},
 	}
 
 	expectedDataMap := map[string]any{
-		"id":     float64(data.Id),
-		"title":  data.Title,
-		"author": data.Author,
+		"id":      float64(data.Id),
+		"title":   data.Title,
+		"author":  data.Author,
+		"authors": authorsJSON(data.Authors),
+		"tags":    tagsJSON(data.Tags),
 	}
 
 	expected := dto.APIResponse{
@@ -67,7 +90,7 @@ func (u *unitTestBookHandlerSuite) TestFindOneById_Success() {
 	}
 
 	// mock service method
-	u.bsm.On("FindOneById", u.ctx, bookId).Return(data, nil)
+	u.bsm.On("FindOneById", u.requestContext(), bookId).Return(data, nil)
 
 	// set route params
 	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
@@ -84,19 +107,30 @@ func (u *unitTestBookHandlerSuite) TestFindOneById_Success() {
 	u.bsm.AssertExpectations(u.T())
 }
 
+func (u *unitTestBookHandlerSuite) TestFindOneById_PropagatesRequestContext() {
+	requestCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u.bsm.On("FindOneById", mock.MatchedBy(func(ctx context.Context) bool {
+		return errors.Is(ctx.Err(), context.Canceled)
+	}), uint(1)).Return(nil, errs.NewNotFoundError("data not found"))
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books/1", nil).WithContext(requestCtx)
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "1"}}
+
+	u.bh.FindOneById(u.ctx)
+
+	u.bsm.AssertExpectations(u.T())
+}
+
 func (u *unitTestBookHandlerSuite) TestFindOneById_NotFound() {
 	// setup expected result
 	bookId := uint(1)
 
-	expected := dto.APIResponse{
-		Status:     http.StatusText(http.StatusNotFound),
-		StatusCode: http.StatusNotFound,
-		Message:    "data not found",
-		Data:       nil,
-	}
+	expected := apperror.New(http.StatusNotFound, apperror.CodeNotFound, "data not found")
 
 	// mock service method
-	u.bsm.On("FindOneById", u.ctx, bookId).Return(nil, errs.NewNotFoundError("data not found"))
+	u.bsm.On("FindOneById", u.requestContext(), bookId).Return(nil, errs.NewNotFoundError("data not found"))
 
 	// set route params
 	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
@@ -104,26 +138,26 @@ func (u *unitTestBookHandlerSuite) TestFindOneById_NotFound() {
 	// call the method
 	u.bh.FindOneById(u.ctx)
 
-	var apiResponse dto.APIResponse
-	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
-
-	u.NoError(err)
-	u.Equal(expected, apiResponse)
+	u.Equal(expected, u.lastError())
 
 	u.bsm.AssertExpectations(u.T())
 }
 
 func (u *unitTestBookHandlerSuite) TestCreate_Success() {
 	data := &dto.BookResponse{
-		Id:     1,
-		Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
-		Author: "James Clear",
+		Id:      1,
+		Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author:  "James Clear",
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}},
+		Tags:    []*dto.TagResponse{},
 	}
 
 	expectedDataMap := map[string]any{
-		"id":     float64(data.Id),
-		"title":  data.Title,
-		"author": data.Author,
+		"id":      float64(data.Id),
+		"title":   data.Title,
+		"author":  data.Author,
+		"authors": authorsJSON(data.Authors),
+		"tags":    tagsJSON(data.Tags),
 	}
 
 	expected := dto.APIResponse{
@@ -133,7 +167,7 @@ func (u *unitTestBookHandlerSuite) TestCreate_Success() {
 		Data:       expectedDataMap,
 	}
 
-	u.bsm.On("Create", u.ctx, mock.Anything).Return(data, nil)
+	u.bsm.On("Create", u.requestContext(), mock.Anything, false).Return(data, true, nil)
 
 	// create request body
 	requestData := dto.NewBookRequest{
@@ -157,19 +191,16 @@ func (u *unitTestBookHandlerSuite) TestCreate_Success() {
 
 func (u *unitTestBookHandlerSuite) TestCreate_Failed() {
 	data := &dto.BookResponse{
-		Id:     1,
-		Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
-		Author: "James Clear",
+		Id:      1,
+		Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author:  "James Clear",
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}},
+		Tags:    []*dto.TagResponse{},
 	}
 
-	expected := dto.APIResponse{
-		Status:     http.StatusText(http.StatusInternalServerError),
-		StatusCode: http.StatusInternalServerError,
-		Message:    "something went wrong",
-		Data:       nil,
-	}
+	expected := apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "something went wrong")
 
-	u.bsm.On("Create", u.ctx, mock.Anything).Return(nil, errs.NewInternalServerError("something went wrong"))
+	u.bsm.On("Create", u.requestContext(), mock.Anything, false).Return(nil, false, errs.NewInternalServerError("something went wrong"))
 
 	// create request body
 	requestData := dto.NewBookRequest{
@@ -182,38 +213,202 @@ func (u *unitTestBookHandlerSuite) TestCreate_Failed() {
 
 	u.bh.Create(u.ctx)
 
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_UpsertExisting() {
+	data := &dto.BookResponse{Id: 7, Title: "Dune", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{{Id: 2, Name: "Frank Herbert"}}, Tags: []*dto.TagResponse{}}
+
+	u.bsm.On("Create", u.requestContext(), &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"}, true).Return(data, false, nil)
+
+	requestBody, _ := json.Marshal(dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"})
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/?upsert=true", bytes.NewBuffer(requestBody))
+
+	u.bh.Create(u.ctx)
+
 	var apiResponse dto.APIResponse
-	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(json.Unmarshal(u.writer.Body.Bytes(), &apiResponse))
 
-	u.NoError(err)
-	u.Equal(expected, apiResponse)
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.Equal(map[string]any{"id": float64(7), "title": "Dune", "author": "Frank Herbert", "authors": authorsJSON(data.Authors), "tags": []any{}}, apiResponse.Data)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_InvalidUpsert() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters")
+
+	requestBody, _ := json.Marshal(dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"})
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/?upsert=maybe", bytes.NewBuffer(requestBody))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_ValidationFailed() {
+	expected := apperror.NewValidationError([]*dto.FieldError{
+		{Field: "title", Rule: "required", Message: "title is required"},
+		{Field: "author", Rule: "max", Message: "author must be at most 255 characters long"},
+	})
+
+	requestBody, _ := json.Marshal(dto.NewBookRequest{Title: "   ", Author: strings.Repeat("é", 256)})
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_NormalizesInput() {
+	// "Cafe\u0301" is the decomposed form of "Café"
+	normalized := &dto.NewBookRequest{Title: "Café Society", Author: "James Clear"}
+
+	u.bsm.On("Create", u.requestContext(), normalized, false).Return(&dto.BookResponse{Id: 1, Title: normalized.Title, Author: normalized.Author}, true, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"  Cafe\u0301 Society ","author":"\tJames Clear\n"}`))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(http.StatusCreated, u.writer.Code)
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_NormalizesDetails() {
+	isbn, language := "9780441013593", "pt-BR"
+	normalized := &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert", Isbn: &isbn, Language: &language}
+
+	u.bsm.On("Create", u.requestContext(), normalized, false).Return(&dto.BookResponse{Id: 1, Title: "Dune", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{{Id: 2, Name: "Frank Herbert"}}, Tags: []*dto.TagResponse{}, Isbn: &isbn, Language: &language}, true, nil)
+
+	// the ISBN-10 0-441-01359-7 is the ISBN-13 978-0-441-01359-3
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"Dune","author":"Frank Herbert","isbn":"0-441-01359-7","language":"PT-br"}`))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(http.StatusCreated, u.writer.Code)
+	u.JSONEq(`{"status_code":201,"status":"Created","message":"success","data":{"id":1,"title":"Dune","author":"Frank Herbert","authors":[{"id":2,"name":"Frank Herbert"}],"tags":[],"isbn":"9780441013593","language":"pt-BR"}}`, u.writer.Body.String())
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_InvalidDetails() {
+	expected := []*dto.FieldError{
+		{Field: "isbn", Rule: "isbn", Message: "isbn must be a valid ISBN-10 or ISBN-13"},
+		{Field: "publication_year", Rule: "publication_year", Message: "publication_year must be a year between 1 and next year"},
+		{Field: "language", Rule: "bcp47_language_tag", Message: "language must be a BCP 47 language tag, e.g. en-US"},
+		{Field: "page_count", Rule: "gte", Message: "page_count must be at least 1"},
+		{Field: "cover_url", Rule: "http_url", Message: "cover_url must be an http or https URL"},
+	}
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(
+		`{"title":"Dune","author":"Frank Herbert","isbn":"0-441-01359-8","publication_year":99999,"language":"not a tag","page_count":0,"cover_url":"ftp://example.com/dune.jpg"}`,
+	))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(expected, u.lastError().FieldErrors())
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_AuthorIds() {
+	expected := &dto.NewBookRequest{Title: "Good Omens", AuthorIds: []uint{3, 2}}
+
+	u.bsm.On("Create", u.requestContext(), expected, false).Return(&dto.BookResponse{Id: 1, Title: "Good Omens"}, true, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"Good Omens","author_ids":[3,2]}`))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(http.StatusCreated, u.writer.Code)
 
 	u.bsm.AssertExpectations(u.T())
 }
 
+func (u *unitTestBookHandlerSuite) TestCreate_InvalidAuthors() {
+	tests := []struct {
+		body     string
+		expected []*dto.FieldError
+	}{
+		{
+			`{"title":"Good Omens"}`,
+			[]*dto.FieldError{{Field: "author", Rule: "required_without", Message: "author is required unless author_ids is given"}},
+		},
+		{
+			`{"title":"Good Omens","author":"Neil Gaiman","author_ids":[2]}`,
+			[]*dto.FieldError{{Field: "author", Rule: "excluded_with", Message: "author cannot be given together with author_ids"}},
+		},
+		{
+			`{"title":"Good Omens","author_ids":[2,2]}`,
+			[]*dto.FieldError{{Field: "author_ids", Rule: "unique", Message: "author_ids must not contain duplicates"}},
+		},
+		{
+			`{"title":"Good Omens","author_ids":[2,0]}`,
+			[]*dto.FieldError{{Field: "author_ids[1]", Rule: "gt", Message: "author_ids[1] must be greater than 0"}},
+		},
+	}
+
+	for _, test := range tests {
+		u.SetupTest()
+
+		u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(test.body))
+
+		u.bh.Create(u.ctx)
+
+		u.Equal(test.expected, u.lastError().FieldErrors(), test.body)
+	}
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_InvalidJson() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidJSON, "invalid json request body")
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":`))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (u *unitTestBookHandlerSuite) TestFindAll_Success() {
 	data := []*dto.BookResponse{
 		{
-			Id:     1,
-			Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
-			Author: "James Clear",
+			Id:      1,
+			Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+			Author:  "James Clear",
+			Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}},
+			Tags:    []*dto.TagResponse{},
 		},
 		{
-			Id:     2,
-			Title:  "The 7 Habits of Highly Effective People",
-			Author: "Stephen R. Covey",
+			Id:      2,
+			Title:   "The 7 Habits of Highly Effective People",
+			Author:  "Stephen R. Covey",
+			Authors: []*dto.AuthorResponse{{Id: 4, Name: "Stephen R. Covey"}},
+			Tags:    []*dto.TagResponse{},
 		},
 	}
 
-	u.bsm.On("FindAll", u.ctx).Return(data, nil)
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 20, TotalCount: 2, TotalPages: 1}
+
+	u.bsm.On("FindAll", u.requestContext(), &dto.FindAllBookRequest{}).Return(data, meta, nil)
 
 	var expectedDataMap []any
 
 	for _, e := range data {
 		expectedDataMap = append(expectedDataMap, map[string]any{
-			"id":     float64(e.Id),
-			"title":  e.Title,
-			"author": e.Author,
+			"id":      float64(e.Id),
+			"title":   e.Title,
+			"author":  e.Author,
+			"authors": authorsJSON(e.Authors),
+			"tags":    tagsJSON(e.Tags),
 		})
 	}
 
@@ -222,8 +417,17 @@ func (u *unitTestBookHandlerSuite) TestFindAll_Success() {
 		StatusCode: http.StatusOK,
 		Message:    "success",
 		Data:       expectedDataMap,
+		Meta: map[string]any{
+			"page":        float64(1),
+			"page_size":   float64(20),
+			"total_count": float64(2),
+			"total_pages": float64(1),
+			"links":       map[string]any{},
+		},
 	}
 
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+
 	u.bh.FindAll(u.ctx)
 
 	var apiResponse dto.APIResponse
@@ -235,21 +439,432 @@ func (u *unitTestBookHandlerSuite) TestFindAll_Success() {
 }
 
 func (u *unitTestBookHandlerSuite) TestFindAll_Failed() {
-	u.bsm.On("FindAll", u.ctx).Return(nil, errs.NewInternalServerError("something went wrong"))
+	u.bsm.On("FindAll", u.requestContext(), mock.Anything).Return(nil, nil, errs.NewInternalServerError("something went wrong"))
+
+	expected := apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "something went wrong")
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_PaginationLinks() {
+	req := &dto.FindAllBookRequest{Page: 2, PageSize: 1, Author: "James Clear"}
+	meta := &dto.PaginationMeta{Page: 2, PageSize: 1, TotalCount: 3, TotalPages: 3}
+
+	u.bsm.On("FindAll", u.requestContext(), req).Return([]*dto.BookResponse{}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?page=2&page_size=1&author=James+Clear", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+
+	u.Equal(map[string]any{
+		"next": "/books?author=James+Clear&page=3&page_size=1",
+		"prev": "/books?author=James+Clear&page=1&page_size=1",
+	}, apiResponse.Meta.(map[string]any)["links"])
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_LimitOffsetLinks() {
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 5, TotalCount: 6, TotalPages: 2}
+
+	u.bsm.On("FindAll", u.requestContext(), &dto.FindAllBookRequest{Limit: 5}).Return([]*dto.BookResponse{}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?limit=5", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+
+	u.Equal(map[string]any{
+		"next": "/books?limit=5&offset=5",
+	}, apiResponse.Meta.(map[string]any)["links"])
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_UnalignedOffsetLinks() {
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 10, TotalCount: 30, TotalPages: 3}
+
+	u.bsm.On("FindAll", u.requestContext(), &dto.FindAllBookRequest{Limit: 10, Offset: 5}).Return([]*dto.BookResponse{}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?limit=10&offset=5", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+
+	// the rows before the offset stay reachable and the next page starts after this one
+	u.Equal(map[string]any{
+		"next": "/books?limit=10&offset=15",
+		"prev": "/books?limit=10&offset=0",
+	}, apiResponse.Meta.(map[string]any)["links"])
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_LastUnalignedOffsetLinks() {
+	meta := &dto.PaginationMeta{Page: 3, PageSize: 10, TotalCount: 30, TotalPages: 3}
+
+	u.bsm.On("FindAll", u.requestContext(), &dto.FindAllBookRequest{Limit: 10, Offset: 25}).Return([]*dto.BookResponse{}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?limit=10&offset=25", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+
+	u.Equal(map[string]any{
+		"prev": "/books?limit=10&offset=15",
+	}, apiResponse.Meta.(map[string]any)["links"])
+}
+
+func (u *unitTestBookHandlerSuite) TestUpdate_Success() {
+	bookId := uint(1)
+
+	data := &dto.BookResponse{
+		Id:      bookId,
+		Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author:  "James Clear",
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}},
+		Tags:    []*dto.TagResponse{},
+	}
+
+	expectedDataMap := map[string]any{
+		"id":      float64(data.Id),
+		"title":   data.Title,
+		"author":  data.Author,
+		"authors": authorsJSON(data.Authors),
+		"tags":    tagsJSON(data.Tags),
+	}
+
+	expected := dto.APIResponse{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Message:    "success",
+		Data:       expectedDataMap,
+	}
+
+	requestData := dto.NewBookRequest{
+		Title:  data.Title,
+		Author: data.Author,
+	}
+
+	u.bsm.On("Update", u.requestContext(), bookId, &requestData).Return(data, nil)
+
+	requestBody, _ := json.Marshal(requestData)
+	u.ctx.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
+
+	u.bh.Update(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+
+	u.NoError(err)
+	u.Equal(expected, apiResponse)
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestUpdate_NotFound() {
+	bookId := uint(1)
+
+	expected := apperror.New(http.StatusNotFound, apperror.CodeNotFound, "data not found")
+
+	u.bsm.On("Update", u.requestContext(), bookId, mock.Anything).Return(nil, errs.NewNotFoundError("data not found"))
+
+	requestBody, _ := json.Marshal(dto.NewBookRequest{Title: "Atomic Habits", Author: "James Clear"})
+	u.ctx.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
+
+	u.bh.Update(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestDelete_Success() {
+	bookId := uint(1)
 
 	expected := dto.APIResponse{
-		Status:     http.StatusText(http.StatusInternalServerError),
-		StatusCode: http.StatusInternalServerError,
-		Message:    "something went wrong",
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Message:    "success",
 		Data:       nil,
 	}
 
-	u.bh.FindAll(u.ctx)
+	u.bsm.On("Delete", u.requestContext(), bookId).Return(nil)
+
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
+
+	u.bh.Delete(u.ctx)
 
 	var apiResponse dto.APIResponse
 	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+
 	u.NoError(err)
 	u.Equal(expected, apiResponse)
 
 	u.bsm.AssertExpectations(u.T())
 }
+
+func (u *unitTestBookHandlerSuite) TestDelete_InvalidBookId() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidBookId, "bookId param must be a valid number")
+
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "abc"}}
+
+	u.bh.Delete(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestPatch_Success() {
+	bookId := uint(1)
+	author := "James Clear"
+
+	data := &dto.BookResponse{
+		Id:      bookId,
+		Title:   "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author:  author,
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: author}},
+		Tags:    []*dto.TagResponse{},
+	}
+
+	expected := dto.APIResponse{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Message:    "success",
+		Data: map[string]any{
+			"id":      float64(data.Id),
+			"title":   data.Title,
+			"author":  data.Author,
+			"authors": authorsJSON(data.Authors),
+			"tags":    tagsJSON(data.Tags),
+		},
+	}
+
+	u.bsm.On("Patch", u.requestContext(), bookId, &dto.PatchBookRequest{Author: &author}).Return(data, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(`{"author":"James Clear"}`))
+	u.ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: strconv.Itoa(int(bookId))}}
+
+	u.bh.Patch(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+
+	u.NoError(err)
+	u.Equal(expected, apiResponse)
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestPatch_NullMember() {
+	expected := apperror.NewValidationError([]*dto.FieldError{
+		{Field: "title", Rule: "required", Message: "title cannot be removed"},
+	})
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(`{"title":null}`))
+	u.ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "1"}}
+
+	u.bh.Patch(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestPatch_RemovesDetail() {
+	expected := &dto.PatchBookRequest{Removed: []string{"publisher", "cover_url"}}
+
+	u.bsm.On("Patch", u.requestContext(), uint(1), expected).Return(&dto.BookResponse{Id: 1, Title: "Dune", Author: "Frank Herbert"}, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(`{"publisher":null,"cover_url":null}`))
+	u.ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "1"}}
+
+	u.bh.Patch(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+
+	u.bsm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookHandlerSuite) TestPatch_EmptyMember() {
+	u.ctx.Request = httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(`{"author":"  "}`))
+	u.ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "1"}}
+
+	u.bh.Patch(u.ctx)
+
+	u.Equal([]*dto.FieldError{{Field: "author", Rule: "min", Message: "author must not be empty"}}, u.lastError().FieldErrors())
+
+	u.bsm.AssertNotCalled(u.T(), "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestPatch_UnsupportedMediaType() {
+	u.ctx.Request = httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(`{"title":"Atomic Habits"}`))
+	u.ctx.Request.Header.Set("Content-Type", "text/plain")
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "1"}}
+
+	u.bh.Patch(u.ctx)
+
+	u.Equal(http.StatusUnsupportedMediaType, u.lastError().StatusCode())
+	u.Equal(apperror.CodeUnsupportedMediaType, u.lastError().Code())
+
+	u.bsm.AssertNotCalled(u.T(), "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_CursorMode() {
+	cursor := ""
+	data := []*dto.BookResponse{{Id: 1, Title: "Atomic Habits", Author: "James Clear", Authors: []*dto.AuthorResponse{}, Tags: []*dto.TagResponse{}}}
+	meta := &dto.CursorMeta{PageSize: 1, NextCursor: "abc.def"}
+
+	u.bsm.On("FindAllByCursor", u.requestContext(), &dto.FindAllBookRequest{PageSize: 1, Cursor: &cursor}).Return(data, meta, nil)
+
+	expected := dto.APIResponse{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Message:    "success",
+		Data: []any{map[string]any{
+			"id":      float64(1),
+			"title":   "Atomic Habits",
+			"author":  "James Clear",
+			"authors": []any{},
+			"tags":    []any{},
+		}},
+		Meta: map[string]any{
+			"page_size":   float64(1),
+			"next_cursor": "abc.def",
+		},
+	}
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?page_size=1&cursor=", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+	u.Equal(expected, apiResponse)
+
+	u.bsm.AssertNotCalled(u.T(), "FindAll", mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestCreate_BodyTooLarge() {
+	cfg := config.Default()
+	cfg.App.MaxBodyBytes = 16
+	u.bh = NewBookHandlerImpl(u.bsm, cfg)
+
+	requestBody, _ := json.Marshal(dto.NewBookRequest{Title: "Atomic Habits", Author: "James Clear"})
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+
+	u.bh.Create(u.ctx)
+
+	u.Equal(http.StatusRequestEntityTooLarge, u.lastError().StatusCode())
+	u.Equal(apperror.CodePayloadTooLarge, u.lastError().Code())
+
+	u.bsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_TagFacets() {
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 20, TotalCount: 1, TotalPages: 1, Facets: &dto.Facets{Tags: []*dto.TagFacet{{Id: 9, Name: "Classic", Count: 1}}}}
+
+	u.bsm.On("FindAll", u.requestContext(), &dto.FindAllBookRequest{Tag: "classic,fiction", TagMode: "any"}).Return([]*dto.BookResponse{}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?tag=classic,fiction&tag_mode=any", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	var apiResponse dto.APIResponse
+	err := json.Unmarshal(u.writer.Body.Bytes(), &apiResponse)
+	u.NoError(err)
+
+	u.Equal(map[string]any{
+		"tags": []any{map[string]any{"id": float64(9), "name": "Classic", "count": float64(1)}},
+	}, apiResponse.Meta.(map[string]any)["facets"])
+}
+
+func (u *unitTestBookHandlerSuite) TestFindAll_InvalidTagMode() {
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books?tag=classic&tag_mode=none", nil)
+
+	u.bh.FindAll(u.ctx)
+
+	u.Equal(apperror.CodeInvalidQuery, u.lastError().Code())
+
+	u.bsm.AssertNotCalled(u.T(), "FindAll", mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestAddTag_Success() {
+	data := &dto.BookResponse{Id: 3, Title: "Dune", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{}, Tags: []*dto.TagResponse{{Id: 9, Name: "Classic"}}}
+
+	u.bsm.On("AddTag", u.requestContext(), uint(3), uint(9)).Return(data, nil)
+
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "3"}, {Key: "tagId", Value: "9"}}
+
+	u.bh.AddTag(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.JSONEq(`{"status_code":200,"status":"OK","message":"success","data":{"id":3,"title":"Dune","author":"Frank Herbert","authors":[],"tags":[{"id":9,"name":"Classic"}]}}`, u.writer.Body.String())
+}
+
+func (u *unitTestBookHandlerSuite) TestRemoveTag_InvalidTagId() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidTagId, "tagId param must be a valid number")
+
+	u.ctx.Params = gin.Params{{Key: "bookId", Value: "3"}, {Key: "tagId", Value: "abc"}}
+
+	u.bh.RemoveTag(u.ctx)
+
+	u.Equal(expected, u.lastError())
+
+	u.bsm.AssertNotCalled(u.T(), "RemoveTag", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookHandlerSuite) TestSearch_Success() {
+	data := []*dto.BookSearchHitResponse{{
+		Book:      &dto.BookResponse{Id: 3, Title: "Dune", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{}, Tags: []*dto.TagResponse{}},
+		Score:     1.5,
+		Highlight: &dto.BookHighlightResponse{Title: "<mark>Dune</mark>", Author: "Frank Herbert"},
+	}}
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 1, TotalCount: 2, TotalPages: 2}
+
+	u.bsm.On("Search", u.requestContext(), &dto.SearchBookRequest{Q: "dune", PageSize: 1}).Return(data, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books/search?q=dune&page_size=1", nil)
+
+	u.bh.Search(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.JSONEq(`{"status_code":200,"status":"OK","message":"success",
+		"data":[{"book":{"id":3,"title":"Dune","author":"Frank Herbert","authors":[],"tags":[]},"score":1.5,"highlight":{"title":"<mark>Dune</mark>","author":"Frank Herbert"}}],
+		"meta":{"page":1,"page_size":1,"total_count":2,"total_pages":2,"links":{"next":"/books/search?page=2&page_size=1&q=dune"}}}`, u.writer.Body.String())
+}
+
+func (u *unitTestBookHandlerSuite) TestSearch_MissingQuery() {
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/books/search?page=2", nil)
+
+	u.bh.Search(u.ctx)
+
+	u.Equal(apperror.CodeInvalidQuery, u.lastError().Code())
+
+	u.bsm.AssertNotCalled(u.T(), "Search", mock.Anything, mock.Anything)
+}
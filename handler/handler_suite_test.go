@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"gin-go-testing/apperror"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// handlerSuite is embedded by the handler suites, it holds the gin context of the request
+// under test and the recorder of its response.
+type handlerSuite struct {
+	suite.Suite
+	ctx    *gin.Context
+	writer *httptest.ResponseRecorder
+}
+
+// setupContext starts every test with a fresh context of a GET / request.
+func (h *handlerSuite) setupContext() {
+	gin.SetMode(gin.TestMode)
+
+	h.writer = httptest.NewRecorder()
+	h.ctx, _ = gin.CreateTestContext(h.writer)
+	h.ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+}
+
+// lastError returns the error the handler reported for the Errors middleware to render.
+func (h *handlerSuite) lastError() *apperror.Error {
+	h.Require().True(h.ctx.IsAborted())
+	h.Require().NotEmpty(h.ctx.Errors)
+
+	var appErr *apperror.Error
+	h.Require().ErrorAs(h.ctx.Errors.Last(), &appErr)
+
+	return appErr
+}
+
+// requestContext matches the context of the request under test, which handlers hand to the service.
+func (h *handlerSuite) requestContext() any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx == h.ctx.Request.Context()
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"gin-go-testing/apperror"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+func getBookIdParam(ctx *gin.Context) (uint, errs.CustomError) {
+	bookId, err := strconv.ParseUint(ctx.Param("bookId"), 10, 0)
+	if err != nil {
+		return 0, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidBookId, "bookId param must be a valid number")
+	}
+
+	return uint(bookId), nil
+}
+
+func getAuthorIdParam(ctx *gin.Context) (uint, errs.CustomError) {
+	authorId, err := strconv.ParseUint(ctx.Param("authorId"), 10, 0)
+	if err != nil {
+		return 0, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidAuthorId, "authorId param must be a valid number")
+	}
+
+	return uint(authorId), nil
+}
+
+func getTagIdParam(ctx *gin.Context) (uint, errs.CustomError) {
+	tagId, err := strconv.ParseUint(ctx.Param("tagId"), 10, 0)
+	if err != nil {
+		return 0, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidTagId, "tagId param must be a valid number")
+	}
+
+	return uint(tagId), nil
+}
+
+// buildPaginationLinks points at the neighbouring pages, keeping every other query
+// parameter and the paging style (limit/offset or page/page_size) of the request.
+func buildPaginationLinks(requestURL *url.URL, meta *dto.PaginationMeta) *dto.PaginationLinks {
+	if requestURL.Query().Has("limit") {
+		return buildOffsetLinks(requestURL, meta)
+	}
+
+	links := &dto.PaginationLinks{}
+
+	pageURL := func(page uint) string {
+		query := requestURL.Query()
+		query.Set("page", strconv.FormatUint(uint64(page), 10))
+
+		return requestURL.Path + "?" + query.Encode()
+	}
+
+	if meta.Page < meta.TotalPages {
+		links.Next = pageURL(meta.Page + 1)
+	}
+
+	if meta.Page > 1 {
+		links.Prev = pageURL(min(meta.Page-1, max(meta.TotalPages, 1)))
+	}
+
+	return links
+}
+
+// buildOffsetLinks moves the offset of the request by a page either way. The offset need not
+// be a multiple of the limit, so the links start from it rather than from a page number.
+func buildOffsetLinks(requestURL *url.URL, meta *dto.PaginationMeta) *dto.PaginationLinks {
+	links := &dto.PaginationLinks{}
+
+	// the offset was bound by the request already, it is a valid number or missing
+	offset, _ := strconv.ParseUint(requestURL.Query().Get("offset"), 10, 0)
+	limit := uint64(meta.PageSize)
+
+	offsetURL := func(offset uint64) string {
+		query := requestURL.Query()
+		query.Set("offset", strconv.FormatUint(offset, 10))
+
+		return requestURL.Path + "?" + query.Encode()
+	}
+
+	if offset+limit < uint64(meta.TotalCount) {
+		links.Next = offsetURL(offset + limit)
+	}
+
+	if offset > 0 {
+		links.Prev = offsetURL(offset - min(offset, limit))
+	}
+
+	return links
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"gin-go-testing/apperror"
+	"gin-go-testing/model/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// respond writes a successful result in the dto.APIResponse envelope.
+func respond(ctx *gin.Context, status int, data any, meta any) {
+	ctx.JSON(status, &dto.APIResponse{
+		Status:     http.StatusText(status),
+		StatusCode: uint(status),
+		Message:    "success",
+		Data:       data,
+		Meta:       meta,
+	})
+}
+
+// fail reports err and stops the request, the middleware.Errors middleware renders it.
+func fail(ctx *gin.Context, err errs.CustomError) {
+	ctx.Error(apperror.From(err))
+	ctx.Abort()
+}
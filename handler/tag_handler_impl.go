@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tagHandlerImpl struct {
+	ts  service.TagService
+	cfg *config.Config
+}
+
+func NewTagHandlerImpl(ts service.TagService, cfg *config.Config) TagHandler {
+	return &tagHandlerImpl{ts, cfg}
+}
+
+func (t *tagHandlerImpl) Create(ctx *gin.Context) {
+	tagDto := new(dto.NewTagRequest)
+	if err := bindJSON(ctx, t.cfg.App.MaxBodyBytes, tagDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := t.ts.Create(ctx.Request.Context(), tagDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusCreated, result, nil)
+}
+
+func (t *tagHandlerImpl) FindOneById(ctx *gin.Context) {
+	tagId, errParam := getTagIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	result, err := t.ts.FindOneById(ctx.Request.Context(), tagId)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (t *tagHandlerImpl) FindAll(ctx *gin.Context) {
+	req := new(dto.FindAllTagRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		fail(ctx, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "invalid query parameters"))
+		return
+	}
+
+	result, meta, err := t.ts.FindAll(ctx.Request.Context(), req)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	meta.Links = buildPaginationLinks(ctx.Request.URL, meta)
+
+	respond(ctx, http.StatusOK, result, meta)
+}
+
+func (t *tagHandlerImpl) Update(ctx *gin.Context) {
+	tagId, errParam := getTagIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	tagDto := new(dto.NewTagRequest)
+	if err := bindJSON(ctx, t.cfg.App.MaxBodyBytes, tagDto); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	result, err := t.ts.Update(ctx.Request.Context(), tagId, tagDto)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, result, nil)
+}
+
+func (t *tagHandlerImpl) Delete(ctx *gin.Context) {
+	tagId, errParam := getTagIdParam(ctx)
+	if errParam != nil {
+		fail(ctx, errParam)
+		return
+	}
+
+	if err := t.ts.Delete(ctx.Request.Context(), tagId); err != nil {
+		fail(ctx, err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, nil, nil)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bytes"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTagHandlerSuite struct {
+	handlerSuite
+	th  TagHandler
+	tsm *mocks.TagService
+}
+
+func TestUnitTestTagHandler(t *testing.T) {
+	suite.Run(t, &unitTestTagHandlerSuite{})
+}
+
+func (u *unitTestTagHandlerSuite) SetupTest() {
+	u.tsm = mocks.NewTagService(u.T())
+	u.th = NewTagHandlerImpl(u.tsm, config.Default())
+
+	u.setupContext()
+}
+
+func (u *unitTestTagHandlerSuite) TestCreate_Success() {
+	parentId := uint(1)
+
+	u.tsm.On("Create", u.requestContext(), &dto.NewTagRequest{Name: "Fantasy", ParentId: &parentId}).Return(&dto.TagResponse{Id: 4, Name: "Fantasy", ParentId: &parentId}, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":" Fantasy ","parent_id":1}`))
+
+	u.th.Create(u.ctx)
+
+	u.Equal(http.StatusCreated, u.writer.Code)
+	u.JSONEq(`{"status_code":201,"status":"Created","message":"success","data":{"id":4,"name":"Fantasy","parent_id":1}}`, u.writer.Body.String())
+}
+
+func (u *unitTestTagHandlerSuite) TestCreate_ValidationFailed() {
+	u.ctx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"sci-fi, fantasy","parent_id":0}`))
+
+	u.th.Create(u.ctx)
+
+	u.Equal([]*dto.FieldError{
+		{Field: "name", Rule: "excludesall", Message: `name must not contain any of ","`},
+		{Field: "parent_id", Rule: "gt", Message: "parent_id must be greater than 0"},
+	}, u.lastError().FieldErrors())
+
+	u.tsm.AssertNotCalled(u.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (u *unitTestTagHandlerSuite) TestFindOneById_InvalidId() {
+	expected := apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidTagId, "tagId param must be a valid number")
+
+	u.ctx.Params = gin.Params{{Key: "tagId", Value: "abc"}}
+
+	u.th.FindOneById(u.ctx)
+
+	u.Equal(expected, u.lastError())
+}
+
+func (u *unitTestTagHandlerSuite) TestFindAll_Success() {
+	meta := &dto.PaginationMeta{Page: 1, PageSize: 1, TotalCount: 2, TotalPages: 2}
+
+	u.tsm.On("FindAll", u.requestContext(), &dto.FindAllTagRequest{PageSize: 1, NameContains: "fi"}).Return([]*dto.TagResponse{{Id: 1, Name: "Fiction"}}, meta, nil)
+
+	u.ctx.Request = httptest.NewRequest(http.MethodGet, "/tags?page_size=1&name_contains=fi", nil)
+
+	u.th.FindAll(u.ctx)
+
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.JSONEq(`{
+		"status_code":200,"status":"OK","message":"success",
+		"data":[{"id":1,"name":"Fiction"}],
+		"meta":{"page":1,"page_size":1,"total_count":2,"total_pages":2,"links":{"next":"/tags?name_contains=fi&page=2&page_size=1"}}
+	}`, u.writer.Body.String())
+}
+
+func (u *unitTestTagHandlerSuite) TestDelete_HasChildren() {
+	expected := apperror.New(http.StatusConflict, apperror.CodeInUse, "other tags are nested under the tag, move or delete them first")
+
+	u.tsm.On("Delete", u.requestContext(), uint(1)).Return(expected)
+
+	u.ctx.Params = gin.Params{{Key: "tagId", Value: "1"}}
+
+	u.th.Delete(u.ctx)
+
+	u.Equal(expected, u.lastError())
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+func init() {
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterValidation("publication_year", isPublicationYear)
+	}
+}
+
+// isPublicationYear accepts years up to the next one, for books announced before release.
+func isPublicationYear(fl validator.FieldLevel) bool {
+	year := fl.Field().Int()
+
+	return year >= 1 && year <= int64(time.Now().Year()+1)
+}
+
+// bindJSON decodes a body of at most maxBytes into obj, normalizes it when it implements
+// dto.Normalizer and then runs the binding validation rules.
+func bindJSON(ctx *gin.Context, maxBytes int64, obj any) errs.CustomError {
+	if ctx.Request == nil || ctx.Request.Body == nil {
+		return apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidJSON, "invalid json request body")
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)
+
+	if err := json.NewDecoder(body).Decode(obj); err != nil {
+		var fieldErr *dto.FieldError
+		if errors.As(err, &fieldErr) {
+			return apperror.NewValidationError([]*dto.FieldError{fieldErr})
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodePayloadTooLarge, fmt.Sprintf("request body must not exceed %d bytes", maxBytes))
+		}
+
+		return apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidJSON, "invalid json request body")
+	}
+
+	if normalizer, ok := obj.(dto.Normalizer); ok {
+		normalizer.Normalize()
+	}
+
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidJSON, "invalid json request body")
+		}
+
+		fieldErrs := make([]*dto.FieldError, 0, len(validationErrs))
+		for _, e := range validationErrs {
+			fieldErrs = append(fieldErrs, newFieldError(obj, e))
+		}
+
+		return apperror.NewValidationError(fieldErrs)
+	}
+
+	return nil
+}
+
+func newFieldError(obj any, e validator.FieldError) *dto.FieldError {
+	field := jsonFieldName(obj, e.StructField())
+
+	var message string
+
+	switch e.Tag() {
+	case "required":
+		message = fmt.Sprintf("%s is required", field)
+	case "min":
+		if e.Param() == "1" {
+			message = fmt.Sprintf("%s must not be empty", field)
+		} else {
+			message = fmt.Sprintf("%s must be at least %s characters long", field, e.Param())
+		}
+	case "max":
+		if e.Kind() == reflect.Slice {
+			message = fmt.Sprintf("%s must have at most %s items", field, e.Param())
+		} else {
+			message = fmt.Sprintf("%s must be at most %s characters long", field, e.Param())
+		}
+	case "gt":
+		message = fmt.Sprintf("%s must be greater than %s", field, e.Param())
+	case "unique":
+		message = fmt.Sprintf("%s must not contain duplicates", field)
+	case "required_without":
+		message = fmt.Sprintf("%s is required unless %s is given", field, jsonFieldName(obj, e.Param()))
+	case "excluded_with":
+		message = fmt.Sprintf("%s cannot be given together with %s", field, jsonFieldName(obj, e.Param()))
+	case "gte":
+		message = fmt.Sprintf("%s must be at least %s", field, e.Param())
+	case "lte":
+		message = fmt.Sprintf("%s must be at most %s", field, e.Param())
+	case "isbn":
+		message = fmt.Sprintf("%s must be a valid ISBN-10 or ISBN-13", field)
+	case "publication_year":
+		message = fmt.Sprintf("%s must be a year between 1 and next year", field)
+	case "bcp47_language_tag":
+		message = fmt.Sprintf("%s must be a BCP 47 language tag, e.g. en-US", field)
+	case "excludesall":
+		message = fmt.Sprintf("%s must not contain any of %q", field, e.Param())
+	case "http_url":
+		message = fmt.Sprintf("%s must be an http or https URL", field)
+	default:
+		message = fmt.Sprintf("%s is invalid", field)
+	}
+
+	return &dto.FieldError{Field: field, Rule: e.Tag(), Message: message}
+}
+
+// jsonFieldName reports a struct field by the name clients send it as, keeping the index of
+// a list item, e.g. "author_ids[1]".
+func jsonFieldName(obj any, structField string) string {
+	if name, index, ok := strings.Cut(structField, "["); ok {
+		return jsonFieldName(obj, name) + "[" + index
+	}
+
+	objType := reflect.TypeOf(obj)
+	for objType.Kind() == reflect.Ptr {
+		objType = objType.Elem()
+	}
+
+	field, ok := objType.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return structField
+	}
+
+	return name
+}
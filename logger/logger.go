@@ -0,0 +1,74 @@
+// Package logger builds the application's slog logger and carries request-scoped loggers
+// through context.Context, so every layer logs with the request it works for.
+package logger
+
+import (
+	"context"
+	"gin-go-testing/config"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the logs, matched case-insensitively.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"password":      true,
+	"secret":        true,
+	"cursor_secret": true,
+	"token":         true,
+	"api_key":       true,
+	"x-api-key":     true,
+	"dsn":           true,
+}
+
+var levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// New creates a logger writing cfg.Format records at cfg.Level and above to w, with the
+// caller of every record and sensitive attributes redacted.
+func New(cfg *config.LogConfig, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       levels[cfg.Level],
+		ReplaceAttr: redact,
+	}
+
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	return attr
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, slog.Default when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+
+	return slog.Default()
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"gin-go-testing/config"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestLoggerSuite struct {
+	suite.Suite
+	out *bytes.Buffer
+	cfg *config.LogConfig
+}
+
+func TestUnitTestLogger(t *testing.T) {
+	suite.Run(t, &unitTestLoggerSuite{})
+}
+
+func (u *unitTestLoggerSuite) SetupTest() {
+	u.out = &bytes.Buffer{}
+	u.cfg = &config.Default().Log
+}
+
+func (u *unitTestLoggerSuite) record() map[string]any {
+	var record map[string]any
+	u.Require().NoError(json.Unmarshal(u.out.Bytes(), &record))
+
+	return record
+}
+
+func (u *unitTestLoggerSuite) TestNew_JSONWithSource() {
+	New(u.cfg, u.out).Info("request", "status", 200)
+
+	record := u.record()
+
+	u.Equal("INFO", record["level"])
+	u.Equal("request", record["msg"])
+	u.Equal(float64(200), record["status"])
+	u.Contains(record["source"], "file")
+}
+
+func (u *unitTestLoggerSuite) TestNew_Level() {
+	u.cfg.Level = "warn"
+
+	l := New(u.cfg, u.out)
+	l.Info("ignored")
+
+	u.Empty(u.out.String())
+
+	l.Warn("kept")
+
+	u.Equal("kept", u.record()["msg"])
+}
+
+func (u *unitTestLoggerSuite) TestNew_Redacts() {
+	New(u.cfg, u.out).Info("login", "Authorization", "Bearer abc", slog.Group("user", "password", "hunter2", "name", "ann"))
+
+	record := u.record()
+
+	u.Equal("[REDACTED]", record["Authorization"])
+	u.Equal(map[string]any{"password": "[REDACTED]", "name": "ann"}, record["user"])
+	u.NotContains(u.out.String(), "hunter2")
+}
+
+func (u *unitTestLoggerSuite) TestFromContext() {
+	l := New(u.cfg, u.out).With("request_id", "req-1")
+
+	FromContext(WithContext(context.Background(), l)).Info("hello")
+
+	u.Equal("req-1", u.record()["request_id"])
+	u.Same(slog.Default(), FromContext(context.Background()))
+}
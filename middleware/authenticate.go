@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+const ApiKeyHeader = "X-Api-Key"
+
+// authenticateChallenge tells clients refused with 401 how to authenticate, RFC 6750.
+const authenticateChallenge = `Bearer realm="books"`
+
+// Authenticate attaches the principal of the credentials of a request, an X-Api-Key header
+// or an Authorization bearer token, to the request context, see auth.PrincipalFrom.
+// Requests with invalid credentials are refused, so are requests without any unless
+// anonymous access is allowed. It must run after Errors, which renders the refusal.
+func Authenticate(a auth.Authenticator, cfg *config.AuthConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey := ctx.GetHeader(ApiKeyHeader)
+		authorization := ctx.GetHeader("Authorization")
+
+		var principal *domain.Principal
+		var err errs.CustomError
+
+		switch {
+		case apiKey != "" && authorization != "":
+			err = apperror.New(http.StatusBadRequest, apperror.CodeBadRequest, "send either an API key or an Authorization header, not both")
+		case apiKey != "":
+			principal, err = a.ApiKey(ctx.Request.Context(), apiKey)
+		case authorization != "":
+			token, ok := bearerToken(authorization)
+			if !ok {
+				err = apperror.New(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "the Authorization header must hold a bearer token")
+				break
+			}
+
+			principal, err = a.BearerToken(ctx.Request.Context(), token)
+		case !cfg.AllowAnonymous:
+			err = apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "authentication required, send an API key or a bearer token")
+		}
+
+		if err != nil {
+			if err.StatusCode() == http.StatusUnauthorized {
+				ctx.Header("WWW-Authenticate", authenticateChallenge)
+			}
+
+			ctx.Error(apperror.From(err))
+			ctx.Abort()
+
+			return
+		}
+
+		if principal != nil {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), principal))
+		}
+
+		ctx.Next()
+	}
+}
+
+// bearerToken returns the token of an Authorization header of the Bearer scheme.
+func bearerToken(authorization string) (string, bool) {
+	scheme, token, _ := strings.Cut(authorization, " ")
+	token = strings.TrimSpace(token)
+
+	return token, strings.EqualFold(scheme, "Bearer") && token != ""
+}
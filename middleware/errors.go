@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/logger"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix turns error codes into the problem type URI.
+const problemTypePrefix = "urn:problem-type:"
+
+// Errors renders the last error handlers reported with ctx.Error, or a panic, as RFC 7807
+// problem details, so every endpoint fails the same way. Handlers only report errors.
+func Errors() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// the server aborts the response on purpose, let it do so
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logger.FromContext(ctx.Request.Context()).ErrorContext(ctx.Request.Context(), "panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+			abortWithProblem(ctx, apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "something went wrong"))
+		}()
+
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		appErr := toAppError(ctx, ctx.Errors.Last().Err)
+
+		if appErr.StatusCode() >= http.StatusInternalServerError {
+			logger.FromContext(ctx.Request.Context()).ErrorContext(ctx.Request.Context(), "request failed", "status", appErr.StatusCode(), "err", ctx.Errors.String())
+		}
+
+		abortWithProblem(ctx, appErr)
+	}
+}
+
+// toAppError maps what a handler reported to the error sent to the client. Whatever went
+// wrong, a request whose context ended failed because of it.
+func toAppError(ctx *gin.Context, err error) *apperror.Error {
+	requestErr := ctx.Request.Context().Err()
+
+	switch {
+	case errors.Is(requestErr, context.Canceled) || errors.Is(err, context.Canceled):
+		return apperror.New(apperror.StatusClientClosedRequest, apperror.CodeClientClosedRequest, "the client closed the request")
+	case errors.Is(requestErr, context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded):
+		return apperror.New(http.StatusGatewayTimeout, apperror.CodeTimeout, "the request timed out")
+	}
+
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return apperror.New(http.StatusInternalServerError, apperror.CodeInternal, "something went wrong")
+}
+
+// NoRoute answers requests no route matches.
+func NoRoute() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		abortWithProblem(ctx, apperror.New(http.StatusNotFound, apperror.CodeRouteNotFound, "route not found"))
+	}
+}
+
+func abortWithProblem(ctx *gin.Context, err *apperror.Error) {
+	code := string(err.Code())
+
+	problem := &dto.ProblemDetails{
+		Type:       problemTypePrefix + code,
+		Title:      http.StatusText(err.StatusCode()),
+		Status:     err.StatusCode(),
+		Detail:     err.Message(),
+		Instance:   ctx.Request.URL.Path,
+		Code:       code,
+		RequestId:  GetRequestId(ctx),
+		Errors:     err.FieldErrors(),
+		Extensions: err.Extensions(),
+	}
+
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/model/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rulyadhika/go-custom-err/errs"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestErrorsSuite struct {
+	suite.Suite
+	router *gin.Engine
+	writer *httptest.ResponseRecorder
+}
+
+func TestUnitTestErrors(t *testing.T) {
+	suite.Run(t, &unitTestErrorsSuite{})
+}
+
+func (u *unitTestErrorsSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.router = gin.New()
+	u.router.Use(RequestId(), Errors())
+	u.router.NoRoute(NoRoute())
+
+	u.writer = httptest.NewRecorder()
+}
+
+// serve runs a request through handler and decodes the problem details it failed with.
+func (u *unitTestErrorsSuite) serve(request *http.Request, handler gin.HandlerFunc) dto.ProblemDetails {
+	u.router.GET("/books", handler)
+	u.router.ServeHTTP(u.writer, request)
+
+	u.Equal(ProblemContentType, u.writer.Header().Get("Content-Type"))
+
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(u.writer.Body.Bytes(), &problem))
+
+	return problem
+}
+
+func (u *unitTestErrorsSuite) TestCustomError() {
+	request := httptest.NewRequest(http.MethodGet, "/books", nil)
+	request.Header.Set(RequestIdHeader, "req-1")
+
+	problem := u.serve(request, func(ctx *gin.Context) {
+		ctx.Error(apperror.From(errs.NewNotFoundError("data not found")))
+	})
+
+	u.Equal(http.StatusNotFound, u.writer.Code)
+	u.Equal(dto.ProblemDetails{
+		Type:      "urn:problem-type:not_found",
+		Title:     http.StatusText(http.StatusNotFound),
+		Status:    http.StatusNotFound,
+		Detail:    "data not found",
+		Instance:  "/books",
+		Code:      "not_found",
+		RequestId: "req-1",
+	}, problem)
+}
+
+func (u *unitTestErrorsSuite) TestExtensions() {
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil), func(ctx *gin.Context) {
+		ctx.Error(apperror.New(http.StatusConflict, apperror.CodeAlreadyExists, "the book already exists").With("existing_id", 7))
+	})
+
+	u.Equal(http.StatusConflict, u.writer.Code)
+	u.Equal("already_exists", problem.Code)
+
+	var members map[string]any
+	u.NoError(json.Unmarshal(u.writer.Body.Bytes(), &members))
+	u.Equal(float64(7), members["existing_id"])
+	u.Equal("the book already exists", members["detail"])
+}
+
+func (u *unitTestErrorsSuite) TestValidationError() {
+	fieldErrors := []*dto.FieldError{{Field: "title", Rule: "required", Message: "title is required"}}
+
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil), func(ctx *gin.Context) {
+		ctx.Error(apperror.NewValidationError(fieldErrors))
+	})
+
+	u.Equal(http.StatusUnprocessableEntity, u.writer.Code)
+	u.Equal("validation_failed", problem.Code)
+	u.Equal(fieldErrors, problem.Errors)
+}
+
+func (u *unitTestErrorsSuite) TestUnknownError() {
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil), func(ctx *gin.Context) {
+		ctx.Error(errors.New("connection reset"))
+	})
+
+	u.Equal(http.StatusInternalServerError, u.writer.Code)
+	u.Equal("internal_error", problem.Code)
+	u.Equal("something went wrong", problem.Detail)
+}
+
+func (u *unitTestErrorsSuite) TestClientClosedRequest() {
+	requestCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil).WithContext(requestCtx), func(ctx *gin.Context) {
+		ctx.Error(apperror.From(errs.NewInternalServerError("something went wrong")))
+	})
+
+	u.Equal(apperror.StatusClientClosedRequest, u.writer.Code)
+	u.Equal("client_closed_request", problem.Code)
+}
+
+func (u *unitTestErrorsSuite) TestDeadlineExceeded() {
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil), func(ctx *gin.Context) {
+		ctx.Error(context.DeadlineExceeded)
+	})
+
+	u.Equal(http.StatusGatewayTimeout, u.writer.Code)
+	u.Equal("timeout", problem.Code)
+}
+
+func (u *unitTestErrorsSuite) TestPanic() {
+	problem := u.serve(httptest.NewRequest(http.MethodGet, "/books", nil), func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	u.Equal(http.StatusInternalServerError, u.writer.Code)
+	u.Equal("internal_error", problem.Code)
+	u.NotEmpty(problem.RequestId)
+}
+
+func (u *unitTestErrorsSuite) TestNoRoute() {
+	u.router.ServeHTTP(u.writer, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(u.writer.Body.Bytes(), &problem))
+
+	u.Equal(http.StatusNotFound, u.writer.Code)
+	u.Equal("route_not_found", problem.Code)
+}
+
+func (u *unitTestErrorsSuite) TestWrittenResponseKept() {
+	u.router.GET("/books", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"ok": true})
+		ctx.Error(errors.New("late failure"))
+	})
+
+	u.router.ServeHTTP(u.writer, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	u.Equal(http.StatusOK, u.writer.Code)
+	u.JSONEq(`{"ok":true}`, u.writer.Body.String())
+}
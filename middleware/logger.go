@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"gin-go-testing/logger"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger gives every request a logger carrying its request id, method and route, reachable
+// with logger.FromContext from the request context, and logs the request once it is served.
+// It must run after RequestId.
+func Logger(l *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		requestLogger := l.With(
+			"request_id", GetRequestId(ctx),
+			"method", ctx.Request.Method,
+			"route", ctx.FullPath(),
+		)
+		ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context(), requestLogger))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		requestLogger.Log(ctx.Request.Context(), level, "request",
+			"path", ctx.Request.URL.Path,
+			"status", status,
+			"latency", time.Since(start),
+			"client_ip", ctx.ClientIP(),
+			"bytes", ctx.Writer.Size(),
+		)
+	}
+}
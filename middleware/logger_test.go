@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestLoggerSuite struct {
+	suite.Suite
+	out    *bytes.Buffer
+	router *gin.Engine
+}
+
+func TestUnitTestLogger(t *testing.T) {
+	suite.Run(t, &unitTestLoggerSuite{})
+}
+
+func (u *unitTestLoggerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default().Log
+	cfg.Level = "debug"
+
+	u.out = &bytes.Buffer{}
+
+	u.router = gin.New()
+	u.router.Use(RequestId(), Logger(logger.New(&cfg, u.out)), Errors())
+}
+
+// records decodes every line logged while serving the request.
+func (u *unitTestLoggerSuite) records() []map[string]any {
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(u.out.String()), "\n") {
+		var record map[string]any
+		u.Require().NoError(json.Unmarshal([]byte(line), &record))
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func (u *unitTestLoggerSuite) serve(handler gin.HandlerFunc) {
+	u.router.GET("/books/:bookId", handler)
+
+	request := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	request.Header.Set(RequestIdHeader, "req-1")
+
+	u.router.ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func (u *unitTestLoggerSuite) TestRequest_Logged() {
+	u.serve(func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	records := u.records()
+	u.Require().Len(records, 1)
+
+	record := records[0]
+
+	u.Equal("INFO", record["level"])
+	u.Equal("request", record["msg"])
+	u.Equal("req-1", record["request_id"])
+	u.Equal(http.MethodGet, record["method"])
+	u.Equal("/books/:bookId", record["route"])
+	u.Equal("/books/1", record["path"])
+	u.Equal(float64(http.StatusOK), record["status"])
+	u.Equal(float64(2), record["bytes"])
+	u.Contains(record, "latency")
+	u.Contains(record, "source")
+}
+
+func (u *unitTestLoggerSuite) TestRequestContext_CarriesLogger() {
+	u.serve(func(ctx *gin.Context) {
+		logger.FromContext(ctx.Request.Context()).DebugContext(ctx.Request.Context(), "inside handler")
+		ctx.Status(http.StatusNoContent)
+	})
+
+	records := u.records()
+	u.Require().Len(records, 2)
+
+	u.Equal("inside handler", records[0]["msg"])
+	u.Equal("req-1", records[0]["request_id"])
+	u.Equal("/books/:bookId", records[0]["route"])
+}
+
+func (u *unitTestLoggerSuite) TestLevel_FollowsStatus() {
+	u.serve(func(ctx *gin.Context) {
+		ctx.Error(apperror.New(http.StatusNotFound, apperror.CodeNotFound, "data not found"))
+	})
+
+	records := u.records()
+
+	u.Equal("WARN", records[len(records)-1]["level"])
+}
+
+func (u *unitTestLoggerSuite) TestServerError_Logged() {
+	u.serve(func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	records := u.records()
+	u.Require().Len(records, 2)
+
+	u.Equal("panic recovered", records[0]["msg"])
+	u.Equal("boom", records[0]["panic"])
+	u.Equal("req-1", records[0]["request_id"])
+	u.Equal("ERROR", records[1]["level"])
+	u.Equal(float64(http.StatusInternalServerError), records[1]["status"])
+}
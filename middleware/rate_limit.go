@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/logger"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBucket names the bucket of the routes without a limit of their own.
+const defaultBucket = "default"
+
+// meteredKey marks the requests RateLimit took a token for in the gin context.
+const meteredKey = "rate_limit_metered"
+
+// RateLimit meters the requests of every client with the token buckets of store, refusing
+// those over the limit with 429 and a Retry-After header. API keys are told apart by key,
+// bearer tokens by user and anonymous requests by client ip. A route with a limit of its own
+// in cfg.Routes has its own bucket, the other routes share the default one. Requests are let
+// through when the store fails. It must run after Authenticate.
+func RateLimit(store ratelimit.Store, cfg *config.RateLimitConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if meter(ctx, store, cfg, rateLimitClient(ctx)) {
+			ctx.Next()
+		}
+	}
+}
+
+// RateLimitRejected meters the requests Authenticate refuses, which never reach RateLimit,
+// against the buckets of their client ip, the ones anonymous requests use. Once the bucket
+// is empty they answer 429 rather than 401, so guessing credentials is as limited as any
+// other request. It must run between Errors and Authenticate.
+func RateLimitRejected(store ratelimit.Store, cfg *config.RateLimitConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if ctx.GetBool(meteredKey) || !unauthorized(ctx) {
+			return
+		}
+
+		meter(ctx, store, cfg, "ip:"+ctx.ClientIP())
+	}
+}
+
+// meter takes a token for the request from the bucket of client and sets the rate limit
+// headers. It tells whether the request may go on, otherwise it reported a 429 and aborted.
+func meter(ctx *gin.Context, store ratelimit.Store, cfg *config.RateLimitConfig, client string) bool {
+	bucket := ctx.Request.Method + " " + ctx.FullPath()
+
+	limit, ok := cfg.Routes[bucket]
+	if !ok {
+		bucket, limit = defaultBucket, cfg.Default
+	}
+
+	if limit.Requests == 0 {
+		return true
+	}
+
+	ctx.Set(meteredKey, true)
+
+	result, err := store.Take(ctx.Request.Context(), client+"|"+bucket, ratelimit.Limit(limit), time.Now())
+	if err != nil {
+		logger.FromContext(ctx.Request.Context()).WarnContext(ctx.Request.Context(), "rate limit store failed, request let through", "err", err)
+
+		return true
+	}
+
+	ctx.Header("RateLimit-Limit", strconv.FormatUint(uint64(result.Limit), 10))
+	ctx.Header("RateLimit-Remaining", strconv.FormatUint(uint64(result.Remaining), 10))
+	ctx.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Period)))
+
+	if !result.Allowed {
+		retryAfter := max(seconds(result.RetryAfter), 1)
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+
+		ctx.Error(apperror.New(http.StatusTooManyRequests, apperror.CodeRateLimited, fmt.Sprintf("too many requests, retry in %d seconds", retryAfter)).
+			With("retry_after", retryAfter))
+		ctx.Abort()
+
+		return false
+	}
+
+	return true
+}
+
+// unauthorized tells whether the request was refused with 401, the Errors middleware has yet
+// to render it.
+func unauthorized(ctx *gin.Context) bool {
+	if !ctx.IsAborted() || len(ctx.Errors) == 0 {
+		return false
+	}
+
+	var appErr *apperror.Error
+
+	return errors.As(ctx.Errors.Last(), &appErr) && appErr.StatusCode() == http.StatusUnauthorized
+}
+
+// rateLimitClient tells who a request is from: its API key, the user of its bearer token or,
+// for anonymous requests, its client ip.
+func rateLimitClient(ctx *gin.Context) string {
+	principal, ok := auth.PrincipalFrom(ctx.Request.Context())
+
+	switch {
+	case !ok:
+		return "ip:" + ctx.ClientIP()
+	case principal.Method == domain.AuthMethodApiKey:
+		// the subject of an API key already reads api_key:ID
+		return principal.Subject
+	default:
+		return "user:" + principal.Subject
+	}
+}
+
+// seconds rounds d up to whole seconds, the unit of the rate limit headers.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
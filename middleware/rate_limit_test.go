@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeStore is a Store standing in for a shared one such as Redis, it hands out the results
+// of the test and records the keys it is asked for.
+type fakeStore struct {
+	result *ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (f *fakeStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (*ratelimit.Result, error) {
+	f.keys = append(f.keys, key)
+
+	return f.result, f.err
+}
+
+type unitTestRateLimitSuite struct {
+	suite.Suite
+	cfg    *config.RateLimitConfig
+	store  ratelimit.Store
+	router *gin.Engine
+	// principal is the principal of the requests, nil for anonymous ones
+	principal *domain.Principal
+	// rejected makes authentication refuse the requests
+	rejected bool
+}
+
+func TestUnitTestRateLimit(t *testing.T) {
+	suite.Run(t, &unitTestRateLimitSuite{})
+}
+
+func (u *unitTestRateLimitSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.cfg = &config.RateLimitConfig{
+		Default: config.RateLimit{Requests: 2, Period: time.Minute},
+		Routes: map[string]config.RateLimit{
+			"POST /books":        {Requests: 1, Period: time.Hour},
+			"GET /books/:bookId": {},
+		},
+	}
+	u.store = ratelimit.NewMemoryStoreImpl()
+	u.principal = nil
+	u.rejected = false
+
+	authenticate := func(ctx *gin.Context) {
+		if u.rejected {
+			ctx.Error(apperror.New(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "invalid API key"))
+			ctx.Abort()
+
+			return
+		}
+
+		if u.principal != nil {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), u.principal))
+		}
+	}
+
+	// the middleware is made per request, so a test can swap the store
+	rateLimit := func(ctx *gin.Context) {
+		RateLimit(u.store, u.cfg)(ctx)
+	}
+	rateLimitRejected := func(ctx *gin.Context) {
+		RateLimitRejected(u.store, u.cfg)(ctx)
+	}
+
+	u.router = gin.New()
+	u.router.Use(Errors(), rateLimitRejected, authenticate, rateLimit)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/books"},
+		{http.MethodPost, "/books"},
+		{http.MethodGet, "/books/:bookId"},
+		{http.MethodGet, "/authors"},
+	} {
+		u.router.Handle(route.method, route.path, func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+	}
+}
+
+func (u *unitTestRateLimitSuite) serve(method string, target string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	request.RemoteAddr = "203.0.113.7:51234"
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	return writer
+}
+
+func (u *unitTestRateLimitSuite) TestDefault_SharedByRoutes() {
+	writer := u.serve(http.MethodGet, "/books")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal("2", writer.Header().Get("RateLimit-Limit"))
+	u.Equal("1", writer.Header().Get("RateLimit-Remaining"))
+	u.Equal("30", writer.Header().Get("RateLimit-Reset"))
+	u.Equal("2;w=60", writer.Header().Get("RateLimit-Policy"))
+
+	u.Equal(http.StatusOK, u.serve(http.MethodGet, "/authors").Code)
+
+	writer = u.serve(http.MethodGet, "/books")
+
+	var problem map[string]any
+	u.NoError(json.Unmarshal(writer.Body.Bytes(), &problem))
+
+	u.Equal(http.StatusTooManyRequests, writer.Code)
+	u.Equal("30", writer.Header().Get("Retry-After"))
+	u.Equal("0", writer.Header().Get("RateLimit-Remaining"))
+	u.Equal("rate_limited", problem["code"])
+	u.Equal(float64(30), problem["retry_after"])
+}
+
+func (u *unitTestRateLimitSuite) TestRoute_OwnLimit() {
+	u.Equal(http.StatusOK, u.serve(http.MethodPost, "/books").Code)
+	u.Equal(http.StatusTooManyRequests, u.serve(http.MethodPost, "/books").Code)
+
+	// the default bucket is untouched
+	u.Equal("1", u.serve(http.MethodGet, "/books").Header().Get("RateLimit-Remaining"))
+
+	// a route without requests is not limited
+	for i := 0; i < 5; i++ {
+		writer := u.serve(http.MethodGet, "/books/1")
+
+		u.Equal(http.StatusOK, writer.Code)
+		u.Empty(writer.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func (u *unitTestRateLimitSuite) TestKeys_ApiKeyUserOrIp() {
+	store := &fakeStore{result: &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1}}
+	u.store = store
+
+	u.serve(http.MethodGet, "/books")
+
+	u.principal = &domain.Principal{Subject: "api_key:3", Method: domain.AuthMethodApiKey}
+	u.serve(http.MethodPost, "/books")
+
+	u.principal = &domain.Principal{Subject: "user-1", Method: domain.AuthMethodJWT}
+	u.serve(http.MethodGet, "/authors")
+
+	u.Equal([]string{"ip:203.0.113.7|default", "api_key:3|POST /books", "user:user-1|default"}, store.keys)
+}
+
+func (u *unitTestRateLimitSuite) TestStoreFailure_LetsThrough() {
+	u.store = &fakeStore{err: errors.New("connection refused")}
+
+	writer := u.serve(http.MethodGet, "/books")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Empty(writer.Header().Get("RateLimit-Limit"))
+}
+
+func (u *unitTestRateLimitSuite) TestRejected_MeteredByIp() {
+	u.rejected = true
+
+	writer := u.serve(http.MethodGet, "/books")
+
+	u.Equal(http.StatusUnauthorized, writer.Code)
+	u.Equal("1", writer.Header().Get("RateLimit-Remaining"))
+
+	u.Equal(http.StatusUnauthorized, u.serve(http.MethodGet, "/authors").Code)
+
+	writer = u.serve(http.MethodGet, "/books")
+
+	u.Equal(http.StatusTooManyRequests, writer.Code)
+	u.Equal("30", writer.Header().Get("Retry-After"))
+
+	// anonymous requests of the same ip share the bucket
+	u.rejected = false
+	u.Equal(http.StatusTooManyRequests, u.serve(http.MethodGet, "/books").Code)
+}
+
+func (u *unitTestRateLimitSuite) TestRejected_MeteredOnce() {
+	store := &fakeStore{result: &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1}}
+	u.store = store
+
+	u.rejected = true
+	u.serve(http.MethodGet, "/books")
+
+	// refused after RateLimit took its token, by a policy
+	u.rejected = false
+	u.router.GET("/tags", func(ctx *gin.Context) {
+		ctx.Error(apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "authentication required"))
+		ctx.Abort()
+	})
+	u.serve(http.MethodGet, "/tags")
+
+	u.Equal([]string{"ip:203.0.113.7|default", "ip:203.0.113.7|default"}, store.keys)
+}
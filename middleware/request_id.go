@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIdHeader = "X-Request-Id"
+
+const requestIdKey = "request_id"
+
+// requestIdPattern keeps ids coming from clients or proxies safe to log and echo back.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestId tags every request with the incoming X-Request-Id, or a random one, and
+// echoes it in the response.
+func RequestId() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(RequestIdHeader)
+		if !requestIdPattern.MatchString(requestId) {
+			requestId = newRequestId()
+		}
+
+		ctx.Set(requestIdKey, requestId)
+		ctx.Header(RequestIdHeader, requestId)
+
+		ctx.Next()
+	}
+}
+
+// GetRequestId returns the id RequestId gave the request, empty when it did not run.
+func GetRequestId(ctx *gin.Context) string {
+	return ctx.GetString(requestIdKey)
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestRequestIdSuite struct {
+	suite.Suite
+	router    *gin.Engine
+	requestId string
+}
+
+func TestUnitTestRequestId(t *testing.T) {
+	suite.Run(t, &unitTestRequestIdSuite{})
+}
+
+func (u *unitTestRequestIdSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.router = gin.New()
+	u.router.Use(RequestId())
+	u.router.GET("/", func(ctx *gin.Context) {
+		u.requestId = GetRequestId(ctx)
+	})
+}
+
+func (u *unitTestRequestIdSuite) TestKeepsIncomingId() {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(RequestIdHeader, "abc-123")
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	u.Equal("abc-123", u.requestId)
+	u.Equal("abc-123", writer.Header().Get(RequestIdHeader))
+}
+
+func (u *unitTestRequestIdSuite) TestGeneratesId() {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(RequestIdHeader, "not a valid\nid")
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	u.Len(u.requestId, 32)
+	u.Equal(u.requestId, writer.Header().Get(RequestIdHeader))
+}
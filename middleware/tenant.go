@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/tenant"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// Tenant attaches the tenant a request acts for to the request context, see
+// tenant.FromContext. It is taken from the configured header, the subdomain of the host or
+// the tenant the credentials are bound to. Requests naming no valid tenant are refused, so
+// are requests with credentials bound to no tenant or to another one than they name. Without
+// a source every request acts for tenant.Default. It must run after Authenticate.
+func Tenant(cfg *config.TenancyConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := resolveTenant(ctx, cfg)
+
+		if err != nil {
+			if err.StatusCode() == http.StatusUnauthorized {
+				ctx.Header("WWW-Authenticate", authenticateChallenge)
+			}
+
+			ctx.Error(apperror.From(err))
+			ctx.Abort()
+
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(tenant.WithContext(ctx.Request.Context(), id))
+
+		ctx.Next()
+	}
+}
+
+// resolveTenant returns the tenant of the request from the configured source, checked
+// against the tenant the credentials of the request are bound to. Anonymous requests may
+// name any tenant, the policy decides what they can do there.
+func resolveTenant(ctx *gin.Context, cfg *config.TenancyConfig) (string, errs.CustomError) {
+	principal, authenticated := auth.PrincipalFrom(ctx.Request.Context())
+
+	var id string
+
+	switch cfg.Source {
+	case "":
+		return tenant.Default, nil
+	case "header":
+		if id = ctx.GetHeader(cfg.Header); id == "" {
+			return "", apperror.New(http.StatusBadRequest, apperror.CodeInvalidTenant, fmt.Sprintf("the tenant is required, send it in the %s header", cfg.Header))
+		}
+	case "subdomain":
+		var ok bool
+		if id, ok = subdomain(ctx.Request.Host, cfg.Domain); !ok {
+			return "", apperror.New(http.StatusBadRequest, apperror.CodeInvalidTenant, fmt.Sprintf("the tenant is required, make the request to a subdomain of %s", cfg.Domain))
+		}
+	case "claim":
+		if !authenticated {
+			return "", apperror.New(http.StatusUnauthorized, apperror.CodeUnauthorized, "authentication required, the tenant is taken from the bearer token")
+		}
+
+		id = principal.Tenant
+	}
+
+	// credentials bound to no tenant would act for whichever one the request names
+	if authenticated && principal.Tenant == "" {
+		return "", apperror.New(http.StatusForbidden, apperror.CodeForbidden, "the credentials are not bound to a tenant")
+	}
+
+	if !tenant.Valid(id) {
+		return "", apperror.New(http.StatusBadRequest, apperror.CodeInvalidTenant, fmt.Sprintf("%q is not a valid tenant id", id))
+	}
+
+	if authenticated && principal.Tenant != id {
+		return "", apperror.New(http.StatusForbidden, apperror.CodeForbidden, "the credentials are bound to another tenant")
+	}
+
+	return id, nil
+}
+
+// subdomain returns the label host has right under domain, so acme for acme.example.com
+// under example.com. Hosts further down or outside of domain have none.
+func subdomain(host string, domain string) (string, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(domain))
+
+	return label, ok && label != "" && !strings.Contains(label, ".")
+}
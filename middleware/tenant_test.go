@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"encoding/json"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/tenant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTenantSuite struct {
+	suite.Suite
+	cfg    *config.TenancyConfig
+	router *gin.Engine
+	// principal is the principal of the requests, nil for anonymous ones
+	principal *domain.Principal
+	tenant    string
+}
+
+func TestUnitTestTenant(t *testing.T) {
+	suite.Run(t, &unitTestTenantSuite{})
+}
+
+func (u *unitTestTenantSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.cfg = &config.Default().Tenancy
+	u.principal = nil
+	u.tenant = ""
+
+	authenticate := func(ctx *gin.Context) {
+		if u.principal != nil {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), u.principal))
+		}
+	}
+
+	u.router = gin.New()
+	u.router.Use(Errors(), authenticate, Tenant(u.cfg))
+	u.router.GET("/books", func(ctx *gin.Context) {
+		u.tenant = tenant.FromContext(ctx.Request.Context())
+		ctx.Status(http.StatusOK)
+	})
+}
+
+func (u *unitTestTenantSuite) serve(host string, header string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "http://"+host+"/books", nil)
+	if header != "" {
+		request.Header.Set("X-Tenant-Id", header)
+	}
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	return writer
+}
+
+func (u *unitTestTenantSuite) code(writer *httptest.ResponseRecorder) string {
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(writer.Body.Bytes(), &problem))
+
+	return problem.Code
+}
+
+func (u *unitTestTenantSuite) TestNoSource_Default() {
+	writer := u.serve("books.example.com", "acme")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal(tenant.Default, u.tenant)
+}
+
+func (u *unitTestTenantSuite) TestHeader() {
+	u.cfg.Source = "header"
+
+	writer := u.serve("books.example.com", "acme")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal("acme", u.tenant)
+}
+
+func (u *unitTestTenantSuite) TestHeader_Missing() {
+	u.cfg.Source = "header"
+
+	writer := u.serve("books.example.com", "")
+
+	u.Equal(http.StatusBadRequest, writer.Code)
+	u.Equal("invalid_tenant", u.code(writer))
+}
+
+func (u *unitTestTenantSuite) TestHeader_Invalid() {
+	u.cfg.Source = "header"
+
+	writer := u.serve("books.example.com", "../acme")
+
+	u.Equal(http.StatusBadRequest, writer.Code)
+	u.Equal("invalid_tenant", u.code(writer))
+}
+
+func (u *unitTestTenantSuite) TestSubdomain() {
+	u.cfg.Source = "subdomain"
+	u.cfg.Domain = "books.example.com"
+
+	writer := u.serve("Acme.Books.Example.com:8080", "")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal("acme", u.tenant)
+
+	for _, host := range []string{"books.example.com", "eu.acme.books.example.com", "acme.example.org"} {
+		writer = u.serve(host, "")
+
+		u.Equal(http.StatusBadRequest, writer.Code, host)
+	}
+}
+
+func (u *unitTestTenantSuite) TestClaim() {
+	u.cfg.Source = "claim"
+	u.principal = &domain.Principal{Subject: "user-1", Tenant: "acme"}
+
+	writer := u.serve("books.example.com", "")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal("acme", u.tenant)
+}
+
+func (u *unitTestTenantSuite) TestClaim_Unbound() {
+	u.cfg.Source = "claim"
+	u.principal = &domain.Principal{Subject: "api_key:3"}
+
+	writer := u.serve("books.example.com", "")
+
+	u.Equal(http.StatusForbidden, writer.Code)
+
+	u.principal = nil
+	writer = u.serve("books.example.com", "")
+
+	u.Equal(http.StatusUnauthorized, writer.Code)
+	u.Equal(`Bearer realm="books"`, writer.Header().Get("WWW-Authenticate"))
+}
+
+func (u *unitTestTenantSuite) TestBoundToAnotherTenant() {
+	u.cfg.Source = "header"
+	u.principal = &domain.Principal{Subject: "user-1", Tenant: "acme"}
+
+	writer := u.serve("books.example.com", "globex")
+
+	u.Equal(http.StatusForbidden, writer.Code)
+	u.Equal("forbidden", u.code(writer))
+	u.Empty(u.tenant)
+}
+
+func (u *unitTestTenantSuite) TestUnbound() {
+	u.cfg.Source = "header"
+	u.principal = &domain.Principal{Subject: "user-1"}
+
+	writer := u.serve("books.example.com", "acme")
+
+	u.Equal(http.StatusForbidden, writer.Code)
+	u.Equal("forbidden", u.code(writer))
+	u.Empty(u.tenant)
+
+	// anonymous requests are left to the policy
+	u.principal = nil
+	writer = u.serve("books.example.com", "acme")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal("acme", u.tenant)
+}
+
+func (u *unitTestTenantSuite) TestNoSource_Unbound() {
+	u.principal = &domain.Principal{Subject: "user-1"}
+
+	writer := u.serve("books.example.com", "")
+
+	u.Equal(http.StatusOK, writer.Code)
+	u.Equal(tenant.Default, u.tenant)
+}
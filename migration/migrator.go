@@ -0,0 +1,249 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// lockId is the advisory lock every Postgres instance takes before touching the schema.
+const lockId = 7245190211
+
+type dialect struct {
+	createTableQuery string
+	findAppliedQuery string
+	insertQuery      string
+	deleteQuery      string
+	lockQuery        string
+	unlockQuery      string
+}
+
+var dialects = map[string]*dialect{
+	"postgres": {
+		createTableQuery: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`,
+		findAppliedQuery: `SELECT version, applied_at FROM schema_migrations`,
+		insertQuery:      `INSERT INTO schema_migrations(version, name) VALUES($1,$2)`,
+		deleteQuery:      `DELETE FROM schema_migrations WHERE version=$1`,
+		lockQuery:        `SELECT pg_advisory_lock($1)`,
+		unlockQuery:      `SELECT pg_advisory_unlock($1)`,
+	},
+	// SQLite allows a single writer per database file, a migration applied concurrently
+	// fails on the schema_migrations primary key and is rolled back.
+	"sqlite": {
+		createTableQuery: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+		findAppliedQuery: `SELECT version, applied_at FROM schema_migrations`,
+		insertQuery:      `INSERT INTO schema_migrations(version, name) VALUES(?,?)`,
+		deleteQuery:      `DELETE FROM schema_migrations WHERE version=?`,
+	},
+}
+
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    *dialect
+	migrations []*Migration
+}
+
+// New creates a migrator for a "postgres" or "sqlite" database.
+func New(db *sql.DB, driver string, source fs.FS) (*Migrator, error) {
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported migration driver %q", driver)
+	}
+
+	migrations, err := load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn, applied map[uint]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				return m.revert(ctx, conn, m.migrations[i])
+			}
+		}
+
+		return nil
+	})
+}
+
+// To applies or reverts migrations until the schema is at version, 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn, applied map[uint]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; ok && migration.Version > version {
+				if err := m.revert(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+				if err := m.apply(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status lists every known migration and when it was applied, nil when it is pending.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	statuses := []*Status{}
+
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[uint]time.Time) error {
+		for _, migration := range m.migrations {
+			status := &Status{Version: migration.Version, Name: migration.Name}
+
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) find(version uint) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+
+	return nil
+}
+
+// withLock runs fn on a single connection holding the migration lock, so instances
+// starting at the same time apply each migration exactly once.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, applied map[uint]time.Time) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.lockQuery != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.lockQuery, lockId); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+
+		defer func() {
+			if _, errUnlock := conn.ExecContext(context.Background(), m.dialect.unlockQuery, lockId); errUnlock != nil && err == nil {
+				err = fmt.Errorf("release migration lock: %w", errUnlock)
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createTableQuery); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied, err := m.findApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
+
+func (m *Migrator) findApplied(ctx context.Context, conn *sql.Conn) (map[uint]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, m.dialect.findAppliedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[uint]time.Time{}
+
+	for rows.Next() {
+		var version uint
+		var appliedAt time.Time
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("read schema_migrations: %w", err)
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err := tx.ExecContext(ctx, m.dialect.insertQuery, migration.Version, migration.Name)
+		return err
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err := tx.ExecContext(ctx, m.dialect.deleteQuery, migration.Version)
+		return err
+	})
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,181 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestMigratorSuite struct {
+	suite.Suite
+	migrator *Migrator
+	mock     sqlmock.Sqlmock
+	db       *sql.DB
+	ctx      context.Context
+}
+
+func TestUnitTestMigrator(t *testing.T) {
+	suite.Run(t, &unitTestMigratorSuite{})
+}
+
+func (u *unitTestMigratorSuite) SetupTest() {
+	source := fstest.MapFS{
+		"0001_create_books.up.sql":     {Data: []byte("CREATE TABLE books")},
+		"0001_create_books.down.sql":   {Data: []byte("DROP TABLE books")},
+		"0002_add_isbn.up.sql":         {Data: []byte("ALTER TABLE books ADD isbn")},
+		"0002_add_isbn.down.sql":       {Data: []byte("ALTER TABLE books DROP isbn")},
+		"README.md":                    {Data: []byte("not a migration")},
+		"0003_create_authors.up.sql":   {Data: []byte("CREATE TABLE authors")},
+		"0003_create_authors.down.sql": {Data: []byte("DROP TABLE authors")},
+	}
+
+	db, mock, _ := sqlmock.New()
+	migrator, err := New(db, "postgres", source)
+	u.Require().NoError(err)
+
+	u.migrator = migrator
+	u.mock = mock
+	u.db = db
+	u.ctx = context.Background()
+}
+
+func (u *unitTestMigratorSuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestMigratorSuite) expectLock(applied ...uint) {
+	u.mock.ExpectExec(regexp.QuoteMeta(dialects["postgres"].lockQuery)).WithArgs(lockId).WillReturnResult(sqlmock.NewResult(0, 0))
+	u.mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	}
+
+	u.mock.ExpectQuery(regexp.QuoteMeta(dialects["postgres"].findAppliedQuery)).WillReturnRows(rows)
+}
+
+func (u *unitTestMigratorSuite) expectUnlock() {
+	u.mock.ExpectExec(regexp.QuoteMeta(dialects["postgres"].unlockQuery)).WithArgs(lockId).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (u *unitTestMigratorSuite) expectApply(version uint, name, statement string) {
+	u.mock.ExpectBegin()
+	u.mock.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	u.mock.ExpectExec(regexp.QuoteMeta(dialects["postgres"].insertQuery)).WithArgs(version, name).WillReturnResult(sqlmock.NewResult(0, 1))
+	u.mock.ExpectCommit()
+}
+
+func (u *unitTestMigratorSuite) expectRevert(version uint, statement string) {
+	u.mock.ExpectBegin()
+	u.mock.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	u.mock.ExpectExec(regexp.QuoteMeta(dialects["postgres"].deleteQuery)).WithArgs(version).WillReturnResult(sqlmock.NewResult(0, 1))
+	u.mock.ExpectCommit()
+}
+
+func (u *unitTestMigratorSuite) TestUp_AppliesPendingInOrder() {
+	u.expectLock(1)
+	u.expectApply(2, "add_isbn", "ALTER TABLE books ADD isbn")
+	u.expectApply(3, "create_authors", "CREATE TABLE authors")
+	u.expectUnlock()
+
+	err := u.migrator.Up(u.ctx)
+
+	u.NoError(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestUp_FailedMigrationIsRolledBack() {
+	u.expectLock()
+	u.mock.ExpectBegin()
+	u.mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE books")).WillReturnError(sql.ErrConnDone)
+	u.mock.ExpectRollback()
+	u.expectUnlock()
+
+	err := u.migrator.Up(u.ctx)
+
+	u.ErrorContains(err, "apply migration 1_create_books")
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestDown_RevertsLatest() {
+	u.expectLock(1, 2)
+	u.expectRevert(2, "ALTER TABLE books DROP isbn")
+	u.expectUnlock()
+
+	err := u.migrator.Down(u.ctx)
+
+	u.NoError(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestTo_RevertsNewerVersions() {
+	u.expectLock(1, 2, 3)
+	u.expectRevert(3, "DROP TABLE authors")
+	u.expectRevert(2, "ALTER TABLE books DROP isbn")
+	u.expectUnlock()
+
+	err := u.migrator.To(u.ctx, 1)
+
+	u.NoError(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestTo_UnknownVersion() {
+	err := u.migrator.To(u.ctx, 9)
+
+	u.ErrorIs(err, ErrUnknownVersion)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestStatus() {
+	u.expectLock(1)
+	u.expectUnlock()
+
+	statuses, err := u.migrator.Status(u.ctx)
+
+	u.NoError(err)
+	u.Len(statuses, 3)
+	u.Equal("create_books", statuses[0].Name)
+	u.NotNil(statuses[0].AppliedAt)
+	u.Nil(statuses[1].AppliedAt)
+	u.Nil(statuses[2].AppliedAt)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestMigratorSuite) TestNew_MissingDownFile() {
+	source := fstest.MapFS{
+		"0001_create_books.up.sql": {Data: []byte("CREATE TABLE books")},
+	}
+
+	migrator, err := New(u.db, "postgres", source)
+
+	u.Nil(migrator)
+	u.ErrorContains(err, "needs both an up and a down file")
+}
+
+func (u *unitTestMigratorSuite) TestSource_Embedded() {
+	for driver := range dialects {
+		source, err := Source(driver)
+		u.Require().NoError(err)
+
+		migrations, err := load(source)
+
+		u.NoError(err)
+		u.NotEmpty(migrations, driver)
+		u.Equal(uint(1), migrations[0].Version)
+	}
+}
+
+func (u *unitTestMigratorSuite) TestSource_UnknownDriver() {
+	source, err := Source("oracle")
+
+	u.Nil(source)
+	u.Error(err)
+}
@@ -0,0 +1,8 @@
+ALTER TABLE books
+    DROP COLUMN isbn,
+    DROP COLUMN publication_year,
+    DROP COLUMN publisher,
+    DROP COLUMN language,
+    DROP COLUMN page_count,
+    DROP COLUMN description,
+    DROP COLUMN cover_url;
@@ -0,0 +1,8 @@
+ALTER TABLE books
+    ADD COLUMN isbn VARCHAR(13),
+    ADD COLUMN publication_year INTEGER,
+    ADD COLUMN publisher VARCHAR(255),
+    ADD COLUMN language VARCHAR(35),
+    ADD COLUMN page_count INTEGER,
+    ADD COLUMN description TEXT,
+    ADD COLUMN cover_url VARCHAR(2048);
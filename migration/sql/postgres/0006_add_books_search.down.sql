@@ -0,0 +1,3 @@
+ALTER TABLE books
+    DROP COLUMN search_vector,
+    DROP COLUMN search_text;
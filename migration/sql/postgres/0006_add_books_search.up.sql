@@ -0,0 +1,15 @@
+-- search_vector weighs matches in the title above the author above the description;
+-- search_text backs the typo tolerant trigram match of the same fields
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE books
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', author), 'B') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'C')
+    ) STORED,
+    ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+        lower(title || ' ' || author || ' ' || coalesce(description, ''))
+    ) STORED;
+CREATE INDEX books_search_vector_idx ON books USING GIN (search_vector);
+CREATE INDEX books_search_text_idx ON books USING GIN (search_text gin_trgm_ops);
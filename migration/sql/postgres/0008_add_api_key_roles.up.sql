@@ -0,0 +1,2 @@
+-- roles are comma separated, e.g. reader,editor
+ALTER TABLE api_keys ADD COLUMN roles VARCHAR(200) NOT NULL DEFAULT '';
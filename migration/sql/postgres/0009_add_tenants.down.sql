@@ -0,0 +1,12 @@
+-- fails when two tenants share a name or a dedupe key
+DROP INDEX tags_tenant_id_name_key_idx;
+CREATE UNIQUE INDEX tags_name_key_idx ON tags (name_key);
+ALTER TABLE tags DROP COLUMN tenant_id;
+
+DROP INDEX authors_tenant_id_name_key_idx;
+CREATE UNIQUE INDEX authors_name_key_idx ON authors (name_key);
+ALTER TABLE authors DROP COLUMN tenant_id;
+
+DROP INDEX books_tenant_id_dedupe_key_idx;
+CREATE UNIQUE INDEX books_dedupe_key_idx ON books (dedupe_key);
+ALTER TABLE books DROP COLUMN tenant_id;
@@ -0,0 +1,13 @@
+-- tenant_id is the tenant owning a book, author or tag, rows written before tenancy belong to
+-- the default tenant; names and dedupe keys only have to be unique within a tenant
+ALTER TABLE books ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+DROP INDEX books_dedupe_key_idx;
+CREATE UNIQUE INDEX books_tenant_id_dedupe_key_idx ON books (tenant_id, dedupe_key);
+
+ALTER TABLE authors ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+DROP INDEX authors_name_key_idx;
+CREATE UNIQUE INDEX authors_tenant_id_name_key_idx ON authors (tenant_id, name_key);
+
+ALTER TABLE tags ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+DROP INDEX tags_name_key_idx;
+CREATE UNIQUE INDEX tags_tenant_id_name_key_idx ON tags (tenant_id, name_key);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS books_dedupe_key_idx;
+ALTER TABLE books DROP COLUMN dedupe_key;
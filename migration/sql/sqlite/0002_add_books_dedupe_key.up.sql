@@ -0,0 +1,3 @@
+-- dedupe_key is the normalized form of what makes a book unique, NULL when books are not deduplicated
+ALTER TABLE books ADD COLUMN dedupe_key VARCHAR(512);
+CREATE UNIQUE INDEX books_dedupe_key_idx ON books (dedupe_key);
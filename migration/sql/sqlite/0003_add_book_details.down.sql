@@ -0,0 +1,7 @@
+ALTER TABLE books DROP COLUMN isbn;
+ALTER TABLE books DROP COLUMN publication_year;
+ALTER TABLE books DROP COLUMN publisher;
+ALTER TABLE books DROP COLUMN language;
+ALTER TABLE books DROP COLUMN page_count;
+ALTER TABLE books DROP COLUMN description;
+ALTER TABLE books DROP COLUMN cover_url;
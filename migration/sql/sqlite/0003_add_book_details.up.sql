@@ -0,0 +1,7 @@
+ALTER TABLE books ADD COLUMN isbn VARCHAR(13);
+ALTER TABLE books ADD COLUMN publication_year INTEGER;
+ALTER TABLE books ADD COLUMN publisher VARCHAR(255);
+ALTER TABLE books ADD COLUMN language VARCHAR(35);
+ALTER TABLE books ADD COLUMN page_count INTEGER;
+ALTER TABLE books ADD COLUMN description TEXT;
+ALTER TABLE books ADD COLUMN cover_url VARCHAR(2048);
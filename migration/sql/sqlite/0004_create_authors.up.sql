@@ -0,0 +1,30 @@
+-- name_key is the name lowercased without spaces and dots, so "J.R.R. Tolkien" and
+-- "J. R. R. Tolkien" are the same author
+CREATE TABLE IF NOT EXISTS authors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    name_key VARCHAR(255) NOT NULL
+);
+CREATE UNIQUE INDEX authors_name_key_idx ON authors (name_key);
+
+-- position orders the authors of a book in its byline
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES authors (id),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (book_id, author_id)
+);
+CREATE INDEX book_authors_author_id_idx ON book_authors (author_id);
+
+-- every spelling of an existing author string becomes one author, named after the first
+-- spelling in alphabetical order; the books keep their byline until they are next written
+INSERT INTO authors (name, name_key)
+SELECT MIN(author), name_key
+FROM (SELECT author, LOWER(REPLACE(REPLACE(author, ' ', ''), '.', '')) AS name_key FROM books) spellings
+GROUP BY name_key
+ORDER BY MIN(author);
+
+INSERT INTO book_authors (book_id, author_id, position)
+SELECT books.id, authors.id, 0
+FROM books
+JOIN authors ON authors.name_key = LOWER(REPLACE(REPLACE(books.author, ' ', ''), '.', ''));
@@ -0,0 +1,17 @@
+-- name_key is the name lowercased with its whitespace collapsed, books are filtered by tag name;
+-- parent_id nests genres, e.g. Fantasy under Fiction
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    name_key VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES tags (id)
+);
+CREATE UNIQUE INDEX tags_name_key_idx ON tags (name_key);
+CREATE INDEX tags_parent_id_idx ON tags (parent_id);
+
+CREATE TABLE IF NOT EXISTS book_tags (
+    book_id INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+CREATE INDEX book_tags_tag_id_idx ON book_tags (tag_id);
@@ -0,0 +1,3 @@
+-- SQLite books are searched by the in-memory index the server builds at startup, the
+-- migration only keeps the versions in step with Postgres
+SELECT 1;
@@ -0,0 +1,10 @@
+-- key_hash is the hex SHA-256 of the key, the key itself is never stored
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+CREATE UNIQUE INDEX api_keys_key_hash_idx ON api_keys (key_hash);
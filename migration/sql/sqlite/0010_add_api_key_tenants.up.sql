@@ -0,0 +1,2 @@
+-- tenant_id is the tenant a key acts for, keys created before tenancy act for the default tenant
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
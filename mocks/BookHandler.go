@@ -13,11 +13,21 @@ type BookHandler struct {
 	mock.Mock
 }
 
+// AddTag provides a mock function with given fields: ctx
+func (_m *BookHandler) AddTag(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
 // Create provides a mock function with given fields: ctx
 func (_m *BookHandler) Create(ctx *gin.Context) {
 	_m.Called(ctx)
 }
 
+// Delete provides a mock function with given fields: ctx
+func (_m *BookHandler) Delete(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
 // FindAll provides a mock function with given fields: ctx
 func (_m *BookHandler) FindAll(ctx *gin.Context) {
 	_m.Called(ctx)
@@ -28,6 +38,26 @@ func (_m *BookHandler) FindOneById(ctx *gin.Context) {
 	_m.Called(ctx)
 }
 
+// Patch provides a mock function with given fields: ctx
+func (_m *BookHandler) Patch(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
+// RemoveTag provides a mock function with given fields: ctx
+func (_m *BookHandler) RemoveTag(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
+// Search provides a mock function with given fields: ctx
+func (_m *BookHandler) Search(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
+// Update provides a mock function with given fields: ctx
+func (_m *BookHandler) Update(ctx *gin.Context) {
+	_m.Called(ctx)
+}
+
 // NewBookHandler creates a new instance of BookHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookHandler(t interface {
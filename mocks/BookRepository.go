@@ -3,15 +3,15 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "gin-go-testing/model/domain"
 
 	errs "github.com/rulyadhika/go-custom-err/errs"
 
-	gin "github.com/gin-gonic/gin"
+	repository "gin-go-testing/repository"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // BookRepository is an autogenerated mock type for the BookRepository type
@@ -19,8 +19,90 @@ type BookRepository struct {
 	mock.Mock
 }
 
+// AddTag provides a mock function with given fields: ctx, db, bookId, tagId
+func (_m *BookRepository) AddTag(ctx context.Context, db repository.DBTX, bookId uint, tagId uint) errs.CustomError {
+	ret := _m.Called(ctx, db, bookId, tagId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTag")
+	}
+
+	var r0 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint, uint) errs.CustomError); ok {
+		r0 = rf(ctx, db, bookId, tagId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errs.CustomError)
+		}
+	}
+
+	return r0
+}
+
+// Count provides a mock function with given fields: ctx, db, params
+func (_m *BookRepository) Count(ctx context.Context, db repository.DBTX, params *domain.BookListParams) (uint, errs.CustomError) {
+	ret := _m.Called(ctx, db, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 uint
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) (uint, errs.CustomError)); ok {
+		return rf(ctx, db, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) uint); ok {
+		r0 = rf(ctx, db, params)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.BookListParams) errs.CustomError); ok {
+		r1 = rf(ctx, db, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CountTags provides a mock function with given fields: ctx, db, params
+func (_m *BookRepository) CountTags(ctx context.Context, db repository.DBTX, params *domain.BookListParams) (map[uint]uint, errs.CustomError) {
+	ret := _m.Called(ctx, db, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTags")
+	}
+
+	var r0 map[uint]uint
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) (map[uint]uint, errs.CustomError)); ok {
+		return rf(ctx, db, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) map[uint]uint); ok {
+		r0 = rf(ctx, db, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.BookListParams) errs.CustomError); ok {
+		r1 = rf(ctx, db, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, db, book
-func (_m *BookRepository) Create(ctx *gin.Context, db *sql.DB, book *domain.Book) (*domain.Book, errs.CustomError) {
+func (_m *BookRepository) Create(ctx context.Context, db repository.DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
 	ret := _m.Called(ctx, db, book)
 
 	if len(ret) == 0 {
@@ -29,10 +111,10 @@ func (_m *BookRepository) Create(ctx *gin.Context, db *sql.DB, book *domain.Book
 
 	var r0 *domain.Book
 	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB, *domain.Book) (*domain.Book, errs.CustomError)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) (*domain.Book, errs.CustomError)); ok {
 		return rf(ctx, db, book)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB, *domain.Book) *domain.Book); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) *domain.Book); ok {
 		r0 = rf(ctx, db, book)
 	} else {
 		if ret.Get(0) != nil {
@@ -40,7 +122,7 @@ func (_m *BookRepository) Create(ctx *gin.Context, db *sql.DB, book *domain.Book
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, *sql.DB, *domain.Book) errs.CustomError); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.Book) errs.CustomError); ok {
 		r1 = rf(ctx, db, book)
 	} else {
 		if ret.Get(1) != nil {
@@ -51,9 +133,29 @@ func (_m *BookRepository) Create(ctx *gin.Context, db *sql.DB, book *domain.Book
 	return r0, r1
 }
 
-// FindAll provides a mock function with given fields: ctx, db
-func (_m *BookRepository) FindAll(ctx *gin.Context, db *sql.DB) ([]*domain.Book, errs.CustomError) {
-	ret := _m.Called(ctx, db)
+// Delete provides a mock function with given fields: ctx, db, bookId
+func (_m *BookRepository) Delete(ctx context.Context, db repository.DBTX, bookId uint) errs.CustomError {
+	ret := _m.Called(ctx, db, bookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint) errs.CustomError); ok {
+		r0 = rf(ctx, db, bookId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errs.CustomError)
+		}
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields: ctx, db, params
+func (_m *BookRepository) FindAll(ctx context.Context, db repository.DBTX, params *domain.BookListParams) ([]*domain.Book, errs.CustomError) {
+	ret := _m.Called(ctx, db, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAll")
@@ -61,19 +163,90 @@ func (_m *BookRepository) FindAll(ctx *gin.Context, db *sql.DB) ([]*domain.Book,
 
 	var r0 []*domain.Book
 	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB) ([]*domain.Book, errs.CustomError)); ok {
-		return rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) ([]*domain.Book, errs.CustomError)); ok {
+		return rf(ctx, db, params)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB) []*domain.Book); ok {
-		r0 = rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams) []*domain.Book); ok {
+		r0 = rf(ctx, db, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Book)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, *sql.DB) errs.CustomError); ok {
-		r1 = rf(ctx, db)
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.BookListParams) errs.CustomError); ok {
+		r1 = rf(ctx, db, params)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FindAllByCursor provides a mock function with given fields: ctx, db, params, cursor
+func (_m *BookRepository) FindAllByCursor(ctx context.Context, db repository.DBTX, params *domain.BookListParams, cursor string) ([]*domain.Book, string, errs.CustomError) {
+	ret := _m.Called(ctx, db, params, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByCursor")
+	}
+
+	var r0 []*domain.Book
+	var r1 string
+	var r2 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams, string) ([]*domain.Book, string, errs.CustomError)); ok {
+		return rf(ctx, db, params, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.BookListParams, string) []*domain.Book); ok {
+		r0 = rf(ctx, db, params, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.BookListParams, string) string); ok {
+		r1 = rf(ctx, db, params, cursor)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.DBTX, *domain.BookListParams, string) errs.CustomError); ok {
+		r2 = rf(ctx, db, params, cursor)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errs.CustomError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// FindDuplicate provides a mock function with given fields: ctx, db, book
+func (_m *BookRepository) FindDuplicate(ctx context.Context, db repository.DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	ret := _m.Called(ctx, db, book)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDuplicate")
+	}
+
+	var r0 *domain.Book
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) (*domain.Book, errs.CustomError)); ok {
+		return rf(ctx, db, book)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) *domain.Book); ok {
+		r0 = rf(ctx, db, book)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.Book) errs.CustomError); ok {
+		r1 = rf(ctx, db, book)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(errs.CustomError)
@@ -84,7 +257,7 @@ func (_m *BookRepository) FindAll(ctx *gin.Context, db *sql.DB) ([]*domain.Book,
 }
 
 // FindOneById provides a mock function with given fields: ctx, db, bookId
-func (_m *BookRepository) FindOneById(ctx *gin.Context, db *sql.DB, bookId uint) (*domain.Book, errs.CustomError) {
+func (_m *BookRepository) FindOneById(ctx context.Context, db repository.DBTX, bookId uint) (*domain.Book, errs.CustomError) {
 	ret := _m.Called(ctx, db, bookId)
 
 	if len(ret) == 0 {
@@ -93,10 +266,10 @@ func (_m *BookRepository) FindOneById(ctx *gin.Context, db *sql.DB, bookId uint)
 
 	var r0 *domain.Book
 	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB, uint) (*domain.Book, errs.CustomError)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint) (*domain.Book, errs.CustomError)); ok {
 		return rf(ctx, db, bookId)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, *sql.DB, uint) *domain.Book); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint) *domain.Book); ok {
 		r0 = rf(ctx, db, bookId)
 	} else {
 		if ret.Get(0) != nil {
@@ -104,7 +277,7 @@ func (_m *BookRepository) FindOneById(ctx *gin.Context, db *sql.DB, bookId uint)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, *sql.DB, uint) errs.CustomError); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, uint) errs.CustomError); ok {
 		r1 = rf(ctx, db, bookId)
 	} else {
 		if ret.Get(1) != nil {
@@ -115,6 +288,174 @@ func (_m *BookRepository) FindOneById(ctx *gin.Context, db *sql.DB, bookId uint)
 	return r0, r1
 }
 
+// FindTagIds provides a mock function with given fields: ctx, db, bookIds
+func (_m *BookRepository) FindTagIds(ctx context.Context, db repository.DBTX, bookIds []uint) (map[uint][]uint, errs.CustomError) {
+	ret := _m.Called(ctx, db, bookIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTagIds")
+	}
+
+	var r0 map[uint][]uint
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, []uint) (map[uint][]uint, errs.CustomError)); ok {
+		return rf(ctx, db, bookIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, []uint) map[uint][]uint); ok {
+		r0 = rf(ctx, db, bookIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint][]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, []uint) errs.CustomError); ok {
+		r1 = rf(ctx, db, bookIds)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FindTenantIds provides a mock function with given fields: ctx, db
+func (_m *BookRepository) FindTenantIds(ctx context.Context, db repository.DBTX) ([]string, errs.CustomError) {
+	ret := _m.Called(ctx, db)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTenantIds")
+	}
+
+	var r0 []string
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX) ([]string, errs.CustomError)); ok {
+		return rf(ctx, db)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX) []string); ok {
+		r0 = rf(ctx, db)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX) errs.CustomError); ok {
+		r1 = rf(ctx, db)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: ctx, db, book, columns
+func (_m *BookRepository) Patch(ctx context.Context, db repository.DBTX, book *domain.Book, columns []string) (*domain.Book, errs.CustomError) {
+	ret := _m.Called(ctx, db, book, columns)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 *domain.Book
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book, []string) (*domain.Book, errs.CustomError)); ok {
+		return rf(ctx, db, book, columns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book, []string) *domain.Book); ok {
+		r0 = rf(ctx, db, book, columns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.Book, []string) errs.CustomError); ok {
+		r1 = rf(ctx, db, book, columns)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveTag provides a mock function with given fields: ctx, db, bookId, tagId
+func (_m *BookRepository) RemoveTag(ctx context.Context, db repository.DBTX, bookId uint, tagId uint) errs.CustomError {
+	ret := _m.Called(ctx, db, bookId, tagId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTag")
+	}
+
+	var r0 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint, uint) errs.CustomError); ok {
+		r0 = rf(ctx, db, bookId, tagId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errs.CustomError)
+		}
+	}
+
+	return r0
+}
+
+// RemoveTagFromBooks provides a mock function with given fields: ctx, db, tagId
+func (_m *BookRepository) RemoveTagFromBooks(ctx context.Context, db repository.DBTX, tagId uint) errs.CustomError {
+	ret := _m.Called(ctx, db, tagId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTagFromBooks")
+	}
+
+	var r0 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, uint) errs.CustomError); ok {
+		r0 = rf(ctx, db, tagId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errs.CustomError)
+		}
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, db, book
+func (_m *BookRepository) Update(ctx context.Context, db repository.DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	ret := _m.Called(ctx, db, book)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domain.Book
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) (*domain.Book, errs.CustomError)); ok {
+		return rf(ctx, db, book)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.DBTX, *domain.Book) *domain.Book); ok {
+		r0 = rf(ctx, db, book)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.DBTX, *domain.Book) errs.CustomError); ok {
+		r1 = rf(ctx, db, book)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
 // NewBookRepository creates a new instance of BookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookRepository(t interface {
@@ -3,9 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	dto "gin-go-testing/model/dto"
 
-	gin "github.com/gin-gonic/gin"
 	errs "github.com/rulyadhika/go-custom-err/errs"
 
 	mock "github.com/stretchr/testify/mock"
@@ -16,29 +17,29 @@ type BookService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, bookDto
-func (_m *BookService) Create(ctx *gin.Context, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError) {
-	ret := _m.Called(ctx, bookDto)
+// AddTag provides a mock function with given fields: ctx, bookId, tagId
+func (_m *BookService) AddTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError) {
+	ret := _m.Called(ctx, bookId, tagId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for AddTag")
 	}
 
 	var r0 *dto.BookResponse
 	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context, *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError)); ok {
-		return rf(ctx, bookDto)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*dto.BookResponse, errs.CustomError)); ok {
+		return rf(ctx, bookId, tagId)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, *dto.NewBookRequest) *dto.BookResponse); ok {
-		r0 = rf(ctx, bookDto)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *dto.BookResponse); ok {
+		r0 = rf(ctx, bookId, tagId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*dto.BookResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, *dto.NewBookRequest) errs.CustomError); ok {
-		r1 = rf(ctx, bookDto)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) errs.CustomError); ok {
+		r1 = rf(ctx, bookId, tagId)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(errs.CustomError)
@@ -48,40 +49,149 @@ func (_m *BookService) Create(ctx *gin.Context, bookDto *dto.NewBookRequest) (*d
 	return r0, r1
 }
 
-// FindAll provides a mock function with given fields: ctx
-func (_m *BookService) FindAll(ctx *gin.Context) ([]*dto.BookResponse, errs.CustomError) {
-	ret := _m.Called(ctx)
+// Create provides a mock function with given fields: ctx, bookDto, upsert
+func (_m *BookService) Create(ctx context.Context, bookDto *dto.NewBookRequest, upsert bool) (*dto.BookResponse, bool, errs.CustomError) {
+	ret := _m.Called(ctx, bookDto, upsert)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *dto.BookResponse
+	var r1 bool
+	var r2 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.NewBookRequest, bool) (*dto.BookResponse, bool, errs.CustomError)); ok {
+		return rf(ctx, bookDto, upsert)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.NewBookRequest, bool) *dto.BookResponse); ok {
+		r0 = rf(ctx, bookDto, upsert)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.NewBookRequest, bool) bool); ok {
+		r1 = rf(ctx, bookDto, upsert)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *dto.NewBookRequest, bool) errs.CustomError); ok {
+		r2 = rf(ctx, bookDto, upsert)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errs.CustomError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// Delete provides a mock function with given fields: ctx, bookId
+func (_m *BookService) Delete(ctx context.Context, bookId uint) errs.CustomError {
+	ret := _m.Called(ctx, bookId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, uint) errs.CustomError); ok {
+		r0 = rf(ctx, bookId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errs.CustomError)
+		}
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields: ctx, req
+func (_m *BookService) FindAll(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.PaginationMeta, errs.CustomError) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAll")
 	}
 
 	var r0 []*dto.BookResponse
-	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context) ([]*dto.BookResponse, errs.CustomError)); ok {
-		return rf(ctx)
+	var r1 *dto.PaginationMeta
+	var r2 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.PaginationMeta, errs.CustomError)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context) []*dto.BookResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.FindAllBookRequest) []*dto.BookResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*dto.BookResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context) errs.CustomError); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.FindAllBookRequest) *dto.PaginationMeta); ok {
+		r1 = rf(ctx, req)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(errs.CustomError)
+			r1 = ret.Get(1).(*dto.PaginationMeta)
 		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, *dto.FindAllBookRequest) errs.CustomError); ok {
+		r2 = rf(ctx, req)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errs.CustomError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// FindAllByCursor provides a mock function with given fields: ctx, req
+func (_m *BookService) FindAllByCursor(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.CursorMeta, errs.CustomError) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllByCursor")
+	}
+
+	var r0 []*dto.BookResponse
+	var r1 *dto.CursorMeta
+	var r2 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.CursorMeta, errs.CustomError)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.FindAllBookRequest) []*dto.BookResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.FindAllBookRequest) *dto.CursorMeta); ok {
+		r1 = rf(ctx, req)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*dto.CursorMeta)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *dto.FindAllBookRequest) errs.CustomError); ok {
+		r2 = rf(ctx, req)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errs.CustomError)
+		}
+	}
+
+	return r0, r1, r2
 }
 
 // FindOneById provides a mock function with given fields: ctx, bookId
-func (_m *BookService) FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResponse, errs.CustomError) {
+func (_m *BookService) FindOneById(ctx context.Context, bookId uint) (*dto.BookResponse, errs.CustomError) {
 	ret := _m.Called(ctx, bookId)
 
 	if len(ret) == 0 {
@@ -90,10 +200,10 @@ func (_m *BookService) FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResp
 
 	var r0 *dto.BookResponse
 	var r1 errs.CustomError
-	if rf, ok := ret.Get(0).(func(*gin.Context, uint) (*dto.BookResponse, errs.CustomError)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*dto.BookResponse, errs.CustomError)); ok {
 		return rf(ctx, bookId)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, uint) *dto.BookResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *dto.BookResponse); ok {
 		r0 = rf(ctx, bookId)
 	} else {
 		if ret.Get(0) != nil {
@@ -101,7 +211,7 @@ func (_m *BookService) FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResp
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, uint) errs.CustomError); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, uint) errs.CustomError); ok {
 		r1 = rf(ctx, bookId)
 	} else {
 		if ret.Get(1) != nil {
@@ -112,6 +222,143 @@ func (_m *BookService) FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResp
 	return r0, r1
 }
 
+// Patch provides a mock function with given fields: ctx, bookId, patchDto
+func (_m *BookService) Patch(ctx context.Context, bookId uint, patchDto *dto.PatchBookRequest) (*dto.BookResponse, errs.CustomError) {
+	ret := _m.Called(ctx, bookId, patchDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 *dto.BookResponse
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.PatchBookRequest) (*dto.BookResponse, errs.CustomError)); ok {
+		return rf(ctx, bookId, patchDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.PatchBookRequest) *dto.BookResponse); ok {
+		r0 = rf(ctx, bookId, patchDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *dto.PatchBookRequest) errs.CustomError); ok {
+		r1 = rf(ctx, bookId, patchDto)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveTag provides a mock function with given fields: ctx, bookId, tagId
+func (_m *BookService) RemoveTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError) {
+	ret := _m.Called(ctx, bookId, tagId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTag")
+	}
+
+	var r0 *dto.BookResponse
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*dto.BookResponse, errs.CustomError)); ok {
+		return rf(ctx, bookId, tagId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *dto.BookResponse); ok {
+		r0 = rf(ctx, bookId, tagId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) errs.CustomError); ok {
+		r1 = rf(ctx, bookId, tagId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, req
+func (_m *BookService) Search(ctx context.Context, req *dto.SearchBookRequest) ([]*dto.BookSearchHitResponse, *dto.PaginationMeta, errs.CustomError) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*dto.BookSearchHitResponse
+	var r1 *dto.PaginationMeta
+	var r2 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.SearchBookRequest) ([]*dto.BookSearchHitResponse, *dto.PaginationMeta, errs.CustomError)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.SearchBookRequest) []*dto.BookSearchHitResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.BookSearchHitResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.SearchBookRequest) *dto.PaginationMeta); ok {
+		r1 = rf(ctx, req)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*dto.PaginationMeta)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *dto.SearchBookRequest) errs.CustomError); ok {
+		r2 = rf(ctx, req)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errs.CustomError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: ctx, bookId, bookDto
+func (_m *BookService) Update(ctx context.Context, bookId uint, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError) {
+	ret := _m.Called(ctx, bookId, bookDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *dto.BookResponse
+	var r1 errs.CustomError
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError)); ok {
+		return rf(ctx, bookId, bookDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *dto.NewBookRequest) *dto.BookResponse); ok {
+		r0 = rf(ctx, bookId, bookDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *dto.NewBookRequest) errs.CustomError); ok {
+		r1 = rf(ctx, bookId, bookDto)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errs.CustomError)
+		}
+	}
+
+	return r0, r1
+}
+
 // NewBookService creates a new instance of BookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookService(t interface {
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ApiKey is a static credential of a machine client. The key itself is shown once, when it is
+// created, only its hash is stored.
+type ApiKey struct {
+	Id   uint
+	Name string
+	// Prefix is the start of the key, enough for people to tell their keys apart
+	Prefix string
+	// Hash is the hex SHA-256 of the key
+	Hash string
+	// Roles are the roles of the principal the key authenticates
+	Roles []string
+	// Tenant is the tenant the key acts for, requests naming another one are refused
+	Tenant    string
+	CreatedAt time.Time
+	// RevokedAt is nil while the key is usable
+	RevokedAt *time.Time
+}
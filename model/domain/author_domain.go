@@ -0,0 +1,28 @@
+package domain
+
+import "strings"
+
+// Author is a person credited on books, shared by every book they wrote.
+type Author struct {
+	Id   uint
+	Name string
+}
+
+// AuthorListParams narrows the authors returned by a listing.
+// An empty filter is ignored and a zero Limit means no limit.
+type AuthorListParams struct {
+	Limit        uint
+	Offset       uint
+	NameContains string
+}
+
+// Byline joins the names of authors in order, e.g. "Terry Pratchett, Neil Gaiman".
+func Byline(authors []*Author) string {
+	names := make([]string, 0, len(authors))
+
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -1,7 +1,24 @@
 package domain
 
+// Book is a catalogue entry. Title and Author are required, the other details are optional
+// and nil when unknown.
 type Book struct {
-	Id     uint
-	Title  string
+	Id    uint
+	Title string
+	// Author is the byline, the names of Authors joined in order. It is stored with the book
+	// so listings can filter, sort and deduplicate on it.
 	Author string
+	// Authors are loaded and saved by the AuthorRepository, not the BookRepository
+	Authors []*Author
+	// Tags are loaded by the service, the BookRepository only stores the tag ids of a book
+	Tags []*Tag
+	// Isbn is normalized to its 13 digits
+	Isbn            *string
+	PublicationYear *int
+	Publisher       *string
+	// Language is a canonical BCP 47 tag, e.g. en-US
+	Language    *string
+	PageCount   *int
+	Description *string
+	CoverUrl    *string
 }
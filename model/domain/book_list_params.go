@@ -0,0 +1,18 @@
+package domain
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// BookListParams narrows and orders the books returned by a listing.
+// Empty filters are ignored and a zero Limit means no limit.
+type BookListParams struct {
+	Limit         uint
+	Offset        uint
+	Sort          []SortField
+	Author        string
+	TitleContains string
+	// TagIds narrows the listing to books carrying at least one tag of every group
+	TagIds [][]uint
+}
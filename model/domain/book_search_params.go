@@ -0,0 +1,26 @@
+package domain
+
+// BookSearchParams pages through the books matching a full-text query, best matches first.
+// A zero Limit means no limit.
+type BookSearchParams struct {
+	Query  string
+	Limit  uint
+	Offset uint
+}
+
+// BookSearchHit is a book matching a search, with its relevance and the matched words of
+// its fields highlighted.
+type BookSearchHit struct {
+	Book      *Book
+	Score     float64
+	Highlight BookHighlight
+}
+
+// BookHighlight holds HTML escaped snippets of the searched fields with the matched words
+// wrapped in <mark>. Description is a passage around the first match, empty when the book
+// has none.
+type BookHighlight struct {
+	Title       string
+	Author      string
+	Description string
+}
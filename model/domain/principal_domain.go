@@ -0,0 +1,22 @@
+package domain
+
+// Ways a principal can authenticate.
+const (
+	AuthMethodApiKey = "api_key"
+	AuthMethodJWT    = "jwt"
+)
+
+// Principal is who a request is made on behalf of, attached to the request context once the
+// request is authenticated.
+type Principal struct {
+	// Subject identifies the principal, the sub claim of a token or api_key:ID for an API key
+	Subject string
+	// Name is the name of the API key or the name claim of a token, empty when unknown
+	Name   string
+	Method string
+	// Roles are granted permissions by the policy, see auth.Policy
+	Roles []string
+	// Tenant is the tenant the credentials are bound to, that of an API key or the tenant claim
+	// of a token, empty when they are not bound to one
+	Tenant string
+}
@@ -0,0 +1,18 @@
+package domain
+
+// Tag categorises books. Tags nest, a genre such as Fantasy has Fiction as its parent, and
+// a book tagged with a genre is found under every tag above it.
+type Tag struct {
+	Id   uint
+	Name string
+	// ParentId is nil for a top level tag
+	ParentId *uint
+}
+
+// TagListParams narrows the tags returned by a listing.
+// An empty filter is ignored and a zero Limit means no limit.
+type TagListParams struct {
+	Limit        uint
+	Offset       uint
+	NameContains string
+}
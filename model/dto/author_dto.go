@@ -0,0 +1,20 @@
+package dto
+
+type NewAuthorRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+func (r *NewAuthorRequest) Normalize() {
+	r.Name = normalizeText(r.Name)
+}
+
+type AuthorResponse struct {
+	Id   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type FindAllAuthorRequest struct {
+	Page         uint   `form:"page"`
+	PageSize     uint   `form:"page_size"`
+	NameContains string `form:"name_contains"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var isbnSeparators = strings.NewReplacer("-", "", " ", "")
+
+// normalizeIsbn strips separators and turns a valid ISBN-10 into its ISBN-13, so a book has
+// one ISBN whichever form it was given in. Invalid values are left for validation to reject.
+func normalizeIsbn(value string) string {
+	isbn := strings.ToUpper(isbnSeparators.Replace(strings.TrimSpace(value)))
+
+	if !isValidIsbn10(isbn) {
+		return isbn
+	}
+
+	isbn13 := "978" + isbn[:9]
+
+	sum := 0
+	for i, digit := range isbn13 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += int(digit-'0') * weight
+	}
+
+	return isbn13 + string(rune('0'+(10-sum%10)%10))
+}
+
+func isValidIsbn10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+
+	sum := 0
+
+	for i, char := range isbn {
+		var digit int
+
+		switch {
+		case char >= '0' && char <= '9':
+			digit = int(char - '0')
+		case char == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+// normalizeLanguage canonicalizes a BCP 47 tag, e.g. "EN-us" becomes "en-US". Invalid tags
+// are left for validation to reject.
+func normalizeLanguage(value string) string {
+	value = strings.TrimSpace(value)
+
+	tag, err := language.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	return tag.String()
+}
@@ -1,12 +1,143 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// NewBookRequest credits the book either to the author named Author, created when it does not
+// exist yet, or to the existing authors listed in AuthorIds in byline order.
 type NewBookRequest struct {
-	Title  string `json:"title"`
-	Author string `json:"author"`
+	Title           string  `json:"title" binding:"required,max=255"`
+	Author          string  `json:"author,omitempty" binding:"required_without=AuthorIds,excluded_with=AuthorIds,max=255"`
+	AuthorIds       []uint  `json:"author_ids,omitempty" binding:"omitnil,min=1,max=20,unique,dive,gt=0"`
+	Isbn            *string `json:"isbn,omitempty" binding:"omitnil,isbn"`
+	PublicationYear *int    `json:"publication_year,omitempty" binding:"omitnil,publication_year"`
+	Publisher       *string `json:"publisher,omitempty" binding:"omitnil,min=1,max=255"`
+	Language        *string `json:"language,omitempty" binding:"omitnil,bcp47_language_tag"`
+	PageCount       *int    `json:"page_count,omitempty" binding:"omitnil,gte=1,lte=100000"`
+	Description     *string `json:"description,omitempty" binding:"omitnil,min=1,max=5000"`
+	CoverUrl        *string `json:"cover_url,omitempty" binding:"omitnil,http_url,max=2048"`
+}
+
+func (r *NewBookRequest) Normalize() {
+	r.Title = normalizeText(r.Title)
+	r.Author = normalizeText(r.Author)
+
+	normalizeDetails(r.Isbn, r.Publisher, r.Language, r.Description, r.CoverUrl)
+}
+
+// CreateBookQuery are the query parameters of a book creation.
+type CreateBookQuery struct {
+	// Upsert returns the existing book instead of a conflict when the book already exists
+	Upsert bool `form:"upsert"`
 }
 
 type BookResponse struct {
-	Id     uint   `json:"id"`
-	Title  string `json:"title"`
-	Author string `json:"author"`
+	Id    uint   `json:"id"`
+	Title string `json:"title"`
+	// Author is the byline, kept for the clients that predate Authors
+	Author          string            `json:"author"`
+	Authors         []*AuthorResponse `json:"authors"`
+	Tags            []*TagResponse    `json:"tags"`
+	Isbn            *string           `json:"isbn,omitempty"`
+	PublicationYear *int              `json:"publication_year,omitempty"`
+	Publisher       *string           `json:"publisher,omitempty"`
+	Language        *string           `json:"language,omitempty"`
+	PageCount       *int              `json:"page_count,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	CoverUrl        *string           `json:"cover_url,omitempty"`
+}
+
+// PatchBookRequest is an RFC 7396 merge patch for a book.
+// A nil field means the member was absent from the patch and must be left untouched.
+type PatchBookRequest struct {
+	Title           *string `json:"title,omitempty" binding:"omitnil,min=1,max=255"`
+	Author          *string `json:"author,omitempty" binding:"omitnil,excluded_with=AuthorIds,min=1,max=255"`
+	AuthorIds       []uint  `json:"author_ids,omitempty" binding:"omitnil,min=1,max=20,unique,dive,gt=0"`
+	Isbn            *string `json:"isbn,omitempty" binding:"omitnil,isbn"`
+	PublicationYear *int    `json:"publication_year,omitempty" binding:"omitnil,publication_year"`
+	Publisher       *string `json:"publisher,omitempty" binding:"omitnil,min=1,max=255"`
+	Language        *string `json:"language,omitempty" binding:"omitnil,bcp47_language_tag"`
+	PageCount       *int    `json:"page_count,omitempty" binding:"omitnil,gte=1,lte=100000"`
+	Description     *string `json:"description,omitempty" binding:"omitnil,min=1,max=5000"`
+	CoverUrl        *string `json:"cover_url,omitempty" binding:"omitnil,http_url,max=2048"`
+	// Removed lists the optional members set to null, which removes them from the book
+	Removed []string `json:"-"`
+}
+
+func (p *PatchBookRequest) UnmarshalJSON(data []byte) error {
+	members := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		name     string
+		target   any
+		required bool
+		kind     string
+	}{
+		{"title", &p.Title, true, "string"},
+		{"author", &p.Author, true, "string"},
+		{"author_ids", &p.AuthorIds, true, "list"},
+		{"isbn", &p.Isbn, false, "string"},
+		{"publication_year", &p.PublicationYear, false, "integer"},
+		{"publisher", &p.Publisher, false, "string"},
+		{"language", &p.Language, false, "string"},
+		{"page_count", &p.PageCount, false, "integer"},
+		{"description", &p.Description, false, "string"},
+		{"cover_url", &p.CoverUrl, false, "string"},
+	}
+
+	for _, field := range fields {
+		raw, ok := members[field.name]
+		if !ok {
+			continue
+		}
+
+		// a null member asks for removal, but every book must keep its title and author
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if field.required {
+				return &FieldError{Field: field.name, Rule: "required", Message: field.name + " cannot be removed"}
+			}
+
+			p.Removed = append(p.Removed, field.name)
+			continue
+		}
+
+		if err := json.Unmarshal(raw, field.target); err != nil {
+			return &FieldError{Field: field.name, Rule: field.kind, Message: field.name + " must be a " + field.kind}
+		}
+	}
+
+	return nil
+}
+
+func (p *PatchBookRequest) Normalize() {
+	for _, field := range []*string{p.Title, p.Author} {
+		if field != nil {
+			*field = normalizeText(*field)
+		}
+	}
+
+	normalizeDetails(p.Isbn, p.Publisher, p.Language, p.Description, p.CoverUrl)
+}
+
+// normalizeDetails normalizes the optional details shared by the book requests.
+func normalizeDetails(isbn, publisher, language, description, coverUrl *string) {
+	for _, field := range []*string{publisher, description, coverUrl} {
+		if field != nil {
+			*field = normalizeText(*field)
+		}
+	}
+
+	if isbn != nil {
+		*isbn = normalizeIsbn(*isbn)
+	}
+
+	if language != nil {
+		*language = normalizeLanguage(*language)
+	}
 }
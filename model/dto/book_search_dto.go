@@ -0,0 +1,23 @@
+package dto
+
+type SearchBookRequest struct {
+	Q        string `form:"q" binding:"required,max=200"`
+	Page     uint   `form:"page"`
+	PageSize uint   `form:"page_size"`
+}
+
+// BookSearchHitResponse is a book matching a search. Score only orders the hits of one
+// search, it is not comparable across searches or backends.
+type BookSearchHitResponse struct {
+	Book      *BookResponse          `json:"book"`
+	Score     float64                `json:"score"`
+	Highlight *BookHighlightResponse `json:"highlight"`
+}
+
+// BookHighlightResponse holds the HTML escaped fields of a hit with the matched words
+// wrapped in <mark>. Description is a passage around the first match.
+type BookHighlightResponse struct {
+	Title       string `json:"title"`
+	Author      string `json:"author"`
+	Description string `json:"description,omitempty"`
+}
@@ -5,4 +5,5 @@ type APIResponse struct {
 	StatusCode uint   `json:"status_code"`
 	Message    string `json:"message"`
 	Data       any    `json:"data"`
+	Meta       any    `json:"meta,omitempty"`
 }
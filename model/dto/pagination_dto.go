@@ -0,0 +1,40 @@
+package dto
+
+type FindAllBookRequest struct {
+	Page          uint   `form:"page"`
+	PageSize      uint   `form:"page_size"`
+	Limit         uint   `form:"limit"`
+	Offset        uint   `form:"offset"`
+	Sort          string `form:"sort"`
+	Author        string `form:"author"`
+	TitleContains string `form:"title_contains"`
+	// Tag is a comma separated list of tag names, TagMode tells whether a book must carry
+	// all of them (the default) or any
+	Tag     string  `form:"tag"`
+	TagMode string  `form:"tag_mode" binding:"omitempty,oneof=all any"`
+	Cursor  *string `form:"cursor"`
+}
+
+type PaginationMeta struct {
+	Page       uint             `json:"page"`
+	PageSize   uint             `json:"page_size"`
+	TotalCount uint             `json:"total_count"`
+	TotalPages uint             `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+	Facets     *Facets          `json:"facets,omitempty"`
+}
+
+// Facets break the whole listing down, not only the current page.
+type Facets struct {
+	Tags []*TagFacet `json:"tags"`
+}
+
+type PaginationLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type CursorMeta struct {
+	PageSize   uint   `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "encoding/json"
+
+// ProblemDetails is the RFC 7807 body of every failed request, served as
+// application/problem+json.
+type ProblemDetails struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Status    int           `json:"status"`
+	Detail    string        `json:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	Code      string        `json:"code"`
+	RequestId string        `json:"request_id,omitempty"`
+	Errors    []*FieldError `json:"errors,omitempty"`
+	// Extensions are marshaled as members of their own, e.g. the existing_id of a conflict.
+	Extensions map[string]any `json:"-"`
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	// problem has the fields but not the methods of ProblemDetails, so this does not recurse
+	type problem ProblemDetails
+
+	data, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	extensions, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	// splice the extension members into the object: {...} + {...} => {...,...}
+	return append(append(data[:len(data)-1], ','), extensions[1:]...), nil
+}
@@ -0,0 +1,31 @@
+package dto
+
+// NewTagRequest creates or replaces a tag. Names cannot hold commas, books are filtered by a
+// comma separated list of tag names.
+type NewTagRequest struct {
+	Name     string `json:"name" binding:"required,max=100,excludesall=0x2C"`
+	ParentId *uint  `json:"parent_id" binding:"omitnil,gt=0"`
+}
+
+func (r *NewTagRequest) Normalize() {
+	r.Name = normalizeText(r.Name)
+}
+
+type TagResponse struct {
+	Id       uint   `json:"id"`
+	Name     string `json:"name"`
+	ParentId *uint  `json:"parent_id,omitempty"`
+}
+
+type FindAllTagRequest struct {
+	Page         uint   `form:"page"`
+	PageSize     uint   `form:"page_size"`
+	NameContains string `form:"name_contains"`
+}
+
+// TagFacet is the number of listed books carrying a tag.
+type TagFacet struct {
+	Id    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count uint   `json:"count"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (f *FieldError) Error() string {
+	return f.Message
+}
+
+// Normalizer is implemented by requests that clean up their input before validation.
+type Normalizer interface {
+	Normalize()
+}
+
+// normalizeText trims surrounding whitespace and converts to Unicode NFC, so visually
+// identical input is stored, compared and length checked the same way.
+func normalizeText(value string) string {
+	return norm.NFC.String(strings.TrimSpace(value))
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how often the in-memory store forgets the buckets that are full again.
+const sweepEvery = time.Minute
+
+type memoryStoreImpl struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	swept   time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	// full is when the bucket is full again, from then on it is no different from a new one
+	full time.Time
+}
+
+// NewMemoryStoreImpl returns a Store keeping the buckets in memory, each instance of the
+// server then meters the requests it serves on its own.
+func NewMemoryStoreImpl() Store {
+	return &memoryStoreImpl{buckets: map[string]*memoryBucket{}}
+}
+
+func (m *memoryStoreImpl) Take(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{Bucket: FullBucket(limit, now)}
+		m.buckets[key] = b
+	}
+
+	result := b.Take(limit, now)
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep drops the buckets that are full again, so clients seen once are not kept forever.
+// The caller holds the lock.
+func (m *memoryStoreImpl) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepEvery {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.swept = now
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestMemoryStoreSuite struct {
+	suite.Suite
+	store Store
+	ctx   context.Context
+	now   time.Time
+	// limit refills a token every 20 seconds
+	limit Limit
+}
+
+func TestUnitTestMemoryStore(t *testing.T) {
+	suite.Run(t, &unitTestMemoryStoreSuite{})
+}
+
+func (u *unitTestMemoryStoreSuite) SetupTest() {
+	u.store = NewMemoryStoreImpl()
+	u.ctx = context.Background()
+	u.now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	u.limit = Limit{Requests: 3, Period: time.Minute}
+}
+
+func (u *unitTestMemoryStoreSuite) take(key string, after time.Duration) *Result {
+	result, err := u.store.Take(u.ctx, key, u.limit, u.now.Add(after))
+	u.Require().NoError(err)
+
+	return result
+}
+
+func (u *unitTestMemoryStoreSuite) TestTake_Burst() {
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 20 * time.Second}, u.take("api_key:1", 0))
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 40 * time.Second}, u.take("api_key:1", 0))
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 0, Reset: time.Minute}, u.take("api_key:1", 0))
+
+	u.Equal(&Result{Limit: 3, Remaining: 0, Reset: time.Minute, RetryAfter: 20 * time.Second}, u.take("api_key:1", 0))
+
+	// other clients have buckets of their own
+	u.True(u.take("api_key:2", 0).Allowed)
+}
+
+func (u *unitTestMemoryStoreSuite) TestTake_Refills() {
+	for i := 0; i < 3; i++ {
+		u.take("api_key:1", 0)
+	}
+
+	result := u.take("api_key:1", 15*time.Second)
+	u.False(result.Allowed)
+	u.Equal(5*time.Second, result.RetryAfter)
+
+	result = u.take("api_key:1", 20*time.Second)
+	u.True(result.Allowed)
+	u.Equal(time.Minute, result.Reset)
+
+	// the bucket never holds more than the limit
+	result = u.take("api_key:1", time.Hour)
+	u.Equal(uint(2), result.Remaining)
+}
+
+func (u *unitTestMemoryStoreSuite) TestSweep_ForgetsFullBuckets() {
+	// api_key:1 is full again 20 seconds later, so the next sweep forgets it
+	u.take("api_key:1", 0)
+	u.take("api_key:2", 110*time.Second)
+	u.take("api_key:3", 2*time.Minute)
+
+	buckets := u.store.(*memoryStoreImpl).buckets
+	u.Len(buckets, 2)
+	u.NotContains(buckets, "api_key:1")
+}
@@ -0,0 +1,88 @@
+// Package ratelimit meters requests with token buckets. A bucket holds as many tokens as its
+// limit allows requests in a period and refills at that pace, every request takes a token and
+// requests finding the bucket empty are refused. The buckets live in a Store, so instances of
+// the server can share them.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is how many requests a client may make in Period, at once or spread over it. Both
+// must be positive.
+type Limit struct {
+	Requests uint
+	Period   time.Duration
+}
+
+// Result is what taking a token from a bucket decided.
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit uint
+	// Remaining is how many whole tokens the bucket holds once the request took its own
+	Remaining uint
+	// Reset is how long the bucket takes to be full again
+	Reset time.Duration
+	// RetryAfter is how long until the bucket holds a token again, zero when Allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. Take is a single atomic step, so a store shared by several
+// instances, such as Redis running it as one script, never hands out a token twice.
+type Store interface {
+	// Take takes a token from the bucket under key for a request made at now, creating the
+	// bucket full when there is none.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error)
+}
+
+// Bucket is the state of a token bucket. A Store only has to keep its two fields and run
+// Take on them as a single atomic step.
+type Bucket struct {
+	Tokens float64
+	// Updated is when Tokens was last computed
+	Updated time.Time
+}
+
+// FullBucket returns the bucket of a key seen for the first time.
+func FullBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Requests), Updated: now}
+}
+
+// Take refills b for the time passed since it was updated, then takes a token from it when
+// there is one.
+func (b *Bucket) Take(limit Limit, now time.Time) *Result {
+	perToken := limit.Period / time.Duration(limit.Requests)
+
+	if elapsed := now.Sub(b.Updated); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Requests), b.Tokens+float64(elapsed)/float64(perToken))
+		b.Updated = now
+	}
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+
+	return newResult(limit, b.Tokens, allowed)
+}
+
+// newResult is what taking a token decided, given the tokens the bucket holds afterwards.
+func newResult(limit Limit, tokens float64, allowed bool) *Result {
+	perToken := float64(limit.Period / time.Duration(limit.Requests))
+
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: uint(tokens),
+		Reset:     time.Duration((float64(limit.Requests) - tokens) * perToken),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * perToken)
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ScriptRunner runs a script as one atomic step on the server keeping the buckets, the way
+// Redis runs EVAL. It is all a ScriptStore needs from its client, so any client can be
+// adapted to it.
+type ScriptRunner interface {
+	// Eval runs script with keys and args, and returns its reply: integers as int64,
+	// strings as string and tables as []any.
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// takeScript is Bucket.Take on the hash under KEYS[1], with ARGV the requests, the period
+// and the time of the request, both in milliseconds. The hash expires once the bucket is
+// full again. It replies whether the request is allowed and the tokens left, as a string
+// since Redis truncates numbers to integers.
+const takeScript = `
+local size = tonumber(ARGV[1])
+local per_token = tonumber(ARGV[2]) / size
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens, updated = tonumber(state[1]), tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens, updated = size, now
+end
+
+if now > updated then
+	tokens = math.min(size, tokens + (now - updated) / per_token)
+	updated = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', updated)
+redis.call('PEXPIRE', KEYS[1], math.ceil((size - tokens) * per_token) + 1)
+
+return {allowed, tostring(tokens)}
+`
+
+type scriptStoreImpl struct {
+	runner ScriptRunner
+	// prefix keeps the keys of the buckets apart from the others of the server
+	prefix string
+}
+
+// NewScriptStoreImpl returns a Store keeping the buckets on a server shared by the
+// instances of the server, such as Redis, under keys starting with prefix.
+func NewScriptStoreImpl(runner ScriptRunner, prefix string) Store {
+	return &scriptStoreImpl{runner: runner, prefix: prefix}
+}
+
+func (s *scriptStoreImpl) Take(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	reply, err := s.runner.Eval(ctx, takeScript, []string{s.prefix + key}, limit.Requests, limit.Period.Milliseconds(), now.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("take a token from %s: %w", key, err)
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("take a token from %s: unexpected reply %v", key, reply)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("take a token from %s: unexpected reply %v", key, reply)
+	}
+
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("take a token from %s: unexpected reply %v", key, reply)
+	}
+
+	return newResult(limit, tokens, allowed == 1), nil
+}
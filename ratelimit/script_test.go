@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// fakeScriptRunner stands in for Redis: it runs the scripts in a Lua VM, as Redis does, with
+// redis.call serving the commands takeScript uses from hashes of its own. It records the keys
+// it is asked for.
+type fakeScriptRunner struct {
+	hashes map[string]map[string]string
+	// ttls are the expiries set with PEXPIRE, in milliseconds
+	ttls map[string]int64
+	keys []string
+	// reply replaces the reply of the script when set
+	reply any
+	err   error
+}
+
+func newFakeScriptRunner() *fakeScriptRunner {
+	return &fakeScriptRunner{hashes: map[string]map[string]string{}, ttls: map[string]int64{}}
+}
+
+func (f *fakeScriptRunner) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	f.keys = append(f.keys, keys...)
+
+	if f.err != nil || f.reply != nil {
+		return f.reply, f.err
+	}
+
+	L := lua.NewState()
+	defer L.Close()
+
+	// Redis hands KEYS and ARGV to scripts as strings
+	keysTable, argsTable := L.NewTable(), L.NewTable()
+	for _, key := range keys {
+		keysTable.Append(lua.LString(key))
+	}
+	for _, arg := range args {
+		argsTable.Append(lua.LString(fmt.Sprint(arg)))
+	}
+
+	redis := L.NewTable()
+	L.SetField(redis, "call", L.NewFunction(f.call))
+
+	L.SetGlobal("KEYS", keysTable)
+	L.SetGlobal("ARGV", argsTable)
+	L.SetGlobal("redis", redis)
+
+	if err := L.DoString(script); err != nil {
+		return nil, err
+	}
+
+	return fromLua(L.Get(-1)), nil
+}
+
+// call serves redis.call for the commands of takeScript.
+func (f *fakeScriptRunner) call(L *lua.LState) int {
+	command, key := strings.ToUpper(L.CheckString(1)), L.CheckString(2)
+
+	hash := f.hashes[key]
+	if hash == nil {
+		hash = map[string]string{}
+		f.hashes[key] = hash
+	}
+
+	switch command {
+	case "HMGET":
+		reply := L.NewTable()
+		for i := 3; i <= L.GetTop(); i++ {
+			// missing fields are nil, which Redis hands to scripts as false
+			if value, ok := hash[L.CheckString(i)]; ok {
+				reply.Append(lua.LString(value))
+			} else {
+				reply.Append(lua.LFalse)
+			}
+		}
+
+		L.Push(reply)
+	case "HSET":
+		for i := 3; i < L.GetTop(); i += 2 {
+			hash[L.CheckString(i)] = L.CheckString(i + 1)
+		}
+
+		L.Push(lua.LNumber(0))
+	case "PEXPIRE":
+		f.ttls[key] = int64(L.CheckNumber(3))
+
+		L.Push(lua.LNumber(1))
+	default:
+		L.RaiseError("unknown command %s", command)
+	}
+
+	return 1
+}
+
+// fromLua converts the reply of a script the way Redis does: numbers become integers,
+// strings stay strings and tables become arrays.
+func fromLua(value lua.LValue) any {
+	switch value := value.(type) {
+	case lua.LNumber:
+		return int64(value)
+	case lua.LString:
+		return string(value)
+	case *lua.LTable:
+		values := []any{}
+		for i := 1; i <= value.Len(); i++ {
+			values = append(values, fromLua(value.RawGetInt(i)))
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+type unitTestScriptStoreSuite struct {
+	suite.Suite
+	runner *fakeScriptRunner
+	store  Store
+	ctx    context.Context
+	now    time.Time
+	// limit refills a token every 20 seconds
+	limit Limit
+}
+
+func TestUnitTestScriptStore(t *testing.T) {
+	suite.Run(t, &unitTestScriptStoreSuite{})
+}
+
+func (u *unitTestScriptStoreSuite) SetupTest() {
+	u.runner = newFakeScriptRunner()
+	u.store = NewScriptStoreImpl(u.runner, "rate_limit:")
+	u.ctx = context.Background()
+	u.now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	u.limit = Limit{Requests: 3, Period: time.Minute}
+}
+
+func (u *unitTestScriptStoreSuite) take(key string, after time.Duration) *Result {
+	result, err := u.store.Take(u.ctx, key, u.limit, u.now.Add(after))
+	u.Require().NoError(err)
+
+	return result
+}
+
+func (u *unitTestScriptStoreSuite) TestTake_Burst() {
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 20 * time.Second}, u.take("api_key:1", 0))
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 40 * time.Second}, u.take("api_key:1", 0))
+	u.Equal(&Result{Allowed: true, Limit: 3, Remaining: 0, Reset: time.Minute}, u.take("api_key:1", 0))
+
+	u.Equal(&Result{Limit: 3, Remaining: 0, Reset: time.Minute, RetryAfter: 20 * time.Second}, u.take("api_key:1", 0))
+
+	// the buckets are kept under the prefix
+	u.Equal([]string{"rate_limit:api_key:1", "rate_limit:api_key:1", "rate_limit:api_key:1", "rate_limit:api_key:1"}, u.runner.keys)
+}
+
+func (u *unitTestScriptStoreSuite) TestTake_Refills() {
+	for i := 0; i < 3; i++ {
+		u.take("api_key:1", 0)
+	}
+
+	result := u.take("api_key:1", 15*time.Second)
+	u.False(result.Allowed)
+	u.Equal(5*time.Second, result.RetryAfter)
+
+	result = u.take("api_key:1", 20*time.Second)
+	u.True(result.Allowed)
+	u.Equal(time.Minute, result.Reset)
+}
+
+func (u *unitTestScriptStoreSuite) TestTake_ExpiresFullBuckets() {
+	u.take("api_key:1", 0)
+
+	// the bucket is full again 20 seconds later, then it is no different from a missing one
+	u.Equal(int64(20001), u.runner.ttls["rate_limit:api_key:1"])
+	u.Equal(map[string]string{"tokens": "2", "updated": strconv.FormatInt(u.now.UnixMilli(), 10)}, u.runner.hashes["rate_limit:api_key:1"])
+}
+
+// TestTake_MatchesBucket runs the script and Bucket.Take side by side, so the two
+// implementations of the bucket step cannot drift apart.
+func (u *unitTestScriptStoreSuite) TestTake_MatchesBucket() {
+	limit := Limit{Requests: 5, Period: 10 * time.Second}
+	bucket := FullBucket(limit, u.now)
+
+	for i, after := range []time.Duration{0, 0, 300 * time.Millisecond, time.Second, time.Second, 1100 * time.Millisecond, 1100 * time.Millisecond, 1100 * time.Millisecond, 4 * time.Second, 7 * time.Second, 7 * time.Second, time.Minute} {
+		now := u.now.Add(after)
+
+		result, err := u.store.Take(u.ctx, "api_key:1", limit, now)
+		u.Require().NoError(err)
+
+		u.Equal(bucket.Take(limit, now), result, "take %d", i)
+	}
+}
+
+func (u *unitTestScriptStoreSuite) TestTake_RunnerFailed() {
+	u.runner.err = errors.New("connection refused")
+
+	result, err := u.store.Take(u.ctx, "api_key:1", u.limit, u.now)
+
+	u.Nil(result)
+	u.ErrorIs(err, u.runner.err)
+}
+
+func (u *unitTestScriptStoreSuite) TestTake_UnexpectedReply() {
+	for _, reply := range []any{"OK", []any{int64(1)}, []any{"1", "2"}, []any{int64(1), "many"}} {
+		u.runner.reply = reply
+
+		result, err := u.store.Take(u.ctx, "api_key:1", u.limit, u.now)
+
+		u.Nil(result)
+		u.ErrorContains(err, "unexpected reply", reply)
+	}
+}
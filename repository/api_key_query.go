@@ -0,0 +1,23 @@
+package repository
+
+import "strings"
+
+const (
+	createApiKeyQuery     = `INSERT INTO api_keys(name, prefix, key_hash, roles, tenant_id, created_at) VALUES($1,$2,$3,$4,$5,$6)`
+	findApiKeyByHashQuery = `SELECT id, name, prefix, key_hash, roles, tenant_id, created_at, revoked_at FROM api_keys WHERE key_hash=$1`
+	findAllApiKeysQuery   = `SELECT id, name, prefix, key_hash, roles, tenant_id, created_at, revoked_at FROM api_keys ORDER BY id ASC`
+	revokeApiKeyQuery     = `UPDATE api_keys SET revoked_at=$1 WHERE id=$2 AND revoked_at IS NULL`
+)
+
+// joinRoles and splitRoles convert roles to and from the comma separated roles column.
+func joinRoles(roles []string) string {
+	return strings.Join(roles, ",")
+}
+
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return []string{}
+	}
+
+	return strings.Split(roles, ",")
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, db DBTX, key *domain.ApiKey) (*domain.ApiKey, errs.CustomError)
+	// FindByHash returns the key with the given hash, revoked or not.
+	FindByHash(ctx context.Context, db DBTX, hash string) (*domain.ApiKey, errs.CustomError)
+	// FindAll returns every key, oldest first.
+	FindAll(ctx context.Context, db DBTX) ([]*domain.ApiKey, errs.CustomError)
+	// Revoke fails with ErrNotFound when the key does not exist or is already revoked.
+	Revoke(ctx context.Context, db DBTX, keyId uint, revokedAt time.Time) errs.CustomError
+}
+
+// NewApiKeyRepository picks the repository matching the configured database driver.
+func NewApiKeyRepository(cfg *config.Config) (ApiKeyRepository, error) {
+	switch cfg.Database.Driver {
+	case "postgres":
+		return NewApiKeyRepositoryImpl(cfg), nil
+	case "sqlite":
+		return NewSQLiteApiKeyRepositoryImpl(cfg), nil
+	case "memory":
+		return NewMemoryApiKeyRepositoryImpl(), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// conformanceApiKeyRepositorySuite runs the same scenarios against every ApiKeyRepository
+// implementation.
+type conformanceApiKeyRepositorySuite struct {
+	suite.Suite
+	setup func(cfg *config.Config) (ApiKeyRepository, *sql.DB)
+	ar    ApiKeyRepository
+	db    *sql.DB
+	ctx   context.Context
+}
+
+func TestConformanceMemoryApiKeyRepository(t *testing.T) {
+	suite.Run(t, &conformanceApiKeyRepositorySuite{
+		setup: func(cfg *config.Config) (ApiKeyRepository, *sql.DB) {
+			return NewMemoryApiKeyRepositoryImpl(), nil
+		},
+	})
+}
+
+func TestConformanceSQLiteApiKeyRepository(t *testing.T) {
+	s := &conformanceApiKeyRepositorySuite{}
+	s.setup = func(cfg *config.Config) (ApiKeyRepository, *sql.DB) {
+		db := openSQLite(s.T())
+
+		return NewSQLiteApiKeyRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestConformancePostgresApiKeyRepository needs a disposable database, its tables are
+// truncated before every test.
+func TestConformancePostgresApiKeyRepository(t *testing.T) {
+	dsn := testDatabaseURL(t)
+
+	s := &conformanceApiKeyRepositorySuite{}
+	s.setup = func(cfg *config.Config) (ApiKeyRepository, *sql.DB) {
+		db := openPostgres(s.T(), dsn)
+
+		return NewApiKeyRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+func (c *conformanceApiKeyRepositorySuite) SetupTest() {
+	c.ar, c.db = c.setup(config.Default())
+	c.ctx = context.Background()
+}
+
+func (c *conformanceApiKeyRepositorySuite) TearDownTest() {
+	if c.db != nil {
+		c.db.Close()
+	}
+}
+
+func (c *conformanceApiKeyRepositorySuite) TestCreate_FindByHash() {
+	createdAt := time.Date(2024, 6, 15, 8, 30, 0, 0, time.UTC)
+
+	created, err := c.ar.Create(c.ctx, c.db, &domain.ApiKey{Name: "importer", Prefix: "bk_abcdefgh", Hash: "hash-1", Roles: []string{"reader", "editor"}, Tenant: "acme", CreatedAt: createdAt})
+	c.Require().Nil(err)
+
+	found, err := c.ar.FindByHash(c.ctx, c.db, "hash-1")
+	c.Nil(err)
+	c.Equal(created.Id, found.Id)
+	c.Equal("importer", found.Name)
+	c.Equal([]string{"reader", "editor"}, found.Roles)
+	c.Equal("acme", found.Tenant)
+	c.True(createdAt.Equal(found.CreatedAt))
+	c.Nil(found.RevokedAt)
+
+	_, err = c.ar.Create(c.ctx, c.db, &domain.ApiKey{Name: "copy", Prefix: "bk_abcdefgh", Hash: "hash-1", CreatedAt: createdAt})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	_, err = c.ar.FindByHash(c.ctx, c.db, "hash-2")
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceApiKeyRepositorySuite) TestRevoke_Once() {
+	createdAt := time.Date(2024, 6, 15, 8, 30, 0, 0, time.UTC)
+
+	created, err := c.ar.Create(c.ctx, c.db, &domain.ApiKey{Name: "importer", Prefix: "bk_abcdefgh", Hash: "hash-1", CreatedAt: createdAt})
+	c.Require().Nil(err)
+
+	c.Nil(c.ar.Revoke(c.ctx, c.db, created.Id, createdAt.Add(time.Hour)))
+	c.Equal(ErrNotFound, kindOf(c.ar.Revoke(c.ctx, c.db, created.Id, createdAt.Add(2*time.Hour))))
+	c.Equal(ErrNotFound, kindOf(c.ar.Revoke(c.ctx, c.db, created.Id+1, createdAt)))
+
+	keys, err := c.ar.FindAll(c.ctx, c.db)
+	c.Nil(err)
+	c.Require().Len(keys, 1)
+	c.True(createdAt.Add(time.Hour).Equal(*keys[0].RevokedAt))
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type apiKeyRepositoryImpl struct {
+	dialect      *dialect
+	queryTimeout time.Duration
+}
+
+// NewApiKeyRepositoryImpl creates the Postgres repository.
+func NewApiKeyRepositoryImpl(cfg *config.Config) ApiKeyRepository {
+	return &apiKeyRepositoryImpl{dialect: postgresDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+// NewSQLiteApiKeyRepositoryImpl creates the SQLite repository.
+func NewSQLiteApiKeyRepositoryImpl(cfg *config.Config) ApiKeyRepository {
+	return &apiKeyRepositoryImpl{dialect: sqliteDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+func (a *apiKeyRepositoryImpl) Create(ctx context.Context, db DBTX, key *domain.ApiKey) (*domain.ApiKey, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	id, err := a.dialect.insert(queryCtx, db, createApiKeyQuery, key.Name, key.Prefix, key.Hash, joinRoles(key.Roles), key.Tenant, key.CreatedAt)
+	if err != nil {
+		return nil, newError(ctx, "CreateApiKey", err)
+	}
+
+	key.Id = id
+
+	return key, nil
+}
+
+func (a *apiKeyRepositoryImpl) FindByHash(ctx context.Context, db DBTX, hash string) (*domain.ApiKey, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	key, err := scanApiKey(db.QueryRowContext(queryCtx, a.dialect.rebind(findApiKeyByHashQuery), hash))
+	if err != nil {
+		return nil, newError(ctx, "FindApiKeyByHash", err)
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyRepositoryImpl) FindAll(ctx context.Context, db DBTX) ([]*domain.ApiKey, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, a.dialect.rebind(findAllApiKeysQuery))
+	if err != nil {
+		return nil, newError(ctx, "FindAllApiKey", err)
+	}
+	defer rows.Close()
+
+	keys := []*domain.ApiKey{}
+
+	for rows.Next() {
+		key, err := scanApiKey(rows)
+		if err != nil {
+			return nil, newError(ctx, "FindAllApiKey", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindAllApiKey", err)
+	}
+
+	return keys, nil
+}
+
+func (a *apiKeyRepositoryImpl) Revoke(ctx context.Context, db DBTX, keyId uint, revokedAt time.Time) errs.CustomError {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	result, err := db.ExecContext(queryCtx, a.dialect.rebind(revokeApiKeyQuery), revokedAt, keyId)
+	if err != nil {
+		return newError(ctx, "RevokeApiKey", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return newError(ctx, "RevokeApiKey", err)
+	}
+
+	if affected == 0 {
+		return notFound("RevokeApiKey")
+	}
+
+	return nil
+}
+
+func scanApiKey(row interface{ Scan(dest ...any) error }) (*domain.ApiKey, error) {
+	key := &domain.ApiKey{}
+
+	var roles string
+
+	if err := row.Scan(&key.Id, &key.Name, &key.Prefix, &key.Hash, &roles, &key.Tenant, &key.CreatedAt, &key.RevokedAt); err != nil {
+		return nil, err
+	}
+
+	key.Roles = splitRoles(roles)
+
+	return key, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"gin-go-testing/model/domain"
+	"sync"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type memoryApiKeyRepositoryImpl struct {
+	mu     sync.RWMutex
+	keys   []*domain.ApiKey
+	lastId uint
+}
+
+// NewMemoryApiKeyRepositoryImpl creates a thread-safe repository that keeps API keys in
+// memory, they are lost when the process exits. The db argument of its methods is ignored.
+func NewMemoryApiKeyRepositoryImpl() ApiKeyRepository {
+	return &memoryApiKeyRepositoryImpl{}
+}
+
+func (m *memoryApiKeyRepositoryImpl) Create(ctx context.Context, db DBTX, key *domain.ApiKey) (*domain.ApiKey, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.keys {
+		if existing.Hash == key.Hash {
+			return nil, &Error{Op: "CreateApiKey", Kind: ErrUniqueViolation}
+		}
+	}
+
+	m.lastId++
+	key.Id = m.lastId
+
+	m.keys = append(m.keys, copyApiKey(key))
+
+	return key, nil
+}
+
+func (m *memoryApiKeyRepositoryImpl) FindByHash(ctx context.Context, db DBTX, hash string) (*domain.ApiKey, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return copyApiKey(key), nil
+		}
+	}
+
+	return nil, notFound("FindApiKeyByHash")
+}
+
+func (m *memoryApiKeyRepositoryImpl) FindAll(ctx context.Context, db DBTX) ([]*domain.ApiKey, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]*domain.ApiKey, 0, len(m.keys))
+
+	for _, key := range m.keys {
+		keys = append(keys, copyApiKey(key))
+	}
+
+	return keys, nil
+}
+
+func (m *memoryApiKeyRepositoryImpl) Revoke(ctx context.Context, db DBTX, keyId uint, revokedAt time.Time) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range m.keys {
+		if key.Id == keyId && key.RevokedAt == nil {
+			key.RevokedAt = &revokedAt
+			return nil
+		}
+	}
+
+	return notFound("RevokeApiKey")
+}
+
+func copyApiKey(key *domain.ApiKey) *domain.ApiKey {
+	copied := *key
+	copied.Roles = append([]string{}, key.Roles...)
+
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+
+	return &copied
+}
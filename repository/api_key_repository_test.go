@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestApiKeyRepositorySuite struct {
+	suite.Suite
+	ar   ApiKeyRepository
+	mock sqlmock.Sqlmock
+	db   *sql.DB
+	ctx  context.Context
+}
+
+func TestUnitTestApiKeyRepository(t *testing.T) {
+	suite.Run(t, &unitTestApiKeyRepositorySuite{})
+}
+
+func (u *unitTestApiKeyRepositorySuite) SetupTest() {
+	u.ar = NewApiKeyRepositoryImpl(config.Default())
+
+	u.ctx = context.Background()
+	db, mock, _ := sqlmock.New()
+
+	u.mock = mock
+	u.db = db
+}
+
+func (u *unitTestApiKeyRepositorySuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestCreate_Success() {
+	createdAt := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	hash := strings.Repeat("a", 64)
+
+	row := sqlmock.NewRows([]string{"id"}).AddRow(3)
+	u.mock.ExpectQuery(`INSERT INTO api_keys\(name, prefix, key_hash, roles, tenant_id, created_at\) VALUES\(\$1,\$2,\$3,\$4,\$5,\$6\) RETURNING id`).WithArgs("importer", "bk_abcdefgh", hash, "reader,editor", "acme", createdAt).WillReturnRows(row)
+
+	result, err := u.ar.Create(u.ctx, u.db, &domain.ApiKey{Name: "importer", Prefix: "bk_abcdefgh", Hash: hash, Roles: []string{"reader", "editor"}, Tenant: "acme", CreatedAt: createdAt})
+
+	u.Nil(err)
+	u.Equal(&domain.ApiKey{Id: 3, Name: "importer", Prefix: "bk_abcdefgh", Hash: hash, Roles: []string{"reader", "editor"}, Tenant: "acme", CreatedAt: createdAt}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestCreate_UniqueViolation() {
+	u.mock.ExpectQuery(`INSERT INTO api_keys`).WillReturnError(&pq.Error{Code: "23505"})
+
+	result, err := u.ar.Create(u.ctx, u.db, &domain.ApiKey{Name: "importer"})
+
+	u.Nil(result)
+	u.Equal(ErrUniqueViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestFindByHash_NotFound() {
+	u.mock.ExpectQuery(`SELECT id, name, prefix, key_hash, roles, tenant_id, created_at, revoked_at FROM api_keys WHERE key_hash=\$1`).WithArgs("unknown").WillReturnError(sql.ErrNoRows)
+
+	result, err := u.ar.FindByHash(u.ctx, u.db, "unknown")
+
+	u.Nil(result)
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestFindAll_Success() {
+	createdAt := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	revokedAt := createdAt.Add(time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "roles", "tenant_id", "created_at", "revoked_at"}).
+		AddRow(1, "importer", "bk_abcdefgh", "hash-1", "editor", "acme", createdAt, revokedAt).
+		AddRow(2, "backup", "bk_ijklmnop", "hash-2", "", "default", createdAt, nil)
+	u.mock.ExpectQuery(`SELECT id, name, prefix, key_hash, roles, tenant_id, created_at, revoked_at FROM api_keys ORDER BY id ASC`).WillReturnRows(rows).RowsWillBeClosed()
+
+	result, err := u.ar.FindAll(u.ctx, u.db)
+
+	u.Nil(err)
+	u.Equal([]*domain.ApiKey{
+		{Id: 1, Name: "importer", Prefix: "bk_abcdefgh", Hash: "hash-1", Roles: []string{"editor"}, Tenant: "acme", CreatedAt: createdAt, RevokedAt: &revokedAt},
+		{Id: 2, Name: "backup", Prefix: "bk_ijklmnop", Hash: "hash-2", Roles: []string{}, Tenant: "default", CreatedAt: createdAt},
+	}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestRevoke_Success() {
+	revokedAt := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	u.mock.ExpectExec(`UPDATE api_keys SET revoked_at=\$1 WHERE id=\$2 AND revoked_at IS NULL`).WithArgs(revokedAt, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := u.ar.Revoke(u.ctx, u.db, 3, revokedAt)
+
+	u.Nil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestApiKeyRepositorySuite) TestRevoke_NotFound() {
+	u.mock.ExpectExec(`UPDATE api_keys SET revoked_at`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := u.ar.Revoke(u.ctx, u.db, 9, time.Now())
+
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
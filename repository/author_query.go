@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"fmt"
+	"gin-go-testing/model/domain"
+	"strings"
+)
+
+// book_authors has no tenant of its own, its rows are reached through the books and authors
+// of the tenant.
+const (
+	findAuthorByIdQuery      = `SELECT id, name FROM authors WHERE id=$1 AND tenant_id=$2`
+	findAuthorByNameKeyQuery = `SELECT id, name FROM authors WHERE name_key=$1 AND tenant_id=$2`
+	findAuthorsByIdsQuery    = `SELECT id, name FROM authors WHERE tenant_id=$1 AND id IN (%s)`
+	findAllAuthorsQuery      = `SELECT id, name FROM authors`
+	countAuthorsQuery        = `SELECT COUNT(*) FROM authors`
+	createAuthorQuery        = `INSERT INTO authors(name, name_key, tenant_id) VALUES($1,$2,$3)`
+	ensureAuthorQuery        = `INSERT INTO authors(name, name_key, tenant_id) VALUES($1,$2,$3) ON CONFLICT (tenant_id, name_key) DO NOTHING`
+	updateAuthorQuery        = `UPDATE authors SET name=$1, name_key=$2 WHERE id=$3 AND tenant_id=$4`
+	deleteAuthorQuery        = `DELETE FROM authors WHERE id=$1 AND tenant_id=$2`
+	findBookAuthorsQuery     = `SELECT book_authors.book_id, authors.id, authors.name FROM book_authors JOIN authors ON authors.id = book_authors.author_id WHERE authors.tenant_id=$1 AND book_authors.book_id IN (%s) ORDER BY book_authors.book_id, book_authors.position`
+	findAuthorBookIdsQuery   = `SELECT book_id FROM book_authors WHERE author_id=$1 AND author_id IN (SELECT id FROM authors WHERE tenant_id=$2) ORDER BY book_id`
+	deleteBookAuthorsQuery   = `DELETE FROM book_authors WHERE book_id=$1 AND book_id IN (SELECT id FROM books WHERE tenant_id=$2)`
+	// createBookAuthorQuery only links a book and an author of tenant $4
+	createBookAuthorQuery = `INSERT INTO book_authors(book_id, author_id, position) SELECT books.id, authors.id, CAST($3 AS INTEGER) FROM books, authors WHERE books.id=$1 AND books.tenant_id=$4 AND authors.id=$2 AND authors.tenant_id=$4`
+)
+
+var authorKeyRemover = strings.NewReplacer(" ", "", ".", "")
+
+// authorKey is what two spellings of the same author share, the name lowercased without
+// spaces and dots. Migration 4 computes it in SQL for the authors it creates.
+func authorKey(name string) string {
+	return strings.ToLower(authorKeyRemover.Replace(name))
+}
+
+// buildFindAllAuthorsQuery builds the author listing query, ordered by name.
+func buildFindAllAuthorsQuery(tenantId string, params *domain.AuthorListParams) (string, []any) {
+	conditions, args := buildAuthorFilter(tenantId, params)
+	query := findAllAuthorsQuery + buildWhere(conditions) + " ORDER BY name ASC, id ASC"
+
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args
+}
+
+// buildCountAuthorsQuery counts the authors matched by the listing filter, ignoring paging.
+func buildCountAuthorsQuery(tenantId string, params *domain.AuthorListParams) (string, []any) {
+	conditions, args := buildAuthorFilter(tenantId, params)
+
+	return countAuthorsQuery + buildWhere(conditions), args
+}
+
+func buildAuthorFilter(tenantId string, params *domain.AuthorListParams) ([]string, []any) {
+	conditions := []string{"tenant_id=$1"}
+	args := []any{tenantId}
+
+	if params.NameContains != "" {
+		args = append(args, "%"+escapeLike(params.NameContains)+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(name) LIKE LOWER($%d) ESCAPE '\'`, len(args)))
+	}
+
+	return conditions, args
+}
+
+// buildInQuery fills the IN list of query with one placeholder per id, the tenant id is
+// bound to $1.
+func buildInQuery(query string, tenantId string, ids []uint) (string, []any) {
+	placeholders := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, tenantId)
+
+	for _, id := range ids {
+		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	return fmt.Sprintf(query, strings.Join(placeholders, ",")), args
+}
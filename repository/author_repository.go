@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// AuthorRepository stores authors and who is credited on which book. Every method only sees
+// the authors and books of the tenant carried by ctx, see tenant.FromContext.
+type AuthorRepository interface {
+	Create(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError)
+	// Ensure returns the author name is a spelling of, creating it when there is none.
+	Ensure(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError)
+	FindOneById(ctx context.Context, db DBTX, authorId uint) (*domain.Author, errs.CustomError)
+	// FindByName finds the author name is a spelling of.
+	FindByName(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError)
+	// FindByIds returns the authors with the given ids that exist, in no particular order.
+	FindByIds(ctx context.Context, db DBTX, authorIds []uint) ([]*domain.Author, errs.CustomError)
+	FindAll(ctx context.Context, db DBTX, params *domain.AuthorListParams) ([]*domain.Author, errs.CustomError)
+	Count(ctx context.Context, db DBTX, params *domain.AuthorListParams) (uint, errs.CustomError)
+	Update(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError)
+	// Delete fails with ErrForeignKeyViolation while the author is credited on a book.
+	Delete(ctx context.Context, db DBTX, authorId uint) errs.CustomError
+	// FindByBookIds returns the authors of each of the books, in byline order. Books without
+	// authors are left out of the map.
+	FindByBookIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]*domain.Author, errs.CustomError)
+	// FindBookIds returns the ids of the books the author is credited on.
+	FindBookIds(ctx context.Context, db DBTX, authorId uint) ([]uint, errs.CustomError)
+	// SetBookAuthors replaces the authors of a book, given in byline order. It fails with
+	// ErrForeignKeyViolation when an author is missing.
+	SetBookAuthors(ctx context.Context, db DBTX, bookId uint, authorIds []uint) errs.CustomError
+}
+
+// NewAuthorRepository picks the repository matching the configured database driver.
+func NewAuthorRepository(cfg *config.Config) (AuthorRepository, error) {
+	switch cfg.Database.Driver {
+	case "postgres":
+		return NewAuthorRepositoryImpl(cfg), nil
+	case "sqlite":
+		return NewSQLiteAuthorRepositoryImpl(cfg), nil
+	case "memory":
+		return NewMemoryAuthorRepositoryImpl(), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+}
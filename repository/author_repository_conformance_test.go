@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/migration"
+	"gin-go-testing/model/domain"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// conformanceAuthorRepositorySuite runs the same scenarios against every AuthorRepository
+// implementation, paired with the book repository of the same backend.
+type conformanceAuthorRepositorySuite struct {
+	suite.Suite
+	setup func(cfg *config.Config) (AuthorRepository, BookRepository, *sql.DB)
+	ar    AuthorRepository
+	br    BookRepository
+	db    *sql.DB
+	// driver names the migrations of db
+	driver string
+	ctx    context.Context
+}
+
+func TestConformanceMemoryAuthorRepository(t *testing.T) {
+	suite.Run(t, &conformanceAuthorRepositorySuite{
+		setup: func(cfg *config.Config) (AuthorRepository, BookRepository, *sql.DB) {
+			return NewMemoryAuthorRepositoryImpl(), NewMemoryBookRepositoryImpl(cfg), nil
+		},
+	})
+}
+
+func TestConformanceSQLiteAuthorRepository(t *testing.T) {
+	s := &conformanceAuthorRepositorySuite{driver: "sqlite"}
+	s.setup = func(cfg *config.Config) (AuthorRepository, BookRepository, *sql.DB) {
+		db := openSQLite(s.T())
+
+		return NewSQLiteAuthorRepositoryImpl(cfg), NewSQLiteBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestConformancePostgresAuthorRepository needs a disposable database, its tables are
+// truncated before every test.
+func TestConformancePostgresAuthorRepository(t *testing.T) {
+	dsn := testDatabaseURL(t)
+
+	s := &conformanceAuthorRepositorySuite{driver: "postgres"}
+	s.setup = func(cfg *config.Config) (AuthorRepository, BookRepository, *sql.DB) {
+		db := openPostgres(s.T(), dsn)
+
+		return NewAuthorRepositoryImpl(cfg), NewBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+func (c *conformanceAuthorRepositorySuite) SetupTest() {
+	c.ar, c.br, c.db = c.setup(config.Default())
+	c.ctx = context.Background()
+}
+
+func (c *conformanceAuthorRepositorySuite) TearDownTest() {
+	if c.db != nil {
+		c.db.Close()
+	}
+}
+
+func (c *conformanceAuthorRepositorySuite) createAuthors(names ...string) []*domain.Author {
+	var created []*domain.Author
+
+	for _, name := range names {
+		result, err := c.ar.Create(c.ctx, c.db, &domain.Author{Name: name})
+		c.Require().Nil(err)
+
+		created = append(created, result)
+	}
+
+	return created
+}
+
+func (c *conformanceAuthorRepositorySuite) createBook(title string, authors ...*domain.Author) *domain.Book {
+	book, err := c.br.Create(c.ctx, c.db, &domain.Book{Title: title, Author: domain.Byline(authors)})
+	c.Require().Nil(err)
+
+	c.Require().Nil(c.ar.SetBookAuthors(c.ctx, c.db, book.Id, authorIdsOf(authors)))
+
+	return book
+}
+
+func (c *conformanceAuthorRepositorySuite) TestCreate_SpellingsAreOneAuthor() {
+	created := c.createAuthors("J.R.R. Tolkien")
+
+	_, err := c.ar.Create(c.ctx, c.db, &domain.Author{Name: "J. R. R. Tolkien"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	found, err := c.ar.FindByName(c.ctx, c.db, "j r r tolkien")
+	c.Nil(err)
+	c.Equal(created[0], found)
+}
+
+func (c *conformanceAuthorRepositorySuite) TestEnsure_ReusesOrCreates() {
+	created := c.createAuthors("Stephen R. Covey")
+
+	existing, err := c.ar.Ensure(c.ctx, c.db, "Stephen R Covey")
+	c.Nil(err)
+	c.Equal(created[0], existing)
+
+	other, err := c.ar.Ensure(c.ctx, c.db, "James Clear")
+	c.Nil(err)
+	c.NotEqual(created[0].Id, other.Id)
+	c.Equal("James Clear", other.Name)
+}
+
+func (c *conformanceAuthorRepositorySuite) TestFindAll_FilterSortAndPage() {
+	c.createAuthors("Terry Pratchett", "Neil Gaiman", "Cal Newport", "Neal Stephenson")
+
+	params := &domain.AuthorListParams{NameContains: "ne", Limit: 2, Offset: 1}
+
+	result, err := c.ar.FindAll(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal([]string{"Neal Stephenson", "Neil Gaiman"}, authorNames(result))
+
+	total, err := c.ar.Count(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal(uint(3), total)
+}
+
+func (c *conformanceAuthorRepositorySuite) TestUpdate_Rename() {
+	created := c.createAuthors("Stephen Covey", "James Clear")
+
+	_, err := c.ar.Update(c.ctx, c.db, &domain.Author{Id: created[0].Id, Name: "Stephen R. Covey"})
+	c.Nil(err)
+
+	_, err = c.ar.Update(c.ctx, c.db, &domain.Author{Id: created[0].Id, Name: "james clear"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	found, err := c.ar.FindByName(c.ctx, c.db, "Stephen R. Covey")
+	c.Nil(err)
+	c.Equal(created[0].Id, found.Id)
+}
+
+func (c *conformanceAuthorRepositorySuite) TestBookAuthors_KeepBylineOrder() {
+	authors := c.createAuthors("Neil Gaiman", "Terry Pratchett")
+	omens := c.createBook("Good Omens", authors[1], authors[0])
+	sandman := c.createBook("The Sandman", authors[0])
+	unlinked := c.createBook("Anonymous")
+
+	result, err := c.ar.FindByBookIds(c.ctx, c.db, []uint{omens.Id, sandman.Id, unlinked.Id})
+	c.Nil(err)
+	c.Equal(map[uint][]*domain.Author{
+		omens.Id:   {authors[1], authors[0]},
+		sandman.Id: {authors[0]},
+	}, result)
+
+	bookIds, err := c.ar.FindBookIds(c.ctx, c.db, authors[0].Id)
+	c.Nil(err)
+	c.Equal([]uint{omens.Id, sandman.Id}, bookIds)
+
+	c.Nil(c.ar.SetBookAuthors(c.ctx, c.db, omens.Id, []uint{authors[0].Id}))
+
+	result, err = c.ar.FindByBookIds(c.ctx, c.db, []uint{omens.Id})
+	c.Nil(err)
+	c.Equal(map[uint][]*domain.Author{omens.Id: {authors[0]}}, result)
+}
+
+func (c *conformanceAuthorRepositorySuite) TestDelete_OnlyWhenUncredited() {
+	authors := c.createAuthors("Frank Herbert")
+	book := c.createBook("Dune", authors[0])
+
+	c.Equal(ErrForeignKeyViolation, kindOf(c.ar.Delete(c.ctx, c.db, authors[0].Id)))
+
+	c.Nil(c.ar.SetBookAuthors(c.ctx, c.db, book.Id, nil))
+	c.Nil(c.ar.Delete(c.ctx, c.db, authors[0].Id))
+
+	_, err := c.ar.FindOneById(c.ctx, c.db, authors[0].Id)
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+// TestMigration_DedupesExistingAuthors replays migration 4 over books written before it.
+func (c *conformanceAuthorRepositorySuite) TestMigration_DedupesExistingAuthors() {
+	if c.db == nil {
+		c.T().Skip("the in-memory repository has no migrations")
+	}
+
+	source, err := migration.Source(c.driver)
+	c.Require().NoError(err)
+
+	migrator, err := migration.New(c.db, c.driver, source)
+	c.Require().NoError(err)
+	c.Require().NoError(migrator.To(c.ctx, 3))
+
+	// the books are written the way they were at version 3, the repository has moved on
+	dialect := postgresDialect
+	if c.driver == "sqlite" {
+		dialect = sqliteDialect
+	}
+
+	for _, author := range []string{"J.R.R. Tolkien", "J. R. R. Tolkien", "Frank Herbert"} {
+		_, errCreate := c.db.ExecContext(c.ctx, dialect.rebind(`INSERT INTO books(title, author) VALUES($1,$2)`), "Book by "+author, author)
+		c.Require().NoError(errCreate)
+	}
+
+	c.Require().NoError(migrator.Up(c.ctx))
+
+	authors, errFind := c.ar.FindAll(c.ctx, c.db, &domain.AuthorListParams{})
+	c.Nil(errFind)
+	c.Equal([]string{"Frank Herbert", "J. R. R. Tolkien"}, authorNames(authors))
+
+	bookAuthors, errFind := c.ar.FindByBookIds(c.ctx, c.db, []uint{1, 2, 3})
+	c.Nil(errFind)
+	c.Equal(bookAuthors[1], bookAuthors[2])
+	c.Equal("Frank Herbert", bookAuthors[3][0].Name)
+}
+
+func authorNames(authors []*domain.Author) []string {
+	names := []string{}
+
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+
+	return names
+}
+
+// authorIdsOf mirrors the service helper, the tests link books the way it does.
+func authorIdsOf(authors []*domain.Author) []uint {
+	ids := make([]uint, 0, len(authors))
+
+	for _, author := range authors {
+		ids = append(ids, author.Id)
+	}
+
+	return ids
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type authorRepositoryImpl struct {
+	dialect      *dialect
+	queryTimeout time.Duration
+}
+
+// NewAuthorRepositoryImpl creates the Postgres repository.
+func NewAuthorRepositoryImpl(cfg *config.Config) AuthorRepository {
+	return &authorRepositoryImpl{dialect: postgresDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+// NewSQLiteAuthorRepositoryImpl creates the SQLite repository.
+func NewSQLiteAuthorRepositoryImpl(cfg *config.Config) AuthorRepository {
+	return &authorRepositoryImpl{dialect: sqliteDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+func (a *authorRepositoryImpl) Create(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	id, err := a.dialect.insert(queryCtx, db, createAuthorQuery, author.Name, authorKey(author.Name), tenant.FromContext(ctx))
+	if err != nil {
+		return nil, newError(ctx, "CreateAuthor", err)
+	}
+
+	author.Id = id
+
+	return author, nil
+}
+
+func (a *authorRepositoryImpl) Ensure(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	// inserting first and reading back is race free, a concurrent insert of the same
+	// author makes this one do nothing
+	if _, err := db.ExecContext(queryCtx, a.dialect.rebind(ensureAuthorQuery), name, authorKey(name), tenant.FromContext(ctx)); err != nil {
+		return nil, newError(ctx, "EnsureAuthor", err)
+	}
+
+	author := new(domain.Author)
+
+	err := db.QueryRowContext(queryCtx, a.dialect.rebind(findAuthorByNameKeyQuery), authorKey(name), tenant.FromContext(ctx)).Scan(&author.Id, &author.Name)
+	if err != nil {
+		return nil, newError(ctx, "EnsureAuthor", err)
+	}
+
+	return author, nil
+}
+
+func (a *authorRepositoryImpl) FindOneById(ctx context.Context, db DBTX, authorId uint) (*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	author := new(domain.Author)
+
+	err := db.QueryRowContext(queryCtx, a.dialect.rebind(findAuthorByIdQuery), authorId, tenant.FromContext(ctx)).Scan(&author.Id, &author.Name)
+	if err != nil {
+		return nil, newError(ctx, "FindOneAuthorById", err)
+	}
+
+	return author, nil
+}
+
+func (a *authorRepositoryImpl) FindByName(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	author := new(domain.Author)
+
+	err := db.QueryRowContext(queryCtx, a.dialect.rebind(findAuthorByNameKeyQuery), authorKey(name), tenant.FromContext(ctx)).Scan(&author.Id, &author.Name)
+	if err != nil {
+		return nil, newError(ctx, "FindAuthorByName", err)
+	}
+
+	return author, nil
+}
+
+func (a *authorRepositoryImpl) FindByIds(ctx context.Context, db DBTX, authorIds []uint) ([]*domain.Author, errs.CustomError) {
+	if len(authorIds) == 0 {
+		return []*domain.Author{}, nil
+	}
+
+	query, args := buildInQuery(findAuthorsByIdsQuery, tenant.FromContext(ctx), authorIds)
+
+	return a.findAuthors(ctx, db, "FindAuthorsByIds", query, args)
+}
+
+func (a *authorRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.AuthorListParams) ([]*domain.Author, errs.CustomError) {
+	query, args := buildFindAllAuthorsQuery(tenant.FromContext(ctx), params)
+
+	return a.findAuthors(ctx, db, "FindAllAuthor", query, args)
+}
+
+func (a *authorRepositoryImpl) findAuthors(ctx context.Context, db DBTX, op string, query string, args []any) ([]*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, a.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	authors := []*domain.Author{}
+
+	for rows.Next() {
+		author := &domain.Author{}
+
+		if err := rows.Scan(&author.Id, &author.Name); err != nil {
+			return nil, newError(ctx, op, err)
+		}
+
+		authors = append(authors, author)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, op, err)
+	}
+
+	return authors, nil
+}
+
+func (a *authorRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.AuthorListParams) (uint, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	var total uint
+
+	query, args := buildCountAuthorsQuery(tenant.FromContext(ctx), params)
+
+	if err := db.QueryRowContext(queryCtx, a.dialect.rebind(query), args...).Scan(&total); err != nil {
+		return 0, newError(ctx, "CountAuthor", err)
+	}
+
+	return total, nil
+}
+
+func (a *authorRepositoryImpl) Update(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	result, err := db.ExecContext(queryCtx, a.dialect.rebind(updateAuthorQuery), author.Name, authorKey(author.Name), author.Id, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, newError(ctx, "UpdateAuthor", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, newError(ctx, "UpdateAuthor", err)
+	}
+
+	if affected == 0 {
+		return nil, notFound("UpdateAuthor")
+	}
+
+	return author, nil
+}
+
+func (a *authorRepositoryImpl) Delete(ctx context.Context, db DBTX, authorId uint) errs.CustomError {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	// SQLite only enforces foreign keys when asked to, so the books are checked here too
+	bookIds, errFind := a.FindBookIds(ctx, db, authorId)
+	if errFind != nil {
+		return errFind
+	}
+
+	if len(bookIds) > 0 {
+		return &Error{Op: "DeleteAuthor", Kind: ErrForeignKeyViolation}
+	}
+
+	result, err := db.ExecContext(queryCtx, a.dialect.rebind(deleteAuthorQuery), authorId, tenant.FromContext(ctx))
+	if err != nil {
+		return newError(ctx, "DeleteAuthor", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return newError(ctx, "DeleteAuthor", err)
+	}
+
+	if affected == 0 {
+		return notFound("DeleteAuthor")
+	}
+
+	return nil
+}
+
+func (a *authorRepositoryImpl) FindByBookIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]*domain.Author, errs.CustomError) {
+	authors := map[uint][]*domain.Author{}
+
+	if len(bookIds) == 0 {
+		return authors, nil
+	}
+
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	query, args := buildInQuery(findBookAuthorsQuery, tenant.FromContext(ctx), bookIds)
+
+	rows, err := db.QueryContext(queryCtx, a.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "FindAuthorsByBookIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookId uint
+		author := &domain.Author{}
+
+		if err := rows.Scan(&bookId, &author.Id, &author.Name); err != nil {
+			return nil, newError(ctx, "FindAuthorsByBookIds", err)
+		}
+
+		authors[bookId] = append(authors[bookId], author)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindAuthorsByBookIds", err)
+	}
+
+	return authors, nil
+}
+
+func (a *authorRepositoryImpl) FindBookIds(ctx context.Context, db DBTX, authorId uint) ([]uint, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, a.dialect.rebind(findAuthorBookIdsQuery), authorId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, newError(ctx, "FindAuthorBookIds", err)
+	}
+	defer rows.Close()
+
+	bookIds := []uint{}
+
+	for rows.Next() {
+		var bookId uint
+
+		if err := rows.Scan(&bookId); err != nil {
+			return nil, newError(ctx, "FindAuthorBookIds", err)
+		}
+
+		bookIds = append(bookIds, bookId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindAuthorBookIds", err)
+	}
+
+	return bookIds, nil
+}
+
+func (a *authorRepositoryImpl) SetBookAuthors(ctx context.Context, db DBTX, bookId uint, authorIds []uint) errs.CustomError {
+	queryCtx, cancel := withTimeout(ctx, a.queryTimeout)
+	defer cancel()
+
+	tenantId := tenant.FromContext(ctx)
+
+	if _, err := db.ExecContext(queryCtx, a.dialect.rebind(deleteBookAuthorsQuery), bookId, tenantId); err != nil {
+		return newError(ctx, "SetBookAuthors", err)
+	}
+
+	for position, authorId := range authorIds {
+		result, err := db.ExecContext(queryCtx, a.dialect.rebind(createBookAuthorQuery), bookId, authorId, position, tenantId)
+		if err != nil {
+			return newError(ctx, "SetBookAuthors", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return newError(ctx, "SetBookAuthors", err)
+		}
+
+		// the book or the author is missing or belongs to another tenant
+		if affected == 0 {
+			return &Error{Op: "SetBookAuthors", Kind: ErrForeignKeyViolation}
+		}
+	}
+
+	return nil
+}
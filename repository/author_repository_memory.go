@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type memoryAuthorRepositoryImpl struct {
+	mu      sync.RWMutex
+	authors map[uint]*domain.Author
+	lastId  uint
+	// tenants holds the tenant of each author, the in-memory tenant_id column
+	tenants map[uint]string
+	// keys indexes the ids of authors by tenant and authorKey, the in-memory unique index
+	keys map[tenantKey]uint
+	// bookAuthors holds the author ids of each book in byline order
+	bookAuthors map[uint][]uint
+}
+
+// NewMemoryAuthorRepositoryImpl creates a thread-safe repository that keeps authors in memory,
+// the companion of the in-memory book repository. The db argument of its methods is ignored.
+func NewMemoryAuthorRepositoryImpl() AuthorRepository {
+	return &memoryAuthorRepositoryImpl{
+		authors:     map[uint]*domain.Author{},
+		tenants:     map[uint]string{},
+		keys:        map[tenantKey]uint{},
+		bookAuthors: map[uint][]uint{},
+	}
+}
+
+func (m *memoryAuthorRepositoryImpl) Create(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenantId := tenant.FromContext(ctx)
+
+	if _, ok := m.keys[tenantKey{tenantId, authorKey(author.Name)}]; ok {
+		return nil, &Error{Op: "CreateAuthor", Kind: ErrUniqueViolation}
+	}
+
+	m.lastId++
+	author.Id = m.lastId
+
+	stored := *author
+	m.store(tenantId, &stored)
+
+	return author, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) Ensure(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenantId := tenant.FromContext(ctx)
+
+	if id, ok := m.keys[tenantKey{tenantId, authorKey(name)}]; ok {
+		found := *m.authors[id]
+		return &found, nil
+	}
+
+	m.lastId++
+	m.store(tenantId, &domain.Author{Id: m.lastId, Name: name})
+
+	return &domain.Author{Id: m.lastId, Name: name}, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindOneById(ctx context.Context, db DBTX, authorId uint) (*domain.Author, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	author, ok := m.owned(ctx, authorId)
+	if !ok {
+		return nil, notFound("FindOneAuthorById")
+	}
+
+	found := *author
+
+	return &found, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindByName(ctx context.Context, db DBTX, name string) (*domain.Author, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.keys[tenantKey{tenant.FromContext(ctx), authorKey(name)}]
+	if !ok {
+		return nil, notFound("FindAuthorByName")
+	}
+
+	found := *m.authors[id]
+
+	return &found, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindByIds(ctx context.Context, db DBTX, authorIds []uint) ([]*domain.Author, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	authors := []*domain.Author{}
+
+	for _, id := range authorIds {
+		if author, ok := m.owned(ctx, id); ok {
+			found := *author
+			authors = append(authors, &found)
+		}
+	}
+
+	return authors, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.AuthorListParams) ([]*domain.Author, errs.CustomError) {
+	m.mu.RLock()
+	authors := m.filter(tenant.FromContext(ctx), params)
+	m.mu.RUnlock()
+
+	slices.SortFunc(authors, func(a, b *domain.Author) int {
+		if order := strings.Compare(a.Name, b.Name); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	authors = authors[min(params.Offset, uint(len(authors))):]
+
+	if params.Limit > 0 {
+		authors = authors[:min(params.Limit, uint(len(authors)))]
+	}
+
+	return authors, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.AuthorListParams) (uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return uint(len(m.filter(tenant.FromContext(ctx), params))), nil
+}
+
+func (m *memoryAuthorRepositoryImpl) Update(ctx context.Context, db DBTX, author *domain.Author) (*domain.Author, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.owned(ctx, author.Id); !ok {
+		return nil, notFound("UpdateAuthor")
+	}
+
+	tenantId := tenant.FromContext(ctx)
+
+	if id, ok := m.keys[tenantKey{tenantId, authorKey(author.Name)}]; ok && id != author.Id {
+		return nil, &Error{Op: "UpdateAuthor", Kind: ErrUniqueViolation}
+	}
+
+	stored := *author
+	m.store(tenantId, &stored)
+
+	return author, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) Delete(ctx context.Context, db DBTX, authorId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.owned(ctx, authorId)
+	if !ok {
+		return notFound("DeleteAuthor")
+	}
+
+	for _, authorIds := range m.bookAuthors {
+		if slices.Contains(authorIds, authorId) {
+			return &Error{Op: "DeleteAuthor", Kind: ErrForeignKeyViolation}
+		}
+	}
+
+	delete(m.keys, tenantKey{m.tenants[authorId], authorKey(stored.Name)})
+	delete(m.authors, authorId)
+	delete(m.tenants, authorId)
+
+	return nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindByBookIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]*domain.Author, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	authors := map[uint][]*domain.Author{}
+
+	for _, bookId := range bookIds {
+		for _, authorId := range m.bookAuthors[bookId] {
+			// books are only ever credited to authors of their own tenant
+			author, ok := m.owned(ctx, authorId)
+			if !ok {
+				break
+			}
+
+			found := *author
+			authors[bookId] = append(authors[bookId], &found)
+		}
+	}
+
+	return authors, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) FindBookIds(ctx context.Context, db DBTX, authorId uint) ([]uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bookIds := []uint{}
+
+	if _, ok := m.owned(ctx, authorId); !ok {
+		return bookIds, nil
+	}
+
+	for bookId, authorIds := range m.bookAuthors {
+		if slices.Contains(authorIds, authorId) {
+			bookIds = append(bookIds, bookId)
+		}
+	}
+
+	slices.Sort(bookIds)
+
+	return bookIds, nil
+}
+
+func (m *memoryAuthorRepositoryImpl) SetBookAuthors(ctx context.Context, db DBTX, bookId uint, authorIds []uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the books are kept by the book repository, only the tenant of the authors can be checked
+	for _, authorId := range authorIds {
+		if _, ok := m.owned(ctx, authorId); !ok {
+			return &Error{Op: "SetBookAuthors", Kind: ErrForeignKeyViolation}
+		}
+	}
+
+	if len(authorIds) == 0 {
+		delete(m.bookAuthors, bookId)
+		return nil
+	}
+
+	m.bookAuthors[bookId] = slices.Clone(authorIds)
+
+	return nil
+}
+
+// owned returns the stored author with authorId when it belongs to the tenant of ctx, the
+// caller must hold the lock.
+func (m *memoryAuthorRepositoryImpl) owned(ctx context.Context, authorId uint) (*domain.Author, bool) {
+	author, ok := m.authors[authorId]
+
+	return author, ok && m.tenants[authorId] == tenant.FromContext(ctx)
+}
+
+// store saves author for the tenant and reindexes its key, the caller must hold the write
+// lock.
+func (m *memoryAuthorRepositoryImpl) store(tenantId string, author *domain.Author) {
+	if previous, ok := m.authors[author.Id]; ok {
+		delete(m.keys, tenantKey{tenantId, authorKey(previous.Name)})
+	}
+
+	m.keys[tenantKey{tenantId, authorKey(author.Name)}] = author.Id
+	m.authors[author.Id] = author
+	m.tenants[author.Id] = tenantId
+}
+
+// filter returns copies of the authors of the tenant matching params, the caller must hold
+// the lock.
+func (m *memoryAuthorRepositoryImpl) filter(tenantId string, params *domain.AuthorListParams) []*domain.Author {
+	authors := []*domain.Author{}
+	nameContains := strings.ToLower(params.NameContains)
+
+	for _, author := range m.authors {
+		if m.tenants[author.Id] != tenantId {
+			continue
+		}
+
+		if nameContains != "" && !strings.Contains(strings.ToLower(author.Name), nameContains) {
+			continue
+		}
+
+		found := *author
+		authors = append(authors, &found)
+	}
+
+	return authors
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAuthorRepositorySuite struct {
+	suite.Suite
+	ar   AuthorRepository
+	mock sqlmock.Sqlmock
+	db   *sql.DB
+	ctx  context.Context
+}
+
+func TestUnitTestAuthorRepository(t *testing.T) {
+	suite.Run(t, &unitTestAuthorRepositorySuite{})
+}
+
+func (u *unitTestAuthorRepositorySuite) SetupTest() {
+	u.ar = NewAuthorRepositoryImpl(config.Default())
+
+	u.ctx = context.Background()
+	db, mock, _ := sqlmock.New()
+
+	u.mock = mock
+	u.db = db
+}
+
+func (u *unitTestAuthorRepositorySuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestAuthorRepositorySuite) TestCreate_Success() {
+	row := sqlmock.NewRows([]string{"id"}).AddRow(3)
+	u.mock.ExpectQuery(`INSERT INTO authors\(name, name_key, tenant_id\) VALUES\(\$1,\$2,\$3\) RETURNING id`).WithArgs("Stephen R. Covey", "stephenrcovey", "default").WillReturnRows(row)
+
+	result, err := u.ar.Create(u.ctx, u.db, &domain.Author{Name: "Stephen R. Covey"})
+
+	u.Nil(err)
+	u.Equal(&domain.Author{Id: 3, Name: "Stephen R. Covey"}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestCreate_UniqueViolation() {
+	u.mock.ExpectQuery(`INSERT INTO authors`).WillReturnError(&pq.Error{Code: "23505"})
+
+	result, err := u.ar.Create(u.ctx, u.db, &domain.Author{Name: "Stephen Covey"})
+
+	u.Nil(result)
+	u.Equal(ErrUniqueViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestEnsure_ExistingSpelling() {
+	u.mock.ExpectExec(`INSERT INTO authors\(name, name_key, tenant_id\) VALUES\(\$1,\$2,\$3\) ON CONFLICT \(tenant_id, name_key\) DO NOTHING`).WithArgs("stephen r covey", "stephenrcovey", "default").WillReturnResult(sqlmock.NewResult(0, 0))
+	u.mock.ExpectQuery(`SELECT id, name FROM authors WHERE name_key=\$1 AND tenant_id=\$2`).WithArgs("stephenrcovey", "default").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Stephen R. Covey"))
+
+	result, err := u.ar.Ensure(u.ctx, u.db, "stephen r covey")
+
+	u.Nil(err)
+	u.Equal(&domain.Author{Id: 3, Name: "Stephen R. Covey"}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindOneById_NotFound() {
+	u.mock.ExpectQuery(`SELECT id, name FROM authors WHERE id=\$1 AND tenant_id=\$2`).WithArgs(9, "default").WillReturnError(sql.ErrNoRows)
+
+	result, err := u.ar.FindOneById(u.ctx, u.db, 9)
+
+	u.Nil(result)
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindByIds_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Neil Gaiman").AddRow(3, "Terry Pratchett")
+	u.mock.ExpectQuery(`SELECT id, name FROM authors WHERE tenant_id=\$1 AND id IN \(\$2,\$3\)`).WithArgs("default", 3, 2).WillReturnRows(rows)
+
+	result, err := u.ar.FindByIds(u.ctx, u.db, []uint{3, 2})
+
+	u.Nil(err)
+	u.Equal([]*domain.Author{{Id: 2, Name: "Neil Gaiman"}, {Id: 3, Name: "Terry Pratchett"}}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindAll_FilterAndPage() {
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Neil Gaiman")
+	u.mock.ExpectQuery(`SELECT id, name FROM authors WHERE tenant_id=\$1 AND LOWER\(name\) LIKE LOWER\(\$2\) ESCAPE '\\' ORDER BY name ASC, id ASC LIMIT \$3 OFFSET \$4`).
+		WithArgs("default", `%gai\_%`, 10, 20).WillReturnRows(rows)
+
+	result, err := u.ar.FindAll(u.ctx, u.db, &domain.AuthorListParams{Limit: 10, Offset: 20, NameContains: "gai_"})
+
+	u.Nil(err)
+	u.Equal([]*domain.Author{{Id: 2, Name: "Neil Gaiman"}}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindAll_RowsError() {
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Neil Gaiman").RowError(0, errors.New("connection reset"))
+	u.mock.ExpectQuery(`SELECT id, name FROM authors WHERE tenant_id=\$1 ORDER BY name ASC, id ASC`).WithArgs("default").WillReturnRows(rows)
+
+	result, err := u.ar.FindAll(u.ctx, u.db, &domain.AuthorListParams{})
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestUpdate_NotFound() {
+	u.mock.ExpectExec(`UPDATE authors SET name=\$1, name_key=\$2 WHERE id=\$3 AND tenant_id=\$4`).WithArgs("Neil Gaiman", "neilgaiman", 9, "default").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	result, err := u.ar.Update(u.ctx, u.db, &domain.Author{Id: 9, Name: "Neil Gaiman"})
+
+	u.Nil(result)
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestDelete_Success() {
+	u.mock.ExpectQuery(`SELECT book_id FROM book_authors WHERE author_id=\$1 AND author_id IN \(SELECT id FROM authors WHERE tenant_id=\$2\) ORDER BY book_id`).WithArgs(2, "default").WillReturnRows(sqlmock.NewRows([]string{"book_id"}))
+	u.mock.ExpectExec(`DELETE FROM authors WHERE id=\$1 AND tenant_id=\$2`).WithArgs(2, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := u.ar.Delete(u.ctx, u.db, 2)
+
+	u.Nil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestDelete_StillCredited() {
+	u.mock.ExpectQuery(`SELECT book_id FROM book_authors WHERE author_id=\$1 AND author_id IN \(SELECT id FROM authors WHERE tenant_id=\$2\) ORDER BY book_id`).WithArgs(2, "default").WillReturnRows(sqlmock.NewRows([]string{"book_id"}).AddRow(5))
+
+	err := u.ar.Delete(u.ctx, u.db, 2)
+
+	u.Equal(ErrForeignKeyViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindByBookIds_Success() {
+	rows := sqlmock.NewRows([]string{"book_id", "id", "name"}).
+		AddRow(4, 3, "Terry Pratchett").
+		AddRow(4, 2, "Neil Gaiman").
+		AddRow(5, 2, "Neil Gaiman")
+	u.mock.ExpectQuery(`SELECT book_authors.book_id, authors.id, authors.name FROM book_authors JOIN authors ON authors.id = book_authors.author_id WHERE authors.tenant_id=\$1 AND book_authors.book_id IN \(\$2,\$3,\$4\) ORDER BY book_authors.book_id, book_authors.position`).
+		WithArgs("default", 4, 5, 6).WillReturnRows(rows)
+
+	result, err := u.ar.FindByBookIds(u.ctx, u.db, []uint{4, 5, 6})
+
+	u.Nil(err)
+	u.Equal(map[uint][]*domain.Author{
+		4: {{Id: 3, Name: "Terry Pratchett"}, {Id: 2, Name: "Neil Gaiman"}},
+		5: {{Id: 2, Name: "Neil Gaiman"}},
+	}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestFindByBookIds_NoBooks() {
+	result, err := u.ar.FindByBookIds(u.ctx, u.db, []uint{})
+
+	u.Nil(err)
+	u.Empty(result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestSetBookAuthors_Success() {
+	u.mock.ExpectExec(`DELETE FROM book_authors WHERE book_id=\$1 AND book_id IN \(SELECT id FROM books WHERE tenant_id=\$2\)`).WithArgs(4, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+	u.mock.ExpectExec(`INSERT INTO book_authors\(book_id, author_id, position\) SELECT books.id, authors.id, CAST\(\$3 AS INTEGER\) FROM books, authors WHERE books.id=\$1 AND books.tenant_id=\$4 AND authors.id=\$2 AND authors.tenant_id=\$4`).WithArgs(4, 3, 0, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+	u.mock.ExpectExec(`INSERT INTO book_authors`).WithArgs(4, 2, 1, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := u.ar.SetBookAuthors(u.ctx, u.db, 4, []uint{3, 2})
+
+	u.Nil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestAuthorRepositorySuite) TestSetBookAuthors_AnotherTenant() {
+	u.ctx = tenant.WithContext(u.ctx, "acme")
+
+	u.mock.ExpectExec(`DELETE FROM book_authors`).WithArgs(4, "acme").WillReturnResult(sqlmock.NewResult(0, 1))
+	// author 3 belongs to another tenant, so nothing is linked
+	u.mock.ExpectExec(`INSERT INTO book_authors`).WithArgs(4, 3, 0, "acme").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := u.ar.SetBookAuthors(u.ctx, u.db, 4, []uint{3})
+
+	u.Equal(ErrForeignKeyViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"gin-go-testing/model/domain"
+	"strings"
+)
+
+// dedupeKeys computes, for each books.unique_by rule, the value duplicate books share. It is
+// stored in the uniquely indexed dedupe_key column, an empty key is stored as NULL and never
+// conflicts.
+var dedupeKeys = map[string]func(book *domain.Book) string{
+	"title_author": func(book *domain.Book) string {
+		// \x1f (unit separator) keeps "a b"+"c" and "a"+"b c" apart
+		return normalize(book.Title) + "\x1f" + normalize(book.Author)
+	},
+	"isbn": func(book *domain.Book) string {
+		if book.Isbn == nil {
+			return ""
+		}
+
+		return *book.Isbn
+	},
+	"none": func(book *domain.Book) string {
+		return ""
+	},
+}
+
+// normalize ignores case and differences in whitespace.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// nullableKey turns an empty dedupe key into NULL.
+func nullableKey(key string) any {
+	if key == "" {
+		return nil
+	}
+
+	return key
+}
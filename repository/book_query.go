@@ -1,7 +1,253 @@
 package repository
 
+import (
+	"errors"
+	"fmt"
+	"gin-go-testing/model/domain"
+	"slices"
+	"strings"
+)
+
+// bookColumns are the columns scanned by bookFields, in its order.
+const bookColumns = `id, title, author, isbn, publication_year, publisher, language, page_count, description, cover_url`
+
 const (
-	findOneByIdQuery = `SELECT id, title, author FROM books WHERE id=$1`
-	findAllQuery     = `SELECT id, title, author FROM books`
-	createQuery      = `INSERT INTO books(title, author) VALUES($1,$2) RETURNING id`
+	findOneByIdQuery     = `SELECT ` + bookColumns + ` FROM books WHERE id=$1 AND tenant_id=$2`
+	findByDedupeKeyQuery = `SELECT ` + bookColumns + ` FROM books WHERE dedupe_key=$1 AND tenant_id=$2`
+	findAllQuery         = `SELECT ` + bookColumns + ` FROM books`
+	countQuery           = `SELECT COUNT(*) FROM books`
+	createQuery          = `INSERT INTO books(title, author, isbn, publication_year, publisher, language, page_count, description, cover_url, dedupe_key, tenant_id) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`
+	returningIdQuery     = ` RETURNING id`
+	updateQuery          = `UPDATE books SET title=$1, author=$2, isbn=$3, publication_year=$4, publisher=$5, language=$6, page_count=$7, description=$8, cover_url=$9, dedupe_key=$10 WHERE id=$11 AND tenant_id=$12`
+	deleteQuery          = `DELETE FROM books WHERE id=$1 AND tenant_id=$2`
+	findTenantIdsQuery   = `SELECT DISTINCT tenant_id FROM books ORDER BY tenant_id`
 )
+
+// book_tags has no tenant of its own, its rows are reached through the books of the tenant.
+const (
+	// addBookTagQuery only links a book and a tag of tenant $3
+	addBookTagQuery         = `INSERT INTO book_tags(book_id, tag_id) SELECT books.id, tags.id FROM books, tags WHERE books.id=$1 AND books.tenant_id=$3 AND tags.id=$2 AND tags.tenant_id=$3 ON CONFLICT DO NOTHING`
+	removeBookTagQuery      = `DELETE FROM book_tags WHERE book_id=$1 AND tag_id=$2 AND book_id IN (SELECT id FROM books WHERE tenant_id=$3)`
+	removeTagFromBooksQuery = `DELETE FROM book_tags WHERE tag_id=$1 AND book_id IN (SELECT id FROM books WHERE tenant_id=$2)`
+	findBookTagIdsQuery     = `SELECT book_id, tag_id FROM book_tags WHERE book_id IN (SELECT id FROM books WHERE tenant_id=$1) AND book_id IN (%s) ORDER BY book_id, tag_id`
+	countBookTagsQuery      = `SELECT tag_id, COUNT(*) FROM book_tags`
+)
+
+// bookFields are the scan destinations of bookColumns.
+func bookFields(book *domain.Book) []any {
+	return []any{&book.Id, &book.Title, &book.Author, &book.Isbn, &book.PublicationYear, &book.Publisher, &book.Language, &book.PageCount, &book.Description, &book.CoverUrl}
+}
+
+// bookValues are the values of the columns createQuery and updateQuery write, but the dedupe key.
+func bookValues(book *domain.Book) []any {
+	return []any{book.Title, book.Author, book.Isbn, book.PublicationYear, book.Publisher, book.Language, book.PageCount, book.Description, book.CoverUrl}
+}
+
+// patchableColumns is the whitelist of columns a partial update is allowed to touch.
+var patchableColumns = map[string]func(book *domain.Book) any{
+	"title":            func(book *domain.Book) any { return book.Title },
+	"author":           func(book *domain.Book) any { return book.Author },
+	"isbn":             func(book *domain.Book) any { return book.Isbn },
+	"publication_year": func(book *domain.Book) any { return book.PublicationYear },
+	"publisher":        func(book *domain.Book) any { return book.Publisher },
+	"language":         func(book *domain.Book) any { return book.Language },
+	"page_count":       func(book *domain.Book) any { return book.PageCount },
+	"description":      func(book *domain.Book) any { return book.Description },
+	"cover_url":        func(book *domain.Book) any { return book.CoverUrl },
+}
+
+// dedupedColumns are the columns the dedupe key is computed from.
+var dedupedColumns = map[string]bool{
+	"title":  true,
+	"author": true,
+	"isbn":   true,
+}
+
+// buildPatchQuery builds an UPDATE statement that only sets the given columns, and the
+// dedupe key when they are some of the columns it is computed from.
+func buildPatchQuery(tenantId string, book *domain.Book, columns []string, dedupeKey string) (string, []any, error) {
+	sets := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)+1)
+
+	for _, column := range columns {
+		value, ok := patchableColumns[column]
+		if !ok {
+			return "", nil, fmt.Errorf("column %q is not patchable", column)
+		}
+
+		args = append(args, value(book))
+		sets = append(sets, fmt.Sprintf("%s=$%d", column, len(args)))
+	}
+
+	if len(sets) == 0 {
+		return "", nil, errors.New("no columns to patch")
+	}
+
+	if slices.ContainsFunc(columns, func(column string) bool { return dedupedColumns[column] }) {
+		args = append(args, nullableKey(dedupeKey))
+		sets = append(sets, fmt.Sprintf("dedupe_key=$%d", len(args)))
+	}
+
+	args = append(args, book.Id, tenantId)
+	query := fmt.Sprintf("UPDATE books SET %s WHERE id=$%d AND tenant_id=$%d", strings.Join(sets, ", "), len(args)-1, len(args))
+
+	return query, args, nil
+}
+
+// sortableColumns is the whitelist of columns a listing can be ordered by.
+var sortableColumns = map[string]bool{
+	"id":     true,
+	"title":  true,
+	"author": true,
+}
+
+// buildFindAllQuery builds the listing query with its filters, ordering and paging.
+func buildFindAllQuery(tenantId string, params *domain.BookListParams) (string, []any, error) {
+	conditions, args := buildBookFilter(tenantId, params)
+
+	orderBy, err := buildOrderBy(params.Sort)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := findAllQuery + buildWhere(conditions) + orderBy
+
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args, nil
+}
+
+// buildCountQuery counts the rows matched by the listing filters, ignoring paging.
+func buildCountQuery(tenantId string, params *domain.BookListParams) (string, []any) {
+	conditions, args := buildBookFilter(tenantId, params)
+
+	return countQuery + buildWhere(conditions), args
+}
+
+// buildCountBookTagsQuery counts, per tag, the books matched by the listing filters. The
+// books are always looked up, SQLite does not cascade the delete of a book to its tags.
+func buildCountBookTagsQuery(tenantId string, params *domain.BookListParams) (string, []any) {
+	conditions, args := buildBookFilter(tenantId, params)
+
+	return countBookTagsQuery + " WHERE book_id IN (SELECT id FROM books" + buildWhere(conditions) + ") GROUP BY tag_id", args
+}
+
+// buildFindAllByCursorQuery builds a keyset listing query ordered by (sort key, id),
+// starting right after the row the cursor points at. One extra row is fetched so the
+// caller can tell whether another page exists.
+func buildFindAllByCursorQuery(tenantId string, params *domain.BookListParams, sort domain.SortField, after *bookCursor) (string, []any, error) {
+	conditions, args := buildBookFilter(tenantId, params)
+
+	if sort.Field != "id" && !sortableColumns[sort.Field] {
+		return "", nil, fmt.Errorf("cannot sort by %q", sort.Field)
+	}
+
+	operator, direction := ">", "ASC"
+	if sort.Desc {
+		operator, direction = "<", "DESC"
+	}
+
+	if after != nil {
+		if sort.Field == "id" {
+			args = append(args, after.Id)
+			conditions = append(conditions, fmt.Sprintf("id %s $%d", operator, len(args)))
+		} else {
+			args = append(args, after.Value, after.Id)
+			conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", sort.Field, operator, len(args)-1, len(args)))
+		}
+	}
+
+	orderBy := fmt.Sprintf(" ORDER BY id %s", direction)
+	if sort.Field != "id" {
+		orderBy = fmt.Sprintf(" ORDER BY %s %s, id %s", sort.Field, direction, direction)
+	}
+
+	args = append(args, params.Limit+1)
+	query := findAllQuery + buildWhere(conditions) + orderBy + fmt.Sprintf(" LIMIT $%d", len(args))
+
+	return query, args, nil
+}
+
+func buildWhere(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// buildBookFilter matches the books of the tenant that pass the filters of params.
+func buildBookFilter(tenantId string, params *domain.BookListParams) ([]string, []any) {
+	conditions := []string{"tenant_id=$1"}
+	args := []any{tenantId}
+
+	if params.Author != "" {
+		args = append(args, params.Author)
+		conditions = append(conditions, fmt.Sprintf("author=$%d", len(args)))
+	}
+
+	// LOWER and an explicit ESCAPE keep the match case-insensitive on every dialect
+	if params.TitleContains != "" {
+		args = append(args, "%"+escapeLike(params.TitleContains)+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(title) LIKE LOWER($%d) ESCAPE '\'`, len(args)))
+	}
+
+	for _, tagIds := range params.TagIds {
+		// a group without tags matches no book
+		if len(tagIds) == 0 {
+			conditions = append(conditions, "1=0")
+			continue
+		}
+
+		placeholders := make([]string, 0, len(tagIds))
+
+		for _, tagId := range tagIds {
+			args = append(args, tagId)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT book_id FROM book_tags WHERE tag_id IN (%s))", strings.Join(placeholders, ",")))
+	}
+
+	return conditions, args
+}
+
+func buildOrderBy(sort []domain.SortField) (string, error) {
+	orders := make([]string, 0, len(sort)+1)
+	sortedById := false
+
+	for _, field := range sort {
+		if !sortableColumns[field.Field] {
+			return "", fmt.Errorf("cannot sort by %q", field.Field)
+		}
+
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+
+		orders = append(orders, field.Field+" "+direction)
+		sortedById = sortedById || field.Field == "id"
+	}
+
+	// id breaks ties so pages stay stable between requests
+	if !sortedById {
+		orders = append(orders, "id ASC")
+	}
+
+	return " ORDER BY " + strings.Join(orders, ", "), nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
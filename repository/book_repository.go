@@ -1,15 +1,56 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
+	"gin-go-testing/config"
 	"gin-go-testing/model/domain"
 
-	"github.com/gin-gonic/gin"
 	"github.com/rulyadhika/go-custom-err/errs"
 )
 
+// BookRepository stores books. Every method only sees the books of the tenant carried by
+// ctx, see tenant.FromContext, the books of other tenants are not found.
 type BookRepository interface {
-	Create(ctx *gin.Context, db *sql.DB, book *domain.Book) (*domain.Book, errs.CustomError)
-	FindOneById(ctx *gin.Context, db *sql.DB, bookId uint) (*domain.Book, errs.CustomError)
-	FindAll(ctx *gin.Context, db *sql.DB) ([]*domain.Book, errs.CustomError)
+	Create(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError)
+	FindOneById(ctx context.Context, db DBTX, bookId uint) (*domain.Book, errs.CustomError)
+	// FindDuplicate finds the stored book the books.unique_by rule considers the same as book.
+	FindDuplicate(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError)
+	FindAll(ctx context.Context, db DBTX, params *domain.BookListParams) ([]*domain.Book, errs.CustomError)
+	// FindAllByCursor pages through books by (sort key, id). An empty cursor starts from the
+	// beginning, the returned cursor is empty on the last page.
+	FindAllByCursor(ctx context.Context, db DBTX, params *domain.BookListParams, cursor string) ([]*domain.Book, string, errs.CustomError)
+	Count(ctx context.Context, db DBTX, params *domain.BookListParams) (uint, errs.CustomError)
+	Update(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError)
+	Patch(ctx context.Context, db DBTX, book *domain.Book, columns []string) (*domain.Book, errs.CustomError)
+	Delete(ctx context.Context, db DBTX, bookId uint) errs.CustomError
+	// AddTag tags a book, doing nothing when it already carries the tag or when the book or
+	// the tag belongs to another tenant.
+	AddTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError
+	// RemoveTag untags a book, doing nothing when it does not carry the tag.
+	RemoveTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError
+	// RemoveTagFromBooks untags every book carrying the tag.
+	RemoveTagFromBooks(ctx context.Context, db DBTX, tagId uint) errs.CustomError
+	// FindTagIds returns the ids of the tags of each of the books, in ascending order. Books
+	// without tags are left out of the map.
+	FindTagIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]uint, errs.CustomError)
+	// CountTags counts, per tag id, the books matched by the filters of params, ignoring paging.
+	CountTags(ctx context.Context, db DBTX, params *domain.BookListParams) (map[uint]uint, errs.CustomError)
+	// FindTenantIds returns the ids of the tenants owning books, in ascending order. It is the
+	// one method looking past the tenant of ctx, so the books of all of them can be indexed.
+	FindTenantIds(ctx context.Context, db DBTX) ([]string, errs.CustomError)
+}
+
+// NewBookRepository picks the repository matching the configured database driver.
+func NewBookRepository(cfg *config.Config) (BookRepository, error) {
+	switch cfg.Database.Driver {
+	case "postgres":
+		return NewBookRepositoryImpl(cfg), nil
+	case "sqlite":
+		return NewSQLiteBookRepositoryImpl(cfg), nil
+	case "memory":
+		return NewMemoryBookRepositoryImpl(cfg), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
 }
@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"net/http"
+	"testing"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+	"github.com/stretchr/testify/suite"
+)
+
+// conformanceBookRepositorySuite runs the same scenarios against every BookRepository
+// implementation, so the backends stay interchangeable.
+type conformanceBookRepositorySuite struct {
+	suite.Suite
+	setup func(cfg *config.Config) (BookRepository, *sql.DB)
+	br    BookRepository
+	db    *sql.DB
+	ctx   context.Context
+}
+
+func TestConformanceMemoryBookRepository(t *testing.T) {
+	suite.Run(t, &conformanceBookRepositorySuite{
+		setup: func(cfg *config.Config) (BookRepository, *sql.DB) {
+			return NewMemoryBookRepositoryImpl(cfg), nil
+		},
+	})
+}
+
+func TestConformanceSQLiteBookRepository(t *testing.T) {
+	s := &conformanceBookRepositorySuite{}
+	s.setup = func(cfg *config.Config) (BookRepository, *sql.DB) {
+		db := openSQLite(s.T())
+
+		return NewSQLiteBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestConformancePostgresBookRepository needs a disposable database, its tables are
+// truncated before every test.
+func TestConformancePostgresBookRepository(t *testing.T) {
+	dsn := testDatabaseURL(t)
+
+	s := &conformanceBookRepositorySuite{}
+	s.setup = func(cfg *config.Config) (BookRepository, *sql.DB) {
+		db := openPostgres(s.T(), dsn)
+
+		return NewBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+func (c *conformanceBookRepositorySuite) SetupTest() {
+	cfg := config.Default()
+	cfg.Pagination.CursorSecret = "secret"
+
+	c.br, c.db = c.setup(cfg)
+	c.ctx = context.Background()
+}
+
+func (c *conformanceBookRepositorySuite) TearDownTest() {
+	if c.db != nil {
+		c.db.Close()
+	}
+}
+
+func (c *conformanceBookRepositorySuite) createBooks(books ...domain.Book) []*domain.Book {
+	var created []*domain.Book
+
+	for i := range books {
+		result, err := c.br.Create(c.ctx, c.db, &books[i])
+		c.Require().Nil(err)
+
+		created = append(created, result)
+	}
+
+	return created
+}
+
+func (c *conformanceBookRepositorySuite) TestCreate_AssignsIds() {
+	created := c.createBooks(
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+	)
+
+	c.NotZero(created[0].Id)
+	c.Greater(created[1].Id, created[0].Id)
+
+	result, err := c.br.FindOneById(c.ctx, c.db, created[1].Id)
+
+	c.Nil(err)
+	c.Equal(&domain.Book{Id: created[1].Id, Title: "Emma", Author: "Jane Austen"}, result)
+}
+
+func (c *conformanceBookRepositorySuite) TestFindOneById_NotFound() {
+	result, err := c.br.FindOneById(c.ctx, c.db, 99)
+
+	c.Nil(result)
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestUpdate() {
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	_, err := c.br.Update(c.ctx, c.db, &domain.Book{Id: created[0].Id, Title: "Dune Messiah", Author: "F. Herbert"})
+	c.Nil(err)
+
+	result, _ := c.br.FindOneById(c.ctx, c.db, created[0].Id)
+	c.Equal(&domain.Book{Id: created[0].Id, Title: "Dune Messiah", Author: "F. Herbert"}, result)
+
+	_, err = c.br.Update(c.ctx, c.db, &domain.Book{Id: 99, Title: "Dune", Author: "Frank Herbert"})
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestPatch_OnlyGivenColumns() {
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	_, err := c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[0].Id, Title: "Dune Messiah", Author: "ignored"}, []string{"title"})
+	c.Nil(err)
+
+	result, _ := c.br.FindOneById(c.ctx, c.db, created[0].Id)
+	c.Equal(&domain.Book{Id: created[0].Id, Title: "Dune Messiah", Author: "Frank Herbert"}, result)
+
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: 99, Title: "Dune"}, []string{"title"})
+	c.Equal(ErrNotFound, kindOf(err))
+
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[0].Id}, []string{"id"})
+	c.Equal(http.StatusInternalServerError, err.StatusCode())
+}
+
+func (c *conformanceBookRepositorySuite) TestDetails_RoundTripAndRemove() {
+	isbn, year, publisher, language := "9780441013593", 1965, "Chilton Books", "en"
+	pages, description, coverUrl := 412, "A desert planet.", "https://example.com/dune.jpg"
+
+	created := c.createBooks(domain.Book{
+		Title:           "Dune",
+		Author:          "Frank Herbert",
+		Isbn:            &isbn,
+		PublicationYear: &year,
+		Publisher:       &publisher,
+		Language:        &language,
+		PageCount:       &pages,
+		Description:     &description,
+		CoverUrl:        &coverUrl,
+	})
+
+	result, err := c.br.FindOneById(c.ctx, c.db, created[0].Id)
+
+	c.Nil(err)
+	c.Equal(created[0], result)
+
+	result.Publisher, result.CoverUrl = nil, nil
+	_, err = c.br.Patch(c.ctx, c.db, result, []string{"publisher", "cover_url"})
+	c.Nil(err)
+
+	result, _ = c.br.FindOneById(c.ctx, c.db, created[0].Id)
+	c.Nil(result.Publisher)
+	c.Nil(result.CoverUrl)
+	c.Equal(isbn, *result.Isbn)
+
+	// an update replaces every detail, those it leaves out are removed
+	_, err = c.br.Update(c.ctx, c.db, &domain.Book{Id: created[0].Id, Title: "Dune", Author: "Frank Herbert"})
+	c.Nil(err)
+
+	result, _ = c.br.FindOneById(c.ctx, c.db, created[0].Id)
+	c.Equal(&domain.Book{Id: created[0].Id, Title: "Dune", Author: "Frank Herbert"}, result)
+}
+
+func (c *conformanceBookRepositorySuite) TestDelete() {
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	c.Nil(c.br.Delete(c.ctx, c.db, created[0].Id))
+
+	_, err := c.br.FindOneById(c.ctx, c.db, created[0].Id)
+	c.Equal(ErrNotFound, kindOf(err))
+
+	err = c.br.Delete(c.ctx, c.db, created[0].Id)
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestFindAll_FilterSortAndPage() {
+	c.createBooks(
+		domain.Book{Title: "Persuasion", Author: "Jane Austen"},
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+		domain.Book{Title: "Pride and Prejudice", Author: "Jane Austen"},
+		domain.Book{Title: "100% Austen", Author: "Jane Austen"},
+	)
+
+	params := &domain.BookListParams{
+		Limit:  2,
+		Offset: 1,
+		Sort:   []domain.SortField{{Field: "title", Desc: true}},
+		Author: "Jane Austen",
+	}
+
+	result, err := c.br.FindAll(c.ctx, c.db, params)
+
+	c.Nil(err)
+	c.Equal([]string{"Persuasion", "Emma"}, bookTitles(result))
+
+	count, err := c.br.Count(c.ctx, c.db, params)
+
+	c.Nil(err)
+	c.Equal(uint(4), count)
+
+	params = &domain.BookListParams{Limit: 10, TitleContains: "p"}
+	result, _ = c.br.FindAll(c.ctx, c.db, params)
+	c.Equal([]string{"Persuasion", "Pride and Prejudice"}, bookTitles(result))
+
+	params = &domain.BookListParams{Limit: 10, TitleContains: "0%"}
+	result, _ = c.br.FindAll(c.ctx, c.db, params)
+	c.Equal([]string{"100% Austen"}, bookTitles(result))
+}
+
+func (c *conformanceBookRepositorySuite) TestFindAll_Empty() {
+	result, err := c.br.FindAll(c.ctx, c.db, &domain.BookListParams{Limit: 10})
+	c.Nil(err)
+	c.NotNil(result)
+	c.Empty(result)
+
+	c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	result, err = c.br.FindAll(c.ctx, c.db, &domain.BookListParams{Limit: 10, Offset: 10})
+	c.Nil(err)
+	c.Empty(result)
+}
+
+func (c *conformanceBookRepositorySuite) TestFindAll_UnknownSortField() {
+	_, err := c.br.FindAll(c.ctx, c.db, &domain.BookListParams{Limit: 10, Sort: []domain.SortField{{Field: "price"}}})
+
+	c.Equal(http.StatusBadRequest, err.StatusCode())
+	c.Equal(apperror.CodeInvalidSort, apperror.CodeOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestFindAllByCursor_WalksEveryPage() {
+	c.createBooks(
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+		domain.Book{Title: "Persuasion", Author: "Jane Austen"},
+		domain.Book{Title: "Emma", Author: "J. Austen"},
+		domain.Book{Title: "Ulysses", Author: "James Joyce"},
+	)
+
+	params := &domain.BookListParams{Limit: 2, Sort: []domain.SortField{{Field: "title", Desc: true}}}
+
+	var titles []string
+	var cursor string
+
+	for pages := 0; pages < 5; pages++ {
+		result, next, err := c.br.FindAllByCursor(c.ctx, c.db, params, cursor)
+		c.Require().Nil(err)
+
+		titles = append(titles, bookTitles(result)...)
+
+		if next == "" {
+			break
+		}
+
+		cursor = next
+	}
+
+	c.Equal([]string{"Ulysses", "Persuasion", "Emma", "Emma", "Dune"}, titles)
+}
+
+func (c *conformanceBookRepositorySuite) TestFindAllByCursor_InvalidCursor() {
+	_, _, err := c.br.FindAllByCursor(c.ctx, c.db, &domain.BookListParams{Limit: 2}, "forged")
+
+	c.Equal(http.StatusBadRequest, err.StatusCode())
+	c.Equal(apperror.CodeInvalidCursor, apperror.CodeOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestCreate_Duplicate() {
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	_, err := c.br.Create(c.ctx, c.db, &domain.Book{Title: " dune ", Author: "FRANK  herbert"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	duplicate, err := c.br.FindDuplicate(c.ctx, c.db, &domain.Book{Title: "DUNE", Author: "frank herbert"})
+
+	c.Nil(err)
+	c.Equal(created[0], duplicate)
+
+	_, err = c.br.FindDuplicate(c.ctx, c.db, &domain.Book{Title: "Emma", Author: "Jane Austen"})
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestUpdateAndPatch_Duplicate() {
+	created := c.createBooks(
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+	)
+
+	_, err := c.br.Update(c.ctx, c.db, &domain.Book{Id: created[1].Id, Title: "Dune", Author: "Frank Herbert"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[1].Id, Title: "dune", Author: "Frank Herbert"}, []string{"title", "author"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	// a book does not conflict with itself
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[0].Id, Title: "DUNE", Author: "Frank Herbert"}, []string{"title"})
+	c.Nil(err)
+
+	// the key follows the book
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[0].Id, Title: "Dune Messiah", Author: "Frank Herbert"}, []string{"title"})
+	c.Nil(err)
+	c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+}
+
+func (c *conformanceBookRepositorySuite) TestDelete_FreesDedupeKey() {
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	c.Nil(c.br.Delete(c.ctx, c.db, created[0].Id))
+
+	c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+}
+
+func (c *conformanceBookRepositorySuite) TestUniqueByNone_AllowsDuplicates() {
+	c.TearDownTest()
+
+	cfg := config.Default()
+	cfg.Books.UniqueBy = "none"
+	c.br, c.db = c.setup(cfg)
+
+	c.createBooks(
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+		domain.Book{Title: "Dune", Author: "Frank Herbert"},
+	)
+
+	_, err := c.br.FindDuplicate(c.ctx, c.db, &domain.Book{Title: "Dune", Author: "Frank Herbert"})
+	c.Equal(ErrNotFound, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestUniqueByIsbn() {
+	c.TearDownTest()
+
+	cfg := config.Default()
+	cfg.Books.UniqueBy = "isbn"
+	c.br, c.db = c.setup(cfg)
+
+	isbn, other := "9780441013593", "9780593099322"
+
+	created := c.createBooks(
+		domain.Book{Title: "Dune", Author: "Frank Herbert", Isbn: &isbn},
+		domain.Book{Title: "Dune", Author: "Frank Herbert", Isbn: &other},
+		// books without an isbn are never duplicates of each other
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+		domain.Book{Title: "Emma", Author: "Jane Austen"},
+	)
+
+	_, err := c.br.Create(c.ctx, c.db, &domain.Book{Title: "Dune (Deluxe Edition)", Author: "Frank Herbert", Isbn: &isbn})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	result, err := c.br.FindDuplicate(c.ctx, c.db, &domain.Book{Isbn: &isbn})
+	c.Nil(err)
+	c.Equal(created[0].Id, result.Id)
+
+	_, err = c.br.Patch(c.ctx, c.db, &domain.Book{Id: created[1].Id, Isbn: &isbn}, []string{"isbn"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+}
+
+func (c *conformanceBookRepositorySuite) TestTenants_Isolated() {
+	acme, globex := tenant.WithContext(c.ctx, "acme"), tenant.WithContext(c.ctx, "globex")
+
+	book, err := c.br.Create(acme, c.db, &domain.Book{Title: "Dune", Author: "Frank Herbert"})
+	c.Require().Nil(err)
+
+	_, err = c.br.FindOneById(globex, c.db, book.Id)
+	c.Equal(ErrNotFound, kindOf(err))
+
+	_, err = c.br.Update(globex, c.db, &domain.Book{Id: book.Id, Title: "Emma", Author: "Jane Austen"})
+	c.Equal(ErrNotFound, kindOf(err))
+
+	_, err = c.br.Patch(globex, c.db, &domain.Book{Id: book.Id, Title: "Emma"}, []string{"title"})
+	c.Equal(ErrNotFound, kindOf(err))
+
+	c.Equal(ErrNotFound, kindOf(c.br.Delete(globex, c.db, book.Id)))
+
+	_, err = c.br.FindDuplicate(globex, c.db, &domain.Book{Title: "Dune", Author: "Frank Herbert"})
+	c.Equal(ErrNotFound, kindOf(err))
+
+	books, err := c.br.FindAll(globex, c.db, &domain.BookListParams{})
+	c.Nil(err)
+	c.Empty(books)
+
+	total, err := c.br.Count(globex, c.db, &domain.BookListParams{})
+	c.Nil(err)
+	c.Zero(total)
+
+	// the same book is no duplicate in another tenant
+	_, err = c.br.Create(globex, c.db, &domain.Book{Title: "Dune", Author: "Frank Herbert"})
+	c.Nil(err)
+
+	found, err := c.br.FindOneById(acme, c.db, book.Id)
+	c.Nil(err)
+	c.Equal(book, found)
+
+	tenantIds, err := c.br.FindTenantIds(c.ctx, c.db)
+	c.Nil(err)
+	c.Equal([]string{"acme", "globex"}, tenantIds)
+}
+
+func (c *conformanceBookRepositorySuite) TestWithinTx_RollbackDiscardsWrites() {
+	if c.db == nil {
+		c.T().Skip("the in-memory repository has no rollback")
+	}
+
+	created := c.createBooks(domain.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	err := NewTxManager(c.db, config.Default()).WithinTx(c.ctx, nil, func(tx DBTX) errs.CustomError {
+		if err := c.br.Delete(c.ctx, tx, created[0].Id); err != nil {
+			return err
+		}
+
+		return errs.NewConflictError("changed my mind")
+	})
+
+	c.Equal(http.StatusConflict, err.StatusCode())
+
+	result, err := c.br.FindOneById(c.ctx, c.db, created[0].Id)
+
+	c.Nil(err)
+	c.Equal("Dune", result.Title)
+}
+
+// kindOf returns the kind of a repository error, nil for any other error.
+func kindOf(err errs.CustomError) error {
+	if repoErr, ok := err.(*Error); ok {
+		return repoErr.Kind
+	}
+
+	return nil
+}
+
+func bookTitles(books []*domain.Book) []string {
+	titles := []string{}
+
+	for _, book := range books {
+		titles = append(titles, book.Title)
+	}
+
+	return titles
+}
@@ -1,71 +1,387 @@
 package repository
 
 import (
-	"database/sql"
-	"errors"
+	"context"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
 	"gin-go-testing/model/domain"
-	"log"
+	"gin-go-testing/tenant"
+	"net/http"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/rulyadhika/go-custom-err/errs"
 )
 
-type bookRepositoryImpl struct{}
+type bookRepositoryImpl struct {
+	dialect      *dialect
+	cursor       *cursorCodec
+	queryTimeout time.Duration
+	dedupeKey    func(book *domain.Book) string
+}
+
+// NewBookRepositoryImpl creates the Postgres repository.
+func NewBookRepositoryImpl(cfg *config.Config) BookRepository {
+	return newBookRepositoryImpl(cfg, postgresDialect)
+}
 
-func NewBookRepositoryImpl() BookRepository {
-	return &bookRepositoryImpl{}
+// NewSQLiteBookRepositoryImpl creates the SQLite repository.
+func NewSQLiteBookRepositoryImpl(cfg *config.Config) BookRepository {
+	return newBookRepositoryImpl(cfg, sqliteDialect)
+}
+
+func newBookRepositoryImpl(cfg *config.Config, dialect *dialect) BookRepository {
+	return &bookRepositoryImpl{
+		dialect:      dialect,
+		cursor:       &cursorCodec{secret: []byte(cfg.Pagination.CursorSecret)},
+		queryTimeout: cfg.Database.QueryTimeout,
+		dedupeKey:    dedupeKeys[cfg.Books.UniqueBy],
+	}
 }
-func (b *bookRepositoryImpl) Create(ctx *gin.Context, db *sql.DB, book *domain.Book) (*domain.Book, errs.CustomError) {
-	err := db.QueryRowContext(ctx, createQuery, book.Title, book.Author).Scan(&book.Id)
 
+// withTimeout bounds a single query by the configured query timeout.
+func (b *bookRepositoryImpl) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, b.queryTimeout)
+}
+func (b *bookRepositoryImpl) Create(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	id, err := b.dialect.insert(queryCtx, db, createQuery, append(bookValues(book), nullableKey(b.dedupeKey(book)), tenant.FromContext(ctx))...)
 	if err != nil {
-		log.Printf("[CreateBook - Repo] err: %s", err.Error())
-		return nil, errs.NewInternalServerError("something went wrong")
+		return nil, newError(ctx, "CreateBook", err)
 	}
 
+	book.Id = id
+
 	return book, nil
 }
 
-func (b *bookRepositoryImpl) FindOneById(ctx *gin.Context, db *sql.DB, bookId uint) (*domain.Book, errs.CustomError) {
+func (b *bookRepositoryImpl) FindOneById(ctx context.Context, db DBTX, bookId uint) (*domain.Book, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
 	book := new(domain.Book)
 
-	err := db.QueryRowContext(ctx, findOneByIdQuery, bookId).Scan(&book.Id, &book.Title, &book.Author)
+	err := db.QueryRowContext(queryCtx, b.dialect.rebind(findOneByIdQuery), bookId, tenant.FromContext(ctx)).Scan(bookFields(book)...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errs.NewNotFoundError("data not found")
-		}
-
-		log.Printf("[FindOneBookById - Repo] err: %s", err.Error())
-		return nil, errs.NewInternalServerError("something went wrong")
+		return nil, newError(ctx, "FindOneBookById", err)
 	}
 
 	return book, nil
 }
 
-func (b *bookRepositoryImpl) FindAll(ctx *gin.Context, db *sql.DB) ([]*domain.Book, errs.CustomError) {
+func (b *bookRepositoryImpl) FindDuplicate(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	key := b.dedupeKey(book)
+	if key == "" {
+		return nil, notFound("FindDuplicateBook")
+	}
+
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	duplicate := new(domain.Book)
+
+	err := db.QueryRowContext(queryCtx, b.dialect.rebind(findByDedupeKeyQuery), key, tenant.FromContext(ctx)).Scan(bookFields(duplicate)...)
+	if err != nil {
+		return nil, newError(ctx, "FindDuplicateBook", err)
+	}
+
+	return duplicate, nil
+}
+
+func (b *bookRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.BookListParams) ([]*domain.Book, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
 	books := []*domain.Book{}
 
-	rows, err := db.QueryContext(ctx, findOneByIdQuery)
+	query, args, err := buildFindAllQuery(tenant.FromContext(ctx), params)
 	if err != nil {
-		log.Printf("[FindAllBook - Repo] err: %s", err.Error())
+		return nil, apperror.New(http.StatusBadRequest, apperror.CodeInvalidSort, err.Error())
+	}
 
-		return nil, errs.NewInternalServerError("something went wrong")
+	rows, err := db.QueryContext(queryCtx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "FindAllBook", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		book := &domain.Book{}
 
-		if err := rows.Scan(&book.Id, &book.Title, &book.Author); err != nil {
-			return nil, errs.NewInternalServerError("something went wrong")
+		if err := rows.Scan(bookFields(book)...); err != nil {
+			return nil, newError(ctx, "FindAllBook", err)
 		}
 
 		books = append(books, book)
 	}
 
-	// if the result is empty
-	if len(books) == 0 {
-		return nil, errs.NewNotFoundError("not data found")
+	// a listing broken off midway must not pass for a shorter one
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindAllBook", err)
 	}
 
 	return books, nil
 }
+
+func (b *bookRepositoryImpl) FindAllByCursor(ctx context.Context, db DBTX, params *domain.BookListParams, cursor string) ([]*domain.Book, string, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	sort, after, errCursor := b.cursor.resolve(params, cursor)
+	if errCursor != nil {
+		return nil, "", errCursor
+	}
+
+	query, args, err := buildFindAllByCursorQuery(tenant.FromContext(ctx), params, sort, after)
+	if err != nil {
+		return nil, "", apperror.New(http.StatusBadRequest, apperror.CodeInvalidSort, err.Error())
+	}
+
+	rows, err := db.QueryContext(queryCtx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, "", newError(ctx, "FindAllBookByCursor", err)
+	}
+	defer rows.Close()
+
+	books := []*domain.Book{}
+
+	for rows.Next() {
+		book := &domain.Book{}
+
+		if err := rows.Scan(bookFields(book)...); err != nil {
+			return nil, "", newError(ctx, "FindAllBookByCursor", err)
+		}
+
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", newError(ctx, "FindAllBookByCursor", err)
+	}
+
+	// the query fetches one extra row, which only tells us there is a next page
+	if uint(len(books)) <= params.Limit {
+		return books, "", nil
+	}
+
+	books = books[:params.Limit]
+
+	nextCursor, err := b.cursor.encode(newBookCursor(sort, books[len(books)-1]))
+	if err != nil {
+		return nil, "", newError(ctx, "FindAllBookByCursor", err)
+	}
+
+	return books, nextCursor, nil
+}
+
+func (b *bookRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.BookListParams) (uint, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	var total uint
+
+	query, args := buildCountQuery(tenant.FromContext(ctx), params)
+
+	if err := db.QueryRowContext(queryCtx, b.dialect.rebind(query), args...).Scan(&total); err != nil {
+		return 0, newError(ctx, "CountBook", err)
+	}
+
+	return total, nil
+}
+
+func (b *bookRepositoryImpl) Update(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	result, err := db.ExecContext(queryCtx, b.dialect.rebind(updateQuery), append(bookValues(book), nullableKey(b.dedupeKey(book)), book.Id, tenant.FromContext(ctx))...)
+	if err != nil {
+		return nil, newError(ctx, "UpdateBook", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, newError(ctx, "UpdateBook", err)
+	}
+
+	if affected == 0 {
+		return nil, notFound("UpdateBook")
+	}
+
+	return book, nil
+}
+
+func (b *bookRepositoryImpl) Patch(ctx context.Context, db DBTX, book *domain.Book, columns []string) (*domain.Book, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	query, args, err := buildPatchQuery(tenant.FromContext(ctx), book, columns, b.dedupeKey(book))
+	if err != nil {
+		return nil, newError(ctx, "PatchBook", err)
+	}
+
+	result, err := db.ExecContext(queryCtx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "PatchBook", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, newError(ctx, "PatchBook", err)
+	}
+
+	if affected == 0 {
+		return nil, notFound("PatchBook")
+	}
+
+	return book, nil
+}
+
+func (b *bookRepositoryImpl) Delete(ctx context.Context, db DBTX, bookId uint) errs.CustomError {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	result, err := db.ExecContext(queryCtx, b.dialect.rebind(deleteQuery), bookId, tenant.FromContext(ctx))
+	if err != nil {
+		return newError(ctx, "DeleteBook", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return newError(ctx, "DeleteBook", err)
+	}
+
+	if affected == 0 {
+		return notFound("DeleteBook")
+	}
+
+	return nil
+}
+
+func (b *bookRepositoryImpl) AddTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := db.ExecContext(queryCtx, b.dialect.rebind(addBookTagQuery), bookId, tagId, tenant.FromContext(ctx)); err != nil {
+		return newError(ctx, "AddBookTag", err)
+	}
+
+	return nil
+}
+
+func (b *bookRepositoryImpl) RemoveTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := db.ExecContext(queryCtx, b.dialect.rebind(removeBookTagQuery), bookId, tagId, tenant.FromContext(ctx)); err != nil {
+		return newError(ctx, "RemoveBookTag", err)
+	}
+
+	return nil
+}
+
+func (b *bookRepositoryImpl) RemoveTagFromBooks(ctx context.Context, db DBTX, tagId uint) errs.CustomError {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := db.ExecContext(queryCtx, b.dialect.rebind(removeTagFromBooksQuery), tagId, tenant.FromContext(ctx)); err != nil {
+		return newError(ctx, "RemoveTagFromBooks", err)
+	}
+
+	return nil
+}
+
+func (b *bookRepositoryImpl) FindTagIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]uint, errs.CustomError) {
+	tagIds := map[uint][]uint{}
+
+	if len(bookIds) == 0 {
+		return tagIds, nil
+	}
+
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	query, args := buildInQuery(findBookTagIdsQuery, tenant.FromContext(ctx), bookIds)
+
+	rows, err := db.QueryContext(queryCtx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "FindBookTagIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookId, tagId uint
+
+		if err := rows.Scan(&bookId, &tagId); err != nil {
+			return nil, newError(ctx, "FindBookTagIds", err)
+		}
+
+		tagIds[bookId] = append(tagIds[bookId], tagId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindBookTagIds", err)
+	}
+
+	return tagIds, nil
+}
+
+func (b *bookRepositoryImpl) CountTags(ctx context.Context, db DBTX, params *domain.BookListParams) (map[uint]uint, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	query, args := buildCountBookTagsQuery(tenant.FromContext(ctx), params)
+
+	rows, err := db.QueryContext(queryCtx, b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "CountBookTags", err)
+	}
+	defer rows.Close()
+
+	counts := map[uint]uint{}
+
+	for rows.Next() {
+		var tagId, count uint
+
+		if err := rows.Scan(&tagId, &count); err != nil {
+			return nil, newError(ctx, "CountBookTags", err)
+		}
+
+		counts[tagId] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "CountBookTags", err)
+	}
+
+	return counts, nil
+}
+
+func (b *bookRepositoryImpl) FindTenantIds(ctx context.Context, db DBTX) ([]string, errs.CustomError) {
+	queryCtx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, findTenantIdsQuery)
+	if err != nil {
+		return nil, newError(ctx, "FindBookTenantIds", err)
+	}
+	defer rows.Close()
+
+	tenantIds := []string{}
+
+	for rows.Next() {
+		var tenantId string
+
+		if err := rows.Scan(&tenantId); err != nil {
+			return nil, newError(ctx, "FindBookTenantIds", err)
+		}
+
+		tenantIds = append(tenantIds, tenantId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindBookTenantIds", err)
+	}
+
+	return tenantIds, nil
+}
@@ -0,0 +1,498 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type memoryBookRepositoryImpl struct {
+	mu     sync.RWMutex
+	books  map[uint]*domain.Book
+	lastId uint
+	cursor *cursorCodec
+	// tenants holds the tenant of each book, the in-memory tenant_id column
+	tenants map[uint]string
+	// keys indexes the ids of books by tenant and dedupe key, the in-memory unique index
+	keys      map[tenantKey]uint
+	dedupeKey func(book *domain.Book) string
+	// tags holds the sorted tag ids of each book, the in-memory book_tags table
+	tags map[uint][]uint
+}
+
+// NewMemoryBookRepositoryImpl creates a thread-safe repository that keeps books in memory,
+// for demos and fast tests. The db argument of its methods is ignored.
+func NewMemoryBookRepositoryImpl(cfg *config.Config) BookRepository {
+	return &memoryBookRepositoryImpl{
+		books:     map[uint]*domain.Book{},
+		cursor:    &cursorCodec{secret: []byte(cfg.Pagination.CursorSecret)},
+		tenants:   map[uint]string{},
+		keys:      map[tenantKey]uint{},
+		dedupeKey: dedupeKeys[cfg.Books.UniqueBy],
+		tags:      map[uint][]uint{},
+	}
+}
+
+func (m *memoryBookRepositoryImpl) Create(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenantId := tenant.FromContext(ctx)
+
+	if m.taken(tenantId, book, 0) {
+		return nil, &Error{Op: "CreateBook", Kind: ErrUniqueViolation}
+	}
+
+	m.lastId++
+	book.Id = m.lastId
+
+	stored := *book
+	m.store(tenantId, &stored)
+
+	return book, nil
+}
+
+func (m *memoryBookRepositoryImpl) FindOneById(ctx context.Context, db DBTX, bookId uint) (*domain.Book, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.owned(ctx, bookId)
+	if !ok {
+		return nil, notFound("FindOneBookById")
+	}
+
+	found := *book
+
+	return &found, nil
+}
+
+func (m *memoryBookRepositoryImpl) FindDuplicate(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := m.dedupeKey(book)
+	if key == "" {
+		return nil, notFound("FindDuplicateBook")
+	}
+
+	id, ok := m.keys[tenantKey{tenant.FromContext(ctx), key}]
+	if !ok {
+		return nil, notFound("FindDuplicateBook")
+	}
+
+	found := *m.books[id]
+
+	return &found, nil
+}
+
+func (m *memoryBookRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.BookListParams) ([]*domain.Book, errs.CustomError) {
+	for _, field := range params.Sort {
+		if !sortableColumns[field.Field] {
+			return nil, apperror.New(http.StatusBadRequest, apperror.CodeInvalidSort, fmt.Sprintf("cannot sort by %q", field.Field))
+		}
+	}
+
+	m.mu.RLock()
+	books := m.filter(tenant.FromContext(ctx), params)
+	m.mu.RUnlock()
+
+	slices.SortFunc(books, func(a, b *domain.Book) int {
+		for _, field := range params.Sort {
+			if order := compareBooks(a, b, field); order != 0 {
+				return order
+			}
+		}
+
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	books = books[min(params.Offset, uint(len(books))):]
+
+	if params.Limit > 0 {
+		books = books[:min(params.Limit, uint(len(books)))]
+	}
+
+	return books, nil
+}
+
+func (m *memoryBookRepositoryImpl) FindAllByCursor(ctx context.Context, db DBTX, params *domain.BookListParams, cursor string) ([]*domain.Book, string, errs.CustomError) {
+	sort, after, errCursor := m.cursor.resolve(params, cursor)
+	if errCursor != nil {
+		return nil, "", errCursor
+	}
+
+	if sort.Field != "id" && !sortableColumns[sort.Field] {
+		return nil, "", apperror.New(http.StatusBadRequest, apperror.CodeInvalidSort, fmt.Sprintf("cannot sort by %q", sort.Field))
+	}
+
+	keysetOrder := func(a, b *domain.Book) int {
+		order := compareBooks(a, b, domain.SortField{Field: sort.Field})
+		if order == 0 {
+			order = cmp.Compare(a.Id, b.Id)
+		}
+
+		if sort.Desc {
+			return -order
+		}
+
+		return order
+	}
+
+	m.mu.RLock()
+	books := m.filter(tenant.FromContext(ctx), params)
+	m.mu.RUnlock()
+
+	slices.SortFunc(books, keysetOrder)
+
+	if after != nil {
+		last := &domain.Book{Id: after.Id, Title: after.Value, Author: after.Value}
+		start := slices.IndexFunc(books, func(book *domain.Book) bool {
+			return keysetOrder(book, last) > 0
+		})
+
+		if start < 0 {
+			start = len(books)
+		}
+
+		books = books[start:]
+	}
+
+	if uint(len(books)) <= params.Limit {
+		return books, "", nil
+	}
+
+	books = books[:params.Limit]
+
+	nextCursor, err := m.cursor.encode(newBookCursor(sort, books[len(books)-1]))
+	if err != nil {
+		return nil, "", newError(ctx, "FindAllBookByCursor", err)
+	}
+
+	return books, nextCursor, nil
+}
+
+func (m *memoryBookRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.BookListParams) (uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return uint(len(m.filter(tenant.FromContext(ctx), params))), nil
+}
+
+func (m *memoryBookRepositoryImpl) Update(ctx context.Context, db DBTX, book *domain.Book) (*domain.Book, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.owned(ctx, book.Id); !ok {
+		return nil, notFound("UpdateBook")
+	}
+
+	tenantId := tenant.FromContext(ctx)
+
+	if m.taken(tenantId, book, book.Id) {
+		return nil, &Error{Op: "UpdateBook", Kind: ErrUniqueViolation}
+	}
+
+	stored := *book
+	m.store(tenantId, &stored)
+
+	return book, nil
+}
+
+func (m *memoryBookRepositoryImpl) Patch(ctx context.Context, db DBTX, book *domain.Book, columns []string) (*domain.Book, errs.CustomError) {
+	if len(columns) == 0 {
+		return nil, newError(ctx, "PatchBook", errors.New("no columns to patch"))
+	}
+
+	for _, column := range columns {
+		if _, ok := patchableColumns[column]; !ok {
+			return nil, newError(ctx, "PatchBook", fmt.Errorf("column %q is not patchable", column))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.owned(ctx, book.Id)
+	if !ok {
+		return nil, notFound("PatchBook")
+	}
+
+	patched := *stored
+
+	for _, column := range columns {
+		switch column {
+		case "title":
+			patched.Title = book.Title
+		case "author":
+			patched.Author = book.Author
+		case "isbn":
+			patched.Isbn = book.Isbn
+		case "publication_year":
+			patched.PublicationYear = book.PublicationYear
+		case "publisher":
+			patched.Publisher = book.Publisher
+		case "language":
+			patched.Language = book.Language
+		case "page_count":
+			patched.PageCount = book.PageCount
+		case "description":
+			patched.Description = book.Description
+		case "cover_url":
+			patched.CoverUrl = book.CoverUrl
+		}
+	}
+
+	tenantId := tenant.FromContext(ctx)
+
+	if m.taken(tenantId, &patched, book.Id) {
+		return nil, &Error{Op: "PatchBook", Kind: ErrUniqueViolation}
+	}
+
+	m.store(tenantId, &patched)
+
+	return book, nil
+}
+
+func (m *memoryBookRepositoryImpl) Delete(ctx context.Context, db DBTX, bookId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.owned(ctx, bookId)
+	if !ok {
+		return notFound("DeleteBook")
+	}
+
+	delete(m.keys, tenantKey{m.tenants[bookId], m.dedupeKey(stored)})
+	delete(m.books, bookId)
+	delete(m.tenants, bookId)
+	delete(m.tags, bookId)
+
+	return nil
+}
+
+func (m *memoryBookRepositoryImpl) AddTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the tags are kept by the tag repository, only the tenant of the book can be checked
+	if _, ok := m.owned(ctx, bookId); !ok {
+		return nil
+	}
+
+	tagIds := m.tags[bookId]
+
+	if i, found := slices.BinarySearch(tagIds, tagId); !found {
+		m.tags[bookId] = slices.Insert(tagIds, i, tagId)
+	}
+
+	return nil
+}
+
+func (m *memoryBookRepositoryImpl) RemoveTag(ctx context.Context, db DBTX, bookId uint, tagId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.owned(ctx, bookId); ok {
+		m.removeTag(bookId, tagId)
+	}
+
+	return nil
+}
+
+func (m *memoryBookRepositoryImpl) RemoveTagFromBooks(ctx context.Context, db DBTX, tagId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenantId := tenant.FromContext(ctx)
+
+	for bookId := range m.tags {
+		if m.tenants[bookId] == tenantId {
+			m.removeTag(bookId, tagId)
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryBookRepositoryImpl) FindTagIds(ctx context.Context, db DBTX, bookIds []uint) (map[uint][]uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tagIds := map[uint][]uint{}
+
+	tenantId := tenant.FromContext(ctx)
+
+	for _, bookId := range bookIds {
+		if ids, ok := m.tags[bookId]; ok && m.tenants[bookId] == tenantId {
+			tagIds[bookId] = slices.Clone(ids)
+		}
+	}
+
+	return tagIds, nil
+}
+
+func (m *memoryBookRepositoryImpl) CountTags(ctx context.Context, db DBTX, params *domain.BookListParams) (map[uint]uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := map[uint]uint{}
+
+	for _, book := range m.filter(tenant.FromContext(ctx), params) {
+		for _, tagId := range m.tags[book.Id] {
+			counts[tagId]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (m *memoryBookRepositoryImpl) FindTenantIds(ctx context.Context, db DBTX) ([]string, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenantIds := []string{}
+
+	for _, tenantId := range m.tenants {
+		if !slices.Contains(tenantIds, tenantId) {
+			tenantIds = append(tenantIds, tenantId)
+		}
+	}
+
+	slices.Sort(tenantIds)
+
+	return tenantIds, nil
+}
+
+// removeTag untags a book, the caller must hold the write lock.
+func (m *memoryBookRepositoryImpl) removeTag(bookId uint, tagId uint) {
+	tagIds := m.tags[bookId]
+
+	i, found := slices.BinarySearch(tagIds, tagId)
+	if !found {
+		return
+	}
+
+	if len(tagIds) == 1 {
+		delete(m.tags, bookId)
+		return
+	}
+
+	m.tags[bookId] = slices.Delete(tagIds, i, i+1)
+}
+
+// tenantKey is a key unique within a tenant, the in-memory repositories index by it so
+// different tenants may use the same one.
+type tenantKey struct {
+	tenantId string
+	key      string
+}
+
+// owned returns the stored book with bookId when it belongs to the tenant of ctx, the
+// caller must hold the lock.
+func (m *memoryBookRepositoryImpl) owned(ctx context.Context, bookId uint) (*domain.Book, bool) {
+	book, ok := m.books[bookId]
+
+	return book, ok && m.tenants[bookId] == tenant.FromContext(ctx)
+}
+
+// taken tells whether a book of the tenant other than the one with id ownId has the dedupe
+// key of book, the caller must hold the lock.
+func (m *memoryBookRepositoryImpl) taken(tenantId string, book *domain.Book, ownId uint) bool {
+	key := m.dedupeKey(book)
+	if key == "" {
+		return false
+	}
+
+	id, ok := m.keys[tenantKey{tenantId, key}]
+
+	return ok && id != ownId
+}
+
+// store saves book for the tenant and reindexes its dedupe key, the caller must hold the
+// write lock.
+func (m *memoryBookRepositoryImpl) store(tenantId string, book *domain.Book) {
+	if previous, ok := m.books[book.Id]; ok {
+		delete(m.keys, tenantKey{tenantId, m.dedupeKey(previous)})
+	}
+
+	if key := m.dedupeKey(book); key != "" {
+		m.keys[tenantKey{tenantId, key}] = book.Id
+	}
+
+	m.books[book.Id] = book
+	m.tenants[book.Id] = tenantId
+}
+
+// filter returns copies of the books of the tenant matching params, the caller must hold
+// the lock.
+func (m *memoryBookRepositoryImpl) filter(tenantId string, params *domain.BookListParams) []*domain.Book {
+	books := []*domain.Book{}
+	titleContains := strings.ToLower(params.TitleContains)
+
+	for _, book := range m.books {
+		if m.tenants[book.Id] != tenantId {
+			continue
+		}
+
+		if params.Author != "" && book.Author != params.Author {
+			continue
+		}
+
+		if titleContains != "" && !strings.Contains(strings.ToLower(book.Title), titleContains) {
+			continue
+		}
+
+		if !m.tagged(book.Id, params.TagIds) {
+			continue
+		}
+
+		found := *book
+		books = append(books, &found)
+	}
+
+	return books
+}
+
+// tagged tells whether the book carries a tag of every group, the caller must hold the lock.
+func (m *memoryBookRepositoryImpl) tagged(bookId uint, groups [][]uint) bool {
+	for _, group := range groups {
+		if !slices.ContainsFunc(group, func(tagId uint) bool {
+			_, found := slices.BinarySearch(m.tags[bookId], tagId)
+			return found
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compareBooks(a, b *domain.Book, field domain.SortField) int {
+	var order int
+
+	switch field.Field {
+	case "id":
+		order = cmp.Compare(a.Id, b.Id)
+	case "title":
+		order = strings.Compare(a.Title, b.Title)
+	case "author":
+		order = strings.Compare(a.Author, b.Author)
+	}
+
+	if field.Desc {
+		return -order
+	}
+
+	return order
+}
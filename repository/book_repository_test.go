@@ -1,14 +1,19 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"gin-go-testing/config"
 	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -17,7 +22,7 @@ type unitTestBookRepositorySuite struct {
 	br   BookRepository
 	mock sqlmock.Sqlmock
 	db   *sql.DB
-	ctx  *gin.Context
+	ctx  context.Context
 }
 
 func TestUnitTestBookRepository(t *testing.T) {
@@ -25,9 +30,12 @@ func TestUnitTestBookRepository(t *testing.T) {
 }
 
 func (u *unitTestBookRepositorySuite) SetupTest() {
-	u.br = NewBookRepositoryImpl()
+	cfg := config.Default()
+	cfg.Pagination.CursorSecret = "secret"
 
-	u.ctx = &gin.Context{}
+	u.br = NewBookRepositoryImpl(cfg)
+
+	u.ctx = context.Background()
 	db, mock, _ := sqlmock.New()
 
 	u.mock = mock
@@ -45,9 +53,9 @@ func (u *unitTestBookRepositorySuite) TestFindOneById_Success() {
 		Author: "James Clear",
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "author"}).AddRow(data.Id, data.Title, data.Author)
+	rows := bookRows([]driver.Value{data.Id, data.Title, data.Author})
 
-	u.mock.ExpectQuery(`SELECT id, title, author FROM books WHERE id=\$1`).WithArgs(1).WillReturnRows(rows)
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(1, "default").WillReturnRows(rows)
 
 	result, err := u.br.FindOneById(u.ctx, u.db, 1)
 
@@ -62,19 +70,77 @@ func (u *unitTestBookRepositorySuite) TestFindOneById_Success() {
 	}
 }
 
+func (u *unitTestBookRepositorySuite) TestFindOneById_Details() {
+	rows := bookRows([]driver.Value{1, "Dune", "Frank Herbert", "9780441013593", 1965, "Chilton Books", "en", 412, "A desert planet.", "https://example.com/dune.jpg"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(1, "default").WillReturnRows(rows)
+
+	result, err := u.br.FindOneById(u.ctx, u.db, 1)
+
+	u.Nil(err)
+	u.Equal("9780441013593", *result.Isbn)
+	u.Equal(1965, *result.PublicationYear)
+	u.Equal("Chilton Books", *result.Publisher)
+	u.Equal("en", *result.Language)
+	u.Equal(412, *result.PageCount)
+	u.Equal("A desert planet.", *result.Description)
+	u.Equal("https://example.com/dune.jpg", *result.CoverUrl)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindOneById_NullDetails() {
+	rows := bookRows([]driver.Value{1, "Dune", "Frank Herbert"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(1, "default").WillReturnRows(rows)
+
+	result, err := u.br.FindOneById(u.ctx, u.db, 1)
+
+	u.Nil(err)
+	u.Equal(&domain.Book{Id: 1, Title: "Dune", Author: "Frank Herbert"}, result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindOneById_ContextCanceled() {
+	ctx, cancel := context.WithCancel(u.ctx)
+	cancel()
+
+	result, err := u.br.FindOneById(ctx, u.db, 1)
+
+	u.Nil(result)
+	u.Equal(http.StatusInternalServerError, err.StatusCode())
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
 func (u *unitTestBookRepositorySuite) TestFindOneById_Failed() {
-	u.mock.ExpectQuery(`SELECT id, title, author FROM books WHERE id=\$1`).WithArgs(2).WillReturnError(sql.ErrNoRows)
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(2, "default").WillReturnError(sql.ErrNoRows)
 
 	result, err := u.br.FindOneById(u.ctx, u.db, 2)
 
 	u.Nil(result)
-	u.NotNil(err)
+	u.Equal(ErrNotFound, kindOf(err))
 
 	if err := u.mock.ExpectationsWereMet(); err != nil {
 		u.T().Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
 
+func (u *unitTestBookRepositorySuite) TestFindDuplicate_Success() {
+	rows := bookRows([]driver.Value{3, "Dune", "Frank Herbert"})
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE dedupe_key=\$1 AND tenant_id=\$2`).WithArgs("dune\x1ffrank herbert", "default").WillReturnRows(rows)
+
+	result, err := u.br.FindDuplicate(u.ctx, u.db, &domain.Book{Title: "  Dune", Author: "Frank   HERBERT"})
+
+	u.Nil(err)
+	u.Equal(&domain.Book{Id: 3, Title: "Dune", Author: "Frank Herbert"}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
 func (u *unitTestBookRepositorySuite) TestFindAll_Success() {
 	data := []*domain.Book{{
 		Id:     1,
@@ -92,11 +158,11 @@ func (u *unitTestBookRepositorySuite) TestFindAll_Success() {
 		values = append(values, []driver.Value{e.Id, e.Title, e.Author})
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "author"}).AddRows(values...)
+	rows := bookRows(values...)
 
-	u.mock.ExpectQuery(`SELECT id, title, author FROM books`).WithoutArgs().WillReturnRows(rows)
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 ORDER BY id ASC LIMIT \$2`).WithArgs("default", 20).WillReturnRows(rows).RowsWillBeClosed()
 
-	result, err := u.br.FindAll(u.ctx, u.db)
+	result, err := u.br.FindAll(u.ctx, u.db, &domain.BookListParams{Limit: 20})
 
 	u.Nil(err)
 	u.NotNil(result)
@@ -107,13 +173,148 @@ func (u *unitTestBookRepositorySuite) TestFindAll_Success() {
 	}
 }
 
+func (u *unitTestBookRepositorySuite) TestFindAll_Empty() {
+	rows := bookRows()
+	u.mock.ExpectQuery(`SELECT ` + bookColumns + ` FROM books WHERE tenant_id=\$1 ORDER BY id ASC`).WithArgs("default").WillReturnRows(rows).RowsWillBeClosed()
+
+	result, err := u.br.FindAll(u.ctx, u.db, &domain.BookListParams{})
+	u.Nil(err)
+	u.NotNil(result)
+	u.Empty(result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func (u *unitTestBookRepositorySuite) TestFindAll_Failed() {
-	rows := sqlmock.NewRows([]string{"id", "title", "author"}).AddRows([][]driver.Value{}...)
-	u.mock.ExpectQuery(`SELECT id, title, author FROM books`).WithoutArgs().WillReturnRows(rows)
+	u.mock.ExpectQuery(`SELECT ` + bookColumns + ` FROM books WHERE tenant_id=\$1 ORDER BY id ASC`).WithArgs("default").WillReturnError(sql.ErrConnDone)
+
+	result, err := u.br.FindAll(u.ctx, u.db, &domain.BookListParams{})
+	u.Nil(result)
+	u.Equal(ErrConnectionLost, kindOf(err))
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// TestFindAll_BrokenOffMidway makes sure an error while reading rows is not taken for the
+// end of the listing.
+func (u *unitTestBookRepositorySuite) TestFindAll_BrokenOffMidway() {
+	rows := bookRows([]driver.Value{1, "Dune", "Frank Herbert"}, []driver.Value{2, "Emma", "Jane Austen"}).RowError(1, sql.ErrConnDone)
+	u.mock.ExpectQuery(`SELECT ` + bookColumns + ` FROM books WHERE tenant_id=\$1 ORDER BY id ASC`).WithArgs("default").WillReturnRows(rows).RowsWillBeClosed()
+
+	result, err := u.br.FindAll(u.ctx, u.db, &domain.BookListParams{})
+	u.Nil(result)
+	u.Equal(ErrConnectionLost, kindOf(err))
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAll_FilterSortAndPage() {
+	params := &domain.BookListParams{
+		Limit:         10,
+		Offset:        20,
+		Sort:          []domain.SortField{{Field: "title"}, {Field: "id", Desc: true}},
+		Author:        "James Clear",
+		TitleContains: "100%_habits",
+	}
+
+	rows := bookRows([]driver.Value{1, "100%_habits", "James Clear"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 AND author=\$2 AND LOWER\(title\) LIKE LOWER\(\$3\) ESCAPE '\\' ORDER BY title ASC, id DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs("default", "James Clear", `%100\%\_habits%`, 10, 20).
+		WillReturnRows(rows)
+
+	result, err := u.br.FindAll(u.ctx, u.db, params)
+
+	u.Nil(err)
+	u.Len(result, 1)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAll_TagGroups() {
+	params := &domain.BookListParams{TagIds: [][]uint{{1, 4}, {9}}}
+
+	rows := bookRows([]driver.Value{3, "The Hobbit", "J.R.R. Tolkien"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 AND id IN \(SELECT book_id FROM book_tags WHERE tag_id IN \(\$2,\$3\)\) AND id IN \(SELECT book_id FROM book_tags WHERE tag_id IN \(\$4\)\) ORDER BY id ASC`).
+		WithArgs("default", 1, 4, 9).
+		WillReturnRows(rows)
+
+	result, err := u.br.FindAll(u.ctx, u.db, params)
+
+	u.Nil(err)
+	u.Len(result, 1)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestCountTags_Filtered() {
+	params := &domain.BookListParams{Limit: 10, Author: "J.R.R. Tolkien", TagIds: [][]uint{{}}}
+
+	u.mock.ExpectQuery(`SELECT tag_id, COUNT\(\*\) FROM book_tags WHERE book_id IN \(SELECT id FROM books WHERE tenant_id=\$1 AND author=\$2 AND 1=0\) GROUP BY tag_id`).
+		WithArgs("default", "J.R.R. Tolkien").
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id", "count"}))
+
+	result, err := u.br.CountTags(u.ctx, u.db, params)
+
+	u.Nil(err)
+	u.Empty(result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestAddTag_AnotherTenant() {
+	u.ctx = tenant.WithContext(u.ctx, "acme")
+
+	// book 2 belongs to another tenant, so nothing is linked
+	u.mock.ExpectExec(`INSERT INTO book_tags\(book_id, tag_id\) SELECT books.id, tags.id FROM books, tags WHERE books.id=\$1 AND books.tenant_id=\$3 AND tags.id=\$2 AND tags.tenant_id=\$3 ON CONFLICT DO NOTHING`).
+		WithArgs(2, 9, "acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := u.br.AddTag(u.ctx, u.db, 2, 9)
+
+	u.Nil(err)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAll_InvalidSortField() {
+	params := &domain.BookListParams{Sort: []domain.SortField{{Field: "title; DROP TABLE books"}}}
+
+	result, err := u.br.FindAll(u.ctx, u.db, params)
 
-	result, err := u.br.FindAll(u.ctx, u.db)
 	u.Nil(result)
 	u.NotNil(err)
+	u.Equal(http.StatusBadRequest, err.StatusCode())
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestCount_Success() {
+	row := sqlmock.NewRows([]string{"count"}).AddRow(42)
+
+	u.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE tenant_id=\$1 AND author=\$2`).WithArgs("default", "James Clear").WillReturnRows(row)
+
+	total, err := u.br.Count(u.ctx, u.db, &domain.BookListParams{Limit: 10, Author: "James Clear"})
+
+	u.Nil(err)
+	u.Equal(uint(42), total)
 
 	if err := u.mock.ExpectationsWereMet(); err != nil {
 		u.T().Errorf("there were unfulfilled expectations: %s", err)
@@ -127,8 +328,10 @@ func (u *unitTestBookRepositorySuite) TestCreate_Success() {
 		Author: "James Clear",
 	}
 
+	dedupeKey := "atomic habits: an easy & proven way to build good habits & break bad ones\x1fjames clear"
+
 	row := sqlmock.NewRows([]string{"id"}).AddRow(data.Id)
-	u.mock.ExpectQuery(`INSERT INTO books\(title, author\) VALUES\(\$1,\$2\) RETURNING id`).WithArgs(data.Title, data.Author).WillReturnRows(row)
+	u.mock.ExpectQuery(`INSERT INTO books\(title, author, isbn, publication_year, publisher, language, page_count, description, cover_url, dedupe_key, tenant_id\) VALUES\(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11\) RETURNING id`).WithArgs(bookArgs(data, dedupeKey, "default")...).WillReturnRows(row)
 
 	result, err := u.br.Create(u.ctx, u.db, data)
 
@@ -148,7 +351,7 @@ func (u *unitTestBookRepositorySuite) TestCreate_Failed() {
 		Author: "James Clear",
 	}
 
-	u.mock.ExpectQuery(`INSERT INTO books\(title, author\) VALUES\(\$1,\$2\) RETURNING id`).WithArgs(data.Title, data.Author).WillReturnError(errors.New("some error in db"))
+	u.mock.ExpectQuery(`INSERT INTO books\(title, author, isbn, publication_year, publisher, language, page_count, description, cover_url, dedupe_key, tenant_id\) VALUES\(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11\) RETURNING id`).WithArgs(bookArgs(data, sqlmock.AnyArg(), "default")...).WillReturnError(errors.New("some error in db"))
 
 	result, err := u.br.Create(u.ctx, u.db, data)
 
@@ -159,3 +362,292 @@ func (u *unitTestBookRepositorySuite) TestCreate_Failed() {
 		u.T().Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func (u *unitTestBookRepositorySuite) TestCreate_UniqueViolation() {
+	data := &domain.Book{Title: "Dune", Author: "Frank Herbert"}
+	driverErr := &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}
+
+	u.mock.ExpectQuery(`INSERT INTO books\(title, author, isbn, publication_year, publisher, language, page_count, description, cover_url, dedupe_key, tenant_id\) VALUES\(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11\) RETURNING id`).WithArgs(bookArgs(data, sqlmock.AnyArg(), "default")...).WillReturnError(driverErr)
+
+	result, err := u.br.Create(u.ctx, u.db, data)
+
+	u.Nil(result)
+	u.Equal(ErrUniqueViolation, kindOf(err))
+
+	var pqErr *pq.Error
+	u.ErrorAs(err.(error), &pqErr)
+	u.Same(driverErr, pqErr)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestBookRepositorySuite) TestUpdate_Success() {
+	data := &domain.Book{
+		Id:     1,
+		Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author: "James Clear",
+	}
+
+	u.mock.ExpectExec(`UPDATE books SET title=\$1, author=\$2, isbn=\$3, publication_year=\$4, publisher=\$5, language=\$6, page_count=\$7, description=\$8, cover_url=\$9, dedupe_key=\$10 WHERE id=\$11 AND tenant_id=\$12`).WithArgs(bookArgs(data, sqlmock.AnyArg(), data.Id, "default")...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := u.br.Update(u.ctx, u.db, data)
+
+	u.Nil(err)
+	u.NotNil(result)
+	u.Equal(data, result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestUpdate_NotFound() {
+	data := &domain.Book{
+		Id:     2,
+		Title:  "The 7 Habits of Highly Effective People",
+		Author: "Stephen R. Covey",
+	}
+
+	u.mock.ExpectExec(`UPDATE books SET title=\$1, author=\$2, isbn=\$3, publication_year=\$4, publisher=\$5, language=\$6, page_count=\$7, description=\$8, cover_url=\$9, dedupe_key=\$10 WHERE id=\$11 AND tenant_id=\$12`).WithArgs(bookArgs(data, sqlmock.AnyArg(), data.Id, "default")...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	result, err := u.br.Update(u.ctx, u.db, data)
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(ErrNotFound, kindOf(err))
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestUpdate_Failed() {
+	data := &domain.Book{
+		Id:     2,
+		Title:  "The 7 Habits of Highly Effective People",
+		Author: "Stephen R. Covey",
+	}
+
+	u.mock.ExpectExec(`UPDATE books SET title=\$1, author=\$2, isbn=\$3, publication_year=\$4, publisher=\$5, language=\$6, page_count=\$7, description=\$8, cover_url=\$9, dedupe_key=\$10 WHERE id=\$11 AND tenant_id=\$12`).WithArgs(bookArgs(data, sqlmock.AnyArg(), data.Id, "default")...).WillReturnError(errors.New("some error in db"))
+
+	result, err := u.br.Update(u.ctx, u.db, data)
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(http.StatusInternalServerError, err.StatusCode())
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestDelete_Success() {
+	u.mock.ExpectExec(`DELETE FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(1, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := u.br.Delete(u.ctx, u.db, 1)
+
+	u.Nil(err)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestDelete_NotFound() {
+	u.mock.ExpectExec(`DELETE FROM books WHERE id=\$1 AND tenant_id=\$2`).WithArgs(2, "default").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := u.br.Delete(u.ctx, u.db, 2)
+
+	u.NotNil(err)
+	u.Equal(ErrNotFound, kindOf(err))
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestPatch_Success() {
+	data := &domain.Book{
+		Id:     1,
+		Title:  "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones",
+		Author: "James Clear",
+	}
+
+	u.mock.ExpectExec(`UPDATE books SET author=\$1, dedupe_key=\$2 WHERE id=\$3 AND tenant_id=\$4`).WithArgs(data.Author, sqlmock.AnyArg(), data.Id, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := u.br.Patch(u.ctx, u.db, data, []string{"author"})
+
+	u.Nil(err)
+	u.Equal(data, result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestPatch_Details() {
+	data := &domain.Book{Id: 1, Title: "Dune", Author: "Frank Herbert"}
+
+	// a removed detail is written as NULL, the isbn is one of the deduped columns
+	u.mock.ExpectExec(`UPDATE books SET isbn=\$1, publisher=\$2, dedupe_key=\$3 WHERE id=\$4 AND tenant_id=\$5`).WithArgs(nil, nil, sqlmock.AnyArg(), data.Id, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := u.br.Patch(u.ctx, u.db, data, []string{"isbn", "publisher"})
+
+	u.Nil(err)
+	u.Equal(data, result)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestPatch_NotFound() {
+	data := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+
+	u.mock.ExpectExec(`UPDATE books SET title=\$1, author=\$2, dedupe_key=\$3 WHERE id=\$4 AND tenant_id=\$5`).WithArgs(data.Title, data.Author, sqlmock.AnyArg(), data.Id, "default").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	result, err := u.br.Patch(u.ctx, u.db, data, []string{"title", "author"})
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(ErrNotFound, kindOf(err))
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestPatch_UnknownColumn() {
+	data := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+
+	result, err := u.br.Patch(u.ctx, u.db, data, []string{"id"})
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(http.StatusInternalServerError, err.StatusCode())
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAllByCursor_Pages() {
+	params := &domain.BookListParams{Limit: 2, Sort: []domain.SortField{{Field: "title", Desc: true}}}
+
+	rows := bookRows(
+		[]driver.Value{2, "The 7 Habits of Highly Effective People", "Stephen R. Covey"},
+		[]driver.Value{3, "Deep Work", "Cal Newport"},
+		[]driver.Value{1, "Atomic Habits", "James Clear"},
+	)
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 ORDER BY title DESC, id DESC LIMIT \$2`).WithArgs("default", 3).WillReturnRows(rows)
+
+	result, nextCursor, err := u.br.FindAllByCursor(u.ctx, u.db, params, "")
+
+	u.Nil(err)
+	u.Len(result, 2)
+	u.NotEmpty(nextCursor)
+
+	// the cursor keeps the order it was issued for, even when the next request asks for another sort
+	params.Sort = []domain.SortField{{Field: "author"}}
+	rows = bookRows([]driver.Value{1, "Atomic Habits", "James Clear"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 AND \(title, id\) < \(\$2, \$3\) ORDER BY title DESC, id DESC LIMIT \$4`).
+		WithArgs("default", "Deep Work", 3, 3).
+		WillReturnRows(rows)
+
+	result, nextCursor, err = u.br.FindAllByCursor(u.ctx, u.db, params, nextCursor)
+
+	u.Nil(err)
+	u.Len(result, 1)
+	u.Empty(nextCursor)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAllByCursor_ById() {
+	params := &domain.BookListParams{Limit: 1, Author: "James Clear"}
+
+	rows := bookRows(
+		[]driver.Value{1, "Atomic Habits", "James Clear"},
+		[]driver.Value{4, "Atomic Habits Workbook", "James Clear"},
+	)
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 AND author=\$2 ORDER BY id ASC LIMIT \$3`).WithArgs("default", "James Clear", 2).WillReturnRows(rows)
+
+	_, nextCursor, err := u.br.FindAllByCursor(u.ctx, u.db, params, "")
+	u.Nil(err)
+
+	rows = bookRows([]driver.Value{4, "Atomic Habits Workbook", "James Clear"})
+
+	u.mock.ExpectQuery(`SELECT `+bookColumns+` FROM books WHERE tenant_id=\$1 AND author=\$2 AND id > \$3 ORDER BY id ASC LIMIT \$4`).WithArgs("default", "James Clear", 1, 2).WillReturnRows(rows)
+
+	result, nextCursor, err := u.br.FindAllByCursor(u.ctx, u.db, params, nextCursor)
+
+	u.Nil(err)
+	u.Equal([]*domain.Book{{Id: 4, Title: "Atomic Habits Workbook", Author: "James Clear"}}, result)
+	u.Empty(nextCursor)
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAllByCursor_TamperedCursor() {
+	codec := &cursorCodec{secret: []byte("another secret")}
+	forged, _ := codec.encode(&bookCursor{Field: "id", Id: 100})
+
+	result, nextCursor, err := u.br.FindAllByCursor(u.ctx, u.db, &domain.BookListParams{Limit: 10}, forged)
+
+	u.Nil(result)
+	u.Empty(nextCursor)
+	u.NotNil(err)
+	u.Equal(http.StatusBadRequest, err.StatusCode())
+
+	if err := u.mock.ExpectationsWereMet(); err != nil {
+		u.T().Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (u *unitTestBookRepositorySuite) TestFindAllByCursor_MultipleSortFields() {
+	params := &domain.BookListParams{Limit: 10, Sort: []domain.SortField{{Field: "title"}, {Field: "author"}}}
+
+	result, _, err := u.br.FindAllByCursor(u.ctx, u.db, params, "")
+
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(http.StatusBadRequest, err.StatusCode())
+}
+
+// bookRows returns rows of the bookColumns, a row shorter than them has NULL details.
+func bookRows(values ...[]driver.Value) *sqlmock.Rows {
+	columns := strings.Split(bookColumns, ", ")
+	rows := sqlmock.NewRows(columns)
+
+	for _, row := range values {
+		for len(row) < len(columns) {
+			row = append(row, nil)
+		}
+
+		rows.AddRow(row...)
+	}
+
+	return rows
+}
+
+// bookArgs returns the arguments createQuery and updateQuery are run with for book, followed by extra.
+func bookArgs(book *domain.Book, extra ...driver.Value) []driver.Value {
+	var args []driver.Value
+
+	for _, value := range bookValues(book) {
+		arg, err := driver.DefaultParameterConverter.ConvertValue(value)
+		if err != nil {
+			panic(err)
+		}
+
+		args = append(args, arg)
+	}
+
+	return append(args, extra...)
+}
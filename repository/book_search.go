@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// BookSearcher finds books by the words of their title, author and description. A book
+// matches when every word of the query matches one of its words exactly, as a prefix or,
+// for longer words, with a typo. Only the books of the tenant carried by ctx are searched,
+// see tenant.FromContext.
+type BookSearcher interface {
+	// Search returns a page of the matching books, best matches first, and how many match.
+	Search(ctx context.Context, db DBTX, params *domain.BookSearchParams) ([]*domain.BookSearchHit, uint, errs.CustomError)
+	// Index adds a stored book of the tenant of ctx to the index or replaces it there. It must
+	// be called once the book is committed, searchers backed by the database ignore it.
+	Index(ctx context.Context, book *domain.Book)
+	// Remove drops a deleted book of the tenant of ctx from the index.
+	Remove(ctx context.Context, bookId uint)
+	// Rebuild indexes every book stored by br from scratch, those of every tenant.
+	Rebuild(ctx context.Context, db DBTX, br BookRepository) errs.CustomError
+}
+
+// NewBookSearcher picks the searcher matching the configured database driver. SQLite has no
+// full-text search worth the name built in, so it shares the in-memory index.
+func NewBookSearcher(cfg *config.Config) (BookSearcher, error) {
+	switch cfg.Database.Driver {
+	case "postgres":
+		return NewBookSearcherImpl(cfg), nil
+	case "sqlite", "memory":
+		return NewMemoryBookSearcherImpl(), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+}
+
+// searchTerms splits s into lowercase words of letters and digits, dropping repeated words.
+func searchTerms(s string) []string {
+	terms := []string{}
+
+	for _, term := range strings.FieldsFunc(strings.ToLower(s), isNotWordRune) {
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// queryTerms are the search terms of params, a query without any fails as there is nothing
+// to match.
+func queryTerms(params *domain.BookSearchParams) ([]string, errs.CustomError) {
+	terms := searchTerms(params.Query)
+
+	if len(terms) == 0 {
+		return nil, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "q must contain at least one letter or digit")
+	}
+
+	return terms, nil
+}
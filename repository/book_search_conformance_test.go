@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// conformanceBookSearcherSuite runs the same scenarios against every BookSearcher, paired
+// with the book repository of the same backend. Scores differ between searchers, so only
+// the order of hits is compared.
+type conformanceBookSearcherSuite struct {
+	suite.Suite
+	setup func(cfg *config.Config) (BookSearcher, BookRepository, *sql.DB)
+	s     BookSearcher
+	br    BookRepository
+	db    *sql.DB
+	ctx   context.Context
+}
+
+func TestConformanceMemoryBookSearcher(t *testing.T) {
+	suite.Run(t, &conformanceBookSearcherSuite{
+		setup: func(cfg *config.Config) (BookSearcher, BookRepository, *sql.DB) {
+			return NewMemoryBookSearcherImpl(), NewMemoryBookRepositoryImpl(cfg), nil
+		},
+	})
+}
+
+func TestConformanceSQLiteBookSearcher(t *testing.T) {
+	s := &conformanceBookSearcherSuite{}
+	s.setup = func(cfg *config.Config) (BookSearcher, BookRepository, *sql.DB) {
+		db := openSQLite(s.T())
+
+		searcher, err := NewBookSearcher(&config.Config{Database: config.DatabaseConfig{Driver: "sqlite"}})
+		s.Require().NoError(err)
+
+		return searcher, NewSQLiteBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestConformancePostgresBookSearcher needs a disposable database, its tables are
+// truncated before every test.
+func TestConformancePostgresBookSearcher(t *testing.T) {
+	dsn := testDatabaseURL(t)
+
+	s := &conformanceBookSearcherSuite{}
+	s.setup = func(cfg *config.Config) (BookSearcher, BookRepository, *sql.DB) {
+		db := openPostgres(s.T(), dsn)
+
+		return NewBookSearcherImpl(cfg), NewBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+func (c *conformanceBookSearcherSuite) SetupTest() {
+	c.s, c.br, c.db = c.setup(config.Default())
+	c.ctx = context.Background()
+}
+
+func (c *conformanceBookSearcherSuite) TearDownTest() {
+	if c.db != nil {
+		c.db.Close()
+	}
+}
+
+// createBook stores a book and indexes it the way the service does once it is committed.
+func (c *conformanceBookSearcherSuite) createBook(title string, author string, description string) *domain.Book {
+	book := &domain.Book{Title: title, Author: author}
+	if description != "" {
+		book.Description = &description
+	}
+
+	book, err := c.br.Create(c.ctx, c.db, book)
+	c.Require().Nil(err)
+
+	c.s.Index(c.ctx, book)
+
+	return book
+}
+
+func (c *conformanceBookSearcherSuite) search(query string) []string {
+	hits, total, err := c.s.Search(c.ctx, c.db, &domain.BookSearchParams{Query: query})
+	c.Require().Nil(err)
+	c.Equal(uint(len(hits)), total)
+
+	return hitTitles(hits)
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_RanksTitleAboveDescription() {
+	c.createBook("The Sands of Arrakis", "Frank Herbert", "Stories set among the dunes of a desert planet.")
+	c.createBook("Dune", "Frank Herbert", "")
+	c.createBook("Emma", "Jane Austen", "")
+
+	c.Equal([]string{"Dune", "The Sands of Arrakis"}, c.search("dune"))
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_PrefixesAndTypos() {
+	c.createBook("Dune Messiah", "Frank Herbert", "")
+	c.createBook("Foundation", "Isaac Asimov", "")
+
+	c.Equal([]string{"Dune Messiah"}, c.search("mess"))
+	c.Equal([]string{"Dune Messiah"}, c.search("herbrt"))
+	c.Equal([]string{"Foundation"}, c.search("fuondation"))
+	// short words have to be spelled right
+	c.Empty(c.search("dnu"))
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_EveryWordMatches() {
+	c.createBook("Dune", "Frank Herbert", "")
+	c.createBook("Dune Messiah", "Frank Herbert", "")
+
+	c.Equal([]string{"Dune Messiah"}, c.search("dune messiah"))
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_Highlights() {
+	c.createBook("Dune & Co", "Frank Herbert", "The <b>spice</b> must flow.")
+
+	hits, _, err := c.s.Search(c.ctx, c.db, &domain.BookSearchParams{Query: "dune spice"})
+	c.Require().Nil(err)
+	c.Require().Len(hits, 1)
+
+	c.Equal(domain.BookHighlight{
+		Title:       "<mark>Dune</mark> &amp; Co",
+		Author:      "Frank Herbert",
+		Description: "The &lt;b&gt;<mark>spice</mark>&lt;/b&gt; must flow.",
+	}, hits[0].Highlight)
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_Pages() {
+	for _, title := range []string{"Dune", "Dune Messiah", "Children of Dune"} {
+		c.createBook(title, "Frank Herbert", "")
+	}
+
+	hits, total, err := c.s.Search(c.ctx, c.db, &domain.BookSearchParams{Query: "dune", Limit: 2, Offset: 2})
+	c.Nil(err)
+	c.Len(hits, 1)
+	c.Equal(uint(3), total)
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_NoWords() {
+	_, _, err := c.s.Search(c.ctx, c.db, &domain.BookSearchParams{Query: " -- "})
+
+	c.Equal(422, err.StatusCode())
+}
+
+func (c *conformanceBookSearcherSuite) TestIndex_FollowsChanges() {
+	book := c.createBook("Dune", "Frank Herbert", "")
+	other := c.createBook("Emma", "Jane Austen", "")
+
+	book.Title = "Dune Messiah"
+	_, err := c.br.Patch(c.ctx, c.db, book, []string{"title"})
+	c.Require().Nil(err)
+	c.s.Index(c.ctx, book)
+
+	c.Require().Nil(c.br.Delete(c.ctx, c.db, other.Id))
+	c.s.Remove(c.ctx, other.Id)
+
+	c.Equal([]string{"Dune Messiah"}, c.search("messiah"))
+	c.Empty(c.search("emma"))
+}
+
+func (c *conformanceBookSearcherSuite) TestRebuild_IndexesStoredBooks() {
+	_, err := c.br.Create(c.ctx, c.db, &domain.Book{Title: "Dune", Author: "Frank Herbert"})
+	c.Require().Nil(err)
+
+	c.Nil(c.s.Rebuild(c.ctx, c.db, c.br))
+
+	c.Equal([]string{"Dune"}, c.search("dune"))
+}
+
+func (c *conformanceBookSearcherSuite) TestSearch_OnlyTheTenant() {
+	c.createBook("Dune", "Frank Herbert", "")
+
+	c.ctx = tenant.WithContext(c.ctx, "acme")
+	c.createBook("Dune Messiah", "Frank Herbert", "")
+
+	c.Equal([]string{"Dune Messiah"}, c.search("dune"))
+
+	c.Nil(c.s.Rebuild(c.ctx, c.db, c.br))
+
+	c.Equal([]string{"Dune Messiah"}, c.search("dune"))
+
+	c.ctx = context.Background()
+	c.Equal([]string{"Dune"}, c.search("dune"))
+}
+
+func hitTitles(hits []*domain.BookSearchHit) []string {
+	titles := []string{}
+
+	for _, hit := range hits {
+		titles = append(titles, hit.Book.Title)
+	}
+
+	return titles
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type bookSearcherImpl struct {
+	queryTimeout time.Duration
+}
+
+// NewBookSearcherImpl creates the Postgres searcher, backed by the generated search columns
+// of books. Postgres keeps them up to date itself.
+func NewBookSearcherImpl(cfg *config.Config) BookSearcher {
+	return &bookSearcherImpl{queryTimeout: cfg.Database.QueryTimeout}
+}
+
+func (b *bookSearcherImpl) Search(ctx context.Context, db DBTX, params *domain.BookSearchParams) ([]*domain.BookSearchHit, uint, errs.CustomError) {
+	terms, errTerms := queryTerms(params)
+	if errTerms != nil {
+		return nil, 0, errTerms
+	}
+
+	queryCtx, cancel := withTimeout(ctx, b.queryTimeout)
+	defer cancel()
+
+	tenantId := tenant.FromContext(ctx)
+
+	query, args := buildSearchQuery(tenantId, terms, params)
+
+	rows, err := db.QueryContext(queryCtx, query, args...)
+	if err != nil {
+		return nil, 0, newError(ctx, "SearchBook", err)
+	}
+	defer rows.Close()
+
+	hits := []*domain.BookSearchHit{}
+
+	for rows.Next() {
+		hit := &domain.BookSearchHit{Book: &domain.Book{}}
+		var title, author string
+		var description sql.NullString
+
+		if err := rows.Scan(append(bookFields(hit.Book), &hit.Score, &title, &author, &description)...); err != nil {
+			return nil, 0, newError(ctx, "SearchBook", err)
+		}
+
+		hit.Highlight = domain.BookHighlight{
+			Title:       markHighlight(title),
+			Author:      markHighlight(author),
+			Description: markHighlight(description.String),
+		}
+
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, newError(ctx, "SearchBook", err)
+	}
+
+	var total uint
+
+	query, args = buildCountSearchQuery(tenantId, terms)
+
+	if err := db.QueryRowContext(queryCtx, query, args...).Scan(&total); err != nil {
+		return nil, 0, newError(ctx, "CountSearchBook", err)
+	}
+
+	return hits, total, nil
+}
+
+func (b *bookSearcherImpl) Index(ctx context.Context, book *domain.Book) {}
+
+func (b *bookSearcherImpl) Remove(ctx context.Context, bookId uint) {}
+
+func (b *bookSearcherImpl) Rebuild(ctx context.Context, db DBTX, br BookRepository) errs.CustomError {
+	return nil
+}
+
+// markHighlight escapes a headline and turns its match markers into <mark> tags.
+func markHighlight(headline string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(headline))
+}
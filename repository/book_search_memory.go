@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"html"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// The searched fields of a book, in the order of searchFieldWeights.
+const (
+	searchTitle = iota
+	searchAuthor
+	searchDescription
+)
+
+// searchFieldWeights rank a match in the title above one in the author above one in the
+// description, like the Postgres weights A, B and C.
+var searchFieldWeights = [3]float64{3, 2, 1}
+
+// How much a query term is worth when it matches an indexed term exactly, as a prefix or
+// with a typo.
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.8
+	typoMatch   = 0.5
+)
+
+// passageWords is the length of the description passage shown with a hit.
+const passageWords = 30
+
+type memoryBookSearcherImpl struct {
+	mu sync.RWMutex
+	// indexes holds the index of each tenant, a book is only ever found by its own tenant and
+	// ranked among the books of that tenant
+	indexes map[string]*searchIndex
+}
+
+// searchIndex is the inverted index of the books of one tenant.
+type searchIndex struct {
+	books map[uint]*domain.Book
+	// postings holds, per term, how often each book has it in each searched field
+	postings map[string]map[uint]*[3]int
+	// terms are the keys of postings in order, the terms starting with a prefix are found
+	// by binary search
+	terms []string
+	// bookTerms are the terms of each book, to take it out of postings again
+	bookTerms map[uint][]string
+}
+
+// NewMemoryBookSearcherImpl creates a thread-safe inverted index of books kept in memory.
+// It starts empty, Rebuild fills it from the stored books. The db argument of Search is
+// ignored.
+func NewMemoryBookSearcherImpl() BookSearcher {
+	return &memoryBookSearcherImpl{indexes: map[string]*searchIndex{}}
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		books:     map[uint]*domain.Book{},
+		postings:  map[string]map[uint]*[3]int{},
+		terms:     []string{},
+		bookTerms: map[uint][]string{},
+	}
+}
+
+// tenantIndex returns the index of the tenant, creating it when it has none yet. The caller
+// holds the write lock.
+func (m *memoryBookSearcherImpl) tenantIndex(tenantId string) *searchIndex {
+	index, ok := m.indexes[tenantId]
+	if !ok {
+		index = newSearchIndex()
+		m.indexes[tenantId] = index
+	}
+
+	return index
+}
+
+func (m *memoryBookSearcherImpl) Search(ctx context.Context, db DBTX, params *domain.BookSearchParams) ([]*domain.BookSearchHit, uint, errs.CustomError) {
+	terms, err := queryTerms(params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	index, ok := m.indexes[tenant.FromContext(ctx)]
+	if !ok {
+		return []*domain.BookSearchHit{}, 0, nil
+	}
+
+	hits, total := index.search(terms, params)
+
+	return hits, total, nil
+}
+
+// search returns a page of the books matching every term, best matches first, and how many
+// match. The caller holds the read lock.
+func (idx *searchIndex) search(terms []string, params *domain.BookSearchParams) ([]*domain.BookSearchHit, uint) {
+	// matched collects the indexed terms the query matched, they are the words highlighted
+	matched := map[string]bool{}
+	var scores map[uint]float64
+
+	for _, term := range terms {
+		termScores := map[uint]float64{}
+
+		for indexed, match := range idx.expand(term) {
+			matched[indexed] = true
+			postings := idx.postings[indexed]
+			idf := math.Log(1 + float64(len(idx.books))/float64(len(postings)))
+
+			for bookId, counts := range postings {
+				termScores[bookId] = max(termScores[bookId], match*idf*fieldScore(counts))
+			}
+		}
+
+		// every term has to match
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+
+		for bookId, score := range scores {
+			if termScore, ok := termScores[bookId]; ok {
+				scores[bookId] = score + termScore
+			} else {
+				delete(scores, bookId)
+			}
+		}
+	}
+
+	bookIds := make([]uint, 0, len(scores))
+	for bookId := range scores {
+		bookIds = append(bookIds, bookId)
+	}
+
+	slices.SortFunc(bookIds, func(a, b uint) int {
+		if order := cmp.Compare(scores[b], scores[a]); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	total := uint(len(bookIds))
+	bookIds = bookIds[min(params.Offset, total):]
+
+	if params.Limit > 0 {
+		bookIds = bookIds[:min(params.Limit, uint(len(bookIds)))]
+	}
+
+	hits := make([]*domain.BookSearchHit, 0, len(bookIds))
+
+	for _, bookId := range bookIds {
+		book := *idx.books[bookId]
+		fields := searchFields(&book)
+
+		hits = append(hits, &domain.BookSearchHit{
+			Book:  &book,
+			Score: scores[bookId],
+			Highlight: domain.BookHighlight{
+				Title:       highlight(fields[searchTitle], matched),
+				Author:      highlight(fields[searchAuthor], matched),
+				Description: passage(fields[searchDescription], matched),
+			},
+		})
+	}
+
+	return hits, total
+}
+
+func (m *memoryBookSearcherImpl) Index(ctx context.Context, book *domain.Book) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tenantIndex(tenant.FromContext(ctx)).index(book)
+}
+
+func (m *memoryBookSearcherImpl) Remove(ctx context.Context, bookId uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if index, ok := m.indexes[tenant.FromContext(ctx)]; ok {
+		index.remove(bookId)
+	}
+}
+
+func (m *memoryBookSearcherImpl) Rebuild(ctx context.Context, db DBTX, br BookRepository) errs.CustomError {
+	tenantIds, err := br.FindTenantIds(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	indexes := make(map[string]*searchIndex, len(tenantIds))
+
+	for _, tenantId := range tenantIds {
+		books, err := br.FindAll(tenant.WithContext(ctx, tenantId), db, &domain.BookListParams{})
+		if err != nil {
+			return err
+		}
+
+		index := newSearchIndex()
+		for _, book := range books {
+			index.index(book)
+		}
+
+		indexes[tenantId] = index
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.indexes = indexes
+
+	return nil
+}
+
+// index replaces the book in the index, the caller holds the write lock.
+func (idx *searchIndex) index(book *domain.Book) {
+	idx.remove(book.Id)
+
+	// the relations of a book are not searched, and would go stale here
+	stored := *book
+	stored.Authors, stored.Tags = nil, nil
+	idx.books[book.Id] = &stored
+
+	bookTerms := []string{}
+
+	for field, text := range searchFields(&stored) {
+		for _, term := range strings.FieldsFunc(strings.ToLower(text), isNotWordRune) {
+			postings, ok := idx.postings[term]
+			if !ok {
+				postings = map[uint]*[3]int{}
+				idx.postings[term] = postings
+
+				i, _ := slices.BinarySearch(idx.terms, term)
+				idx.terms = slices.Insert(idx.terms, i, term)
+			}
+
+			counts, ok := postings[book.Id]
+			if !ok {
+				counts = &[3]int{}
+				postings[book.Id] = counts
+				bookTerms = append(bookTerms, term)
+			}
+
+			counts[field]++
+		}
+	}
+
+	idx.bookTerms[book.Id] = bookTerms
+}
+
+// remove takes the book out of the index, the caller holds the write lock.
+func (idx *searchIndex) remove(bookId uint) {
+	for _, term := range idx.bookTerms[bookId] {
+		delete(idx.postings[term], bookId)
+
+		if len(idx.postings[term]) > 0 {
+			continue
+		}
+
+		delete(idx.postings, term)
+
+		if i, ok := slices.BinarySearch(idx.terms, term); ok {
+			idx.terms = slices.Delete(idx.terms, i, i+1)
+		}
+	}
+
+	delete(idx.bookTerms, bookId)
+	delete(idx.books, bookId)
+}
+
+// expand returns the indexed terms a query term matches, with how much each match is worth.
+func (idx *searchIndex) expand(term string) map[string]float64 {
+	expanded := map[string]float64{}
+
+	i, _ := slices.BinarySearch(idx.terms, term)
+	for _, indexed := range idx.terms[i:] {
+		if !strings.HasPrefix(indexed, term) {
+			break
+		}
+
+		expanded[indexed] = prefixMatch
+	}
+
+	if _, ok := idx.postings[term]; ok {
+		expanded[term] = exactMatch
+	}
+
+	if typos := maxTypos(term); typos > 0 {
+		for _, indexed := range idx.terms {
+			if _, ok := expanded[indexed]; !ok && typoDistance(term, indexed, typos) <= typos {
+				expanded[indexed] = typoMatch
+			}
+		}
+	}
+
+	return expanded
+}
+
+// maxTypos is how many typos a query term may have, short words have to be spelled right.
+func maxTypos(term string) int {
+	switch length := utf8.RuneCountInString(term); {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	}
+
+	return 0
+}
+
+// typoDistance counts the insertions, deletions, substitutions and swaps of adjacent
+// letters between a and b, giving up with limit+1 once it exceeds limit.
+func typoDistance(a string, b string, limit int) int {
+	ar, br := []rune(a), []rune(b)
+
+	if abs := len(ar) - len(br); abs > limit || -abs > limit {
+		return limit + 1
+	}
+
+	// rows i-2, i-1 and i of the edit distance matrix
+	before, previous, current := make([]int, len(br)+1), make([]int, len(br)+1), make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		rowMin := current[0]
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				current[j] = min(current[j], before[j-2]+1)
+			}
+
+			rowMin = min(rowMin, current[j])
+		}
+
+		if rowMin > limit {
+			return limit + 1
+		}
+
+		before, previous, current = previous, current, before
+	}
+
+	return min(previous[len(br)], limit+1)
+}
+
+// fieldScore weighs how often a book has a term in each field, repeats adding less and less.
+func fieldScore(counts *[3]int) float64 {
+	score := 0.0
+
+	for field, count := range counts {
+		score += searchFieldWeights[field] * float64(count) / float64(count+1)
+	}
+
+	return score
+}
+
+// searchFields are the searched texts of book, by field.
+func searchFields(book *domain.Book) [3]string {
+	description := ""
+	if book.Description != nil {
+		description = *book.Description
+	}
+
+	return [3]string{book.Title, book.Author, description}
+}
+
+// wordSpans returns the byte offsets of the words of text, as searchTerms splits them.
+func wordSpans(text string) [][2]int {
+	spans := [][2]int{}
+	start := -1
+
+	for i, r := range text {
+		switch {
+		case !isNotWordRune(r) && start < 0:
+			start = i
+		case isNotWordRune(r) && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+
+	return spans
+}
+
+// highlight escapes text and wraps the words matched by the search in <mark> tags.
+func highlight(text string, matched map[string]bool) string {
+	var highlighted strings.Builder
+	last := 0
+
+	for _, span := range wordSpans(text) {
+		word := text[span[0]:span[1]]
+		if !matched[strings.ToLower(word)] {
+			continue
+		}
+
+		highlighted.WriteString(html.EscapeString(text[last:span[0]]))
+		highlighted.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		last = span[1]
+	}
+
+	highlighted.WriteString(html.EscapeString(text[last:]))
+
+	return highlighted.String()
+}
+
+// passage highlights the passageWords words of text around its first match, or the start
+// of text when nothing in it matched.
+func passage(text string, matched map[string]bool) string {
+	spans := wordSpans(text)
+	if len(spans) <= passageWords {
+		return highlight(text, matched)
+	}
+
+	first := slices.IndexFunc(spans, func(span [2]int) bool {
+		return matched[strings.ToLower(text[span[0]:span[1]])]
+	})
+
+	// the match is shown with a few words leading up to it
+	start := min(max(first-passageWords/3, 0), len(spans)-passageWords)
+	end := start + passageWords - 1
+
+	return highlight(text[spans[start][0]:spans[end][1]], matched)
+}
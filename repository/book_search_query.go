@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"gin-go-testing/model/domain"
+	"strings"
+)
+
+// The headline options mark matches with control characters rather than HTML, so the rest
+// of the text can be escaped once it is read, see markHighlight.
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+
+	fieldHeadlineOptions   = `HighlightAll=true, StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+	passageHeadlineOptions = `MaxWords=30, MinWords=15, ShortWord=0, StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+)
+
+// searchQuery ranks the matches of the prefix query $1 and adds the trigram similarity of
+// the query words $2, so a book matched through a typo still ranks.
+const searchQuery = `WITH search AS (SELECT to_tsquery('simple', $1) AS query, $2::text AS words) ` +
+	`SELECT ` + bookColumns + `, ts_rank_cd(search_vector, query) + word_similarity(words, search_text) AS score, ` +
+	`ts_headline('simple', title, query, $3), ts_headline('simple', author, query, $3), ts_headline('simple', description, query, $4) ` +
+	`FROM books, search`
+
+const countSearchQuery = `SELECT COUNT(*) FROM books`
+
+// buildSearchConditions requires the book to belong to the tenant and every term to match a
+// word of the book as a prefix, or a word similar enough to it.
+func buildSearchConditions(tenantId string, terms []string, args []any) ([]string, []any) {
+	conditions := make([]string, 0, len(terms)+1)
+
+	args = append(args, tenantId)
+	conditions = append(conditions, fmt.Sprintf("tenant_id=$%d", len(args)))
+
+	for _, term := range terms {
+		args = append(args, term+":*", term)
+		conditions = append(conditions, fmt.Sprintf("(search_vector @@ to_tsquery('simple', $%d) OR $%d <%% search_text)", len(args)-1, len(args)))
+	}
+
+	return conditions, args
+}
+
+// buildSearchQuery builds the search query with its ranking, highlights and paging.
+func buildSearchQuery(tenantId string, terms []string, params *domain.BookSearchParams) (string, []any) {
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+":*")
+	}
+
+	// the ranking and highlights take any of the terms, the conditions require all of them
+	args := []any{strings.Join(prefixes, " | "), strings.Join(terms, " "), fieldHeadlineOptions, passageHeadlineOptions}
+
+	conditions, args := buildSearchConditions(tenantId, terms, args)
+	query := searchQuery + buildWhere(conditions) + " ORDER BY score DESC, id"
+
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args
+}
+
+// buildCountSearchQuery counts the books of the tenant matching every term, ignoring paging.
+func buildCountSearchQuery(tenantId string, terms []string) (string, []any) {
+	conditions, args := buildSearchConditions(tenantId, terms, nil)
+
+	return countSearchQuery + buildWhere(conditions), args
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestBookSearcherSuite struct {
+	suite.Suite
+	s    BookSearcher
+	mock sqlmock.Sqlmock
+	db   *sql.DB
+	ctx  context.Context
+}
+
+func TestUnitTestBookSearcher(t *testing.T) {
+	suite.Run(t, &unitTestBookSearcherSuite{})
+}
+
+func (u *unitTestBookSearcherSuite) SetupTest() {
+	u.s = NewBookSearcherImpl(config.Default())
+
+	u.ctx = context.Background()
+	db, mock, _ := sqlmock.New()
+
+	u.mock = mock
+	u.db = db
+}
+
+func (u *unitTestBookSearcherSuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestBookSearcherSuite) TestSearch_Success() {
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "isbn", "publication_year", "publisher", "language", "page_count", "description", "cover_url", "score", "title", "author", "description"}).
+		AddRow(1, "Dune & Co", "Frank Herbert", nil, nil, nil, nil, nil, nil, nil, 0.9, "\x01Dune\x02 & Co", "Frank \x01Herbert\x02", nil)
+	u.mock.ExpectQuery(`WITH search AS \(SELECT to_tsquery\('simple', \$1\) AS query, \$2::text AS words\) SELECT .+ FROM books, search `+
+		`WHERE tenant_id=\$5 AND \(search_vector @@ to_tsquery\('simple', \$6\) OR \$7 <% search_text\) AND \(search_vector @@ to_tsquery\('simple', \$8\) OR \$9 <% search_text\) `+
+		`ORDER BY score DESC, id LIMIT \$10 OFFSET \$11`).
+		WithArgs("dune:* | herbrt:*", "dune herbrt", fieldHeadlineOptions, passageHeadlineOptions, "default", "dune:*", "dune", "herbrt:*", "herbrt", 10, 20).
+		WillReturnRows(rows)
+	u.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE tenant_id=\$1 AND \(search_vector @@ to_tsquery\('simple', \$2\) OR \$3 <% search_text\) AND .+`).
+		WithArgs("default", "dune:*", "dune", "herbrt:*", "herbrt").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+
+	hits, total, err := u.s.Search(u.ctx, u.db, &domain.BookSearchParams{Query: "Dune, Herbrt dune", Limit: 10, Offset: 20})
+
+	u.Nil(err)
+	u.Equal([]*domain.BookSearchHit{{
+		Book:      &domain.Book{Id: 1, Title: "Dune & Co", Author: "Frank Herbert"},
+		Score:     0.9,
+		Highlight: domain.BookHighlight{Title: "<mark>Dune</mark> &amp; Co", Author: "Frank <mark>Herbert</mark>"},
+	}}, hits)
+	u.Equal(uint(21), total)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestBookSearcherSuite) TestSearch_NoWords() {
+	hits, _, err := u.s.Search(u.ctx, u.db, &domain.BookSearchParams{Query: "&& !"})
+
+	u.Nil(hits)
+	u.Equal(422, err.StatusCode())
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestBookSearcherSuite) TestSearch_Failed() {
+	u.mock.ExpectQuery(`WITH search AS`).WillReturnError(sql.ErrConnDone)
+
+	_, _, err := u.s.Search(u.ctx, u.db, &domain.BookSearchParams{Query: "dune"})
+
+	u.Equal(ErrConnectionLost, kindOf(err))
+}
+
+func (u *unitTestBookSearcherSuite) TestTypoDistance() {
+	tests := []struct {
+		a, b     string
+		limit    int
+		distance int
+	}{
+		{"herbert", "herbert", 1, 0},
+		{"herbrt", "herbert", 1, 1},
+		{"hrebert", "herbert", 1, 1},
+		{"fuondation", "foundation", 2, 1},
+		{"asmiov", "asimov", 1, 1},
+		{"ásimov", "asimov", 1, 1},
+		{"dune", "dunes", 1, 1},
+		{"dune", "emma", 1, 2},
+		{"dune", "messiah", 2, 3},
+	}
+
+	for _, test := range tests {
+		u.Equal(test.distance, typoDistance(test.a, test.b, test.limit), "%s %s", test.a, test.b)
+	}
+}
+
+func (u *unitTestBookSearcherSuite) TestPassage_AroundFirstMatch() {
+	words := strings.Fields(strings.Repeat("sand ", 40) + "spice " + strings.Repeat("sand ", 40))
+	description := strings.Join(words, " ")
+
+	result := passage(description, map[string]bool{"spice": true})
+
+	u.Len(strings.Fields(result), passageWords)
+	u.True(strings.HasPrefix(result, strings.Repeat("sand ", passageWords/3)+"<mark>spice</mark>"))
+	u.Equal(strings.Join(words[:passageWords], " "), passage(description, map[string]bool{}))
+}
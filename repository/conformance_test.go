@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/migration"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseURL returns the disposable Postgres database of the conformance suites, skipping
+// t when there is none.
+func testDatabaseURL(t *testing.T) string {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	return dsn
+}
+
+// openSQLite returns a migrated SQLite database kept in the temporary directory of t.
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "books.db"))
+	require.NoError(t, err)
+
+	migrate(t, db, "sqlite")
+
+	return db
+}
+
+// openPostgres returns the migrated database at dsn with its tables truncated, so every test
+// starts from an empty one.
+func openPostgres(t *testing.T, dsn string) *sql.DB {
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+
+	migrate(t, db, "postgres")
+
+	_, err = db.Exec("TRUNCATE books, book_authors, authors, book_tags, tags, api_keys RESTART IDENTITY")
+	require.NoError(t, err)
+
+	return db
+}
+
+func migrate(t *testing.T, db *sql.DB, driver string) {
+	source, err := migration.Source(driver)
+	require.NoError(t, err)
+
+	migrator, err := migration.New(db, driver, source)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+)
+
+// dialect covers what differs between the SQL databases behind bookRepositoryImpl.
+// Queries are written with Postgres $N placeholders and rebound for the others.
+type dialect struct {
+	name string
+	// numberedPlaceholders rewrites $N into ?N
+	numberedPlaceholders bool
+	// returning tells whether INSERT ... RETURNING can be used to read generated ids
+	returning bool
+}
+
+var (
+	postgresDialect = &dialect{name: "postgres", returning: true}
+	sqliteDialect   = &dialect{name: "sqlite", numberedPlaceholders: true}
+)
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+func (d *dialect) rebind(query string) string {
+	if !d.numberedPlaceholders {
+		return query
+	}
+
+	return placeholderPattern.ReplaceAllString(query, "?$1")
+}
+
+// insert runs an INSERT of a single row and returns its generated id, with RETURNING where
+// the database supports it.
+func (d *dialect) insert(ctx context.Context, db DBTX, query string, args ...any) (uint, error) {
+	if d.returning {
+		var id uint
+		err := db.QueryRowContext(ctx, d.rebind(query+returningIdQuery), args...).Scan(&id)
+
+		return id, err
+	}
+
+	result, err := db.ExecContext(ctx, d.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+
+	return uint(id), err
+}
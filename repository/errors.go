@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"gin-go-testing/logger"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/lib/pq"
+)
+
+// Kinds of repository failures, test them with errors.Is. The service layer decides what
+// each of them means for the client.
+var (
+	ErrNotFound             = errors.New("not found")
+	ErrUniqueViolation      = errors.New("unique violation")
+	ErrForeignKeyViolation  = errors.New("foreign key violation")
+	ErrSerializationFailure = errors.New("serialization failure")
+	ErrTimeout              = errors.New("timeout")
+	ErrConnectionLost       = errors.New("connection lost")
+)
+
+// Error is a failed repository operation. Kind is one of the Err kinds, nil when the failure
+// has no particular meaning, and Err is the driver error. Both are reachable with errors.Is
+// and errors.As.
+type Error struct {
+	Op   string
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	msg := e.Op
+
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	var wrapped []error
+
+	for _, err := range []error{e.Kind, e.Err} {
+		if err != nil {
+			wrapped = append(wrapped, err)
+		}
+	}
+
+	return wrapped
+}
+
+// StatusCode, Status and Message make an Error an errs.CustomError. The service layer
+// translates repository errors, one reaching the client untranslated is a bug, hence internal.
+func (e *Error) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
+func (e *Error) Status() string {
+	return http.StatusText(http.StatusInternalServerError)
+}
+
+func (e *Error) Message() string {
+	return "something went wrong"
+}
+
+func notFound(op string) *Error {
+	return &Error{Op: op, Kind: ErrNotFound}
+}
+
+// newError wraps err, classified from its driver error code, and logs it unless it only
+// tells a row does not exist. Constraint violations are the client's doing, logged at debug.
+func newError(ctx context.Context, op string, err error) *Error {
+	repoErr := &Error{Op: op, Kind: classify(err), Err: err}
+
+	switch repoErr.Kind {
+	case ErrNotFound:
+	case ErrUniqueViolation, ErrForeignKeyViolation:
+		logger.FromContext(ctx).DebugContext(ctx, "constraint violated", "op", op, "kind", repoErr.Kind.Error(), "err", err)
+	case nil:
+		logger.FromContext(ctx).ErrorContext(ctx, "query failed", "op", op, "err", err)
+	default:
+		logger.FromContext(ctx).ErrorContext(ctx, "query failed", "op", op, "kind", repoErr.Kind.Error(), "err", err)
+	}
+
+	return repoErr
+}
+
+// postgresKinds maps Postgres SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+var postgresKinds = map[pq.ErrorCode]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"40001": ErrSerializationFailure,
+	"40P01": ErrSerializationFailure, // deadlock detected
+	"57014": ErrTimeout,              // statement timeout
+	"57P01": ErrConnectionLost,       // admin shutdown
+	"57P02": ErrConnectionLost,       // crash shutdown
+	"57P03": ErrConnectionLost,       // cannot connect now
+}
+
+func classify(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if kind, ok := postgresKinds[pqErr.Code]; ok {
+			return kind
+		}
+
+		// class 08 is connection exception
+		if pqErr.Code.Class() == "08" {
+			return ErrConnectionLost
+		}
+
+		return nil
+	}
+
+	if kind := classifySQLite(err); kind != nil {
+		return kind
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.As(err, &netErr) {
+		return ErrConnectionLost
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build cgo
+
+package repository
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func classifySQLite(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+
+	switch {
+	case sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey:
+		return ErrUniqueViolation
+	case sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey:
+		return ErrForeignKeyViolation
+	// another connection holds the lock the statement needs
+	case sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked:
+		return ErrSerializationFailure
+	}
+
+	return nil
+}
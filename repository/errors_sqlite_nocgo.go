@@ -0,0 +1,8 @@
+//go:build !cgo
+
+package repository
+
+// classifySQLite has nothing to classify, the SQLite driver needs cgo.
+func classifySQLite(err error) error {
+	return nil
+}
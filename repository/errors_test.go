@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestErrorSuite struct {
+	suite.Suite
+}
+
+func TestUnitTestError(t *testing.T) {
+	suite.Run(t, &unitTestErrorSuite{})
+}
+
+func (u *unitTestErrorSuite) TestClassify() {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"no rows", sql.ErrNoRows, ErrNotFound},
+		{"postgres unique", &pq.Error{Code: "23505"}, ErrUniqueViolation},
+		{"postgres foreign key", &pq.Error{Code: "23503"}, ErrForeignKeyViolation},
+		{"postgres serialization", &pq.Error{Code: "40001"}, ErrSerializationFailure},
+		{"postgres deadlock", &pq.Error{Code: "40P01"}, ErrSerializationFailure},
+		{"postgres statement timeout", &pq.Error{Code: "57014"}, ErrTimeout},
+		{"postgres connection failure", &pq.Error{Code: "08006"}, ErrConnectionLost},
+		{"postgres admin shutdown", &pq.Error{Code: "57P01"}, ErrConnectionLost},
+		{"postgres syntax error", &pq.Error{Code: "42601"}, nil},
+		{"sqlite unique", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, ErrUniqueViolation},
+		{"sqlite primary key", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}, ErrUniqueViolation},
+		{"sqlite foreign key", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}, ErrForeignKeyViolation},
+		{"sqlite busy", sqlite3.Error{Code: sqlite3.ErrBusy}, ErrSerializationFailure},
+		{"sqlite not null", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull}, nil},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), ErrTimeout},
+		{"bad connection", driver.ErrBadConn, ErrConnectionLost},
+		{"connection done", sql.ErrConnDone, ErrConnectionLost},
+		{"dial", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrConnectionLost},
+		{"canceled", context.Canceled, nil},
+		{"other", errors.New("some error in db"), nil},
+	}
+
+	for _, test := range tests {
+		u.Run(test.name, func() {
+			u.Equal(test.kind, classify(test.err))
+		})
+	}
+}
+
+func (u *unitTestErrorSuite) TestError_Wraps() {
+	driverErr := &pq.Error{Code: "23505", Message: "duplicate key"}
+
+	err := newError(context.Background(), "CreateBook", driverErr)
+
+	u.Equal("CreateBook: unique violation: pq: duplicate key", err.Error())
+	u.ErrorIs(err, ErrUniqueViolation)
+	u.ErrorIs(err, driverErr)
+	u.NotErrorIs(err, ErrNotFound)
+
+	u.Equal("DeleteBook: not found", notFound("DeleteBook").Error())
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"fmt"
+	"gin-go-testing/model/domain"
+	"strings"
+)
+
+// A tag is only ever nested under a tag of its own tenant, so the queries following parent_id
+// stay within the tenant they start from.
+const (
+	findTagByIdQuery        = `SELECT id, name, parent_id FROM tags WHERE id=$1 AND tenant_id=$2`
+	findTagsByIdsQuery      = `SELECT id, name, parent_id FROM tags WHERE tenant_id=$1 AND id IN (%s)`
+	findTagsByNameKeysQuery = `SELECT id, name, parent_id FROM tags WHERE tenant_id=$1 AND name_key IN (%s)`
+	findAllTagsQuery        = `SELECT id, name, parent_id FROM tags`
+	countTagsQuery          = `SELECT COUNT(*) FROM tags`
+	createTagQuery          = `INSERT INTO tags(name, name_key, parent_id, tenant_id) VALUES($1,$2,$3,$4)`
+	updateTagQuery          = `UPDATE tags SET name=$1, name_key=$2, parent_id=$3 WHERE id=$4 AND tenant_id=$5`
+	deleteTagQuery          = `DELETE FROM tags WHERE id=$1 AND tenant_id=$2`
+	countTagChildrenQuery   = `SELECT COUNT(*) FROM tags WHERE parent_id=$1`
+	// findTagSubtreeQuery walks down from the given tags to every tag nested under them
+	findTagSubtreeQuery = `WITH RECURSIVE subtree(id) AS (SELECT id FROM tags WHERE tenant_id=$1 AND id IN (%s) UNION SELECT tags.id FROM tags JOIN subtree ON tags.parent_id = subtree.id) SELECT id FROM subtree ORDER BY id`
+)
+
+// tagKey is what two spellings of the same tag share, the name ignoring case and differences
+// in whitespace.
+func tagKey(name string) string {
+	return normalize(name)
+}
+
+// buildFindAllTagsQuery builds the tag listing query, ordered by name.
+func buildFindAllTagsQuery(tenantId string, params *domain.TagListParams) (string, []any) {
+	conditions, args := buildTagFilter(tenantId, params)
+	query := findAllTagsQuery + buildWhere(conditions) + " ORDER BY name ASC, id ASC"
+
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args
+}
+
+// buildCountTagsQuery counts the tags matched by the listing filter, ignoring paging.
+func buildCountTagsQuery(tenantId string, params *domain.TagListParams) (string, []any) {
+	conditions, args := buildTagFilter(tenantId, params)
+
+	return countTagsQuery + buildWhere(conditions), args
+}
+
+func buildTagFilter(tenantId string, params *domain.TagListParams) ([]string, []any) {
+	conditions := []string{"tenant_id=$1"}
+	args := []any{tenantId}
+
+	if params.NameContains != "" {
+		args = append(args, "%"+escapeLike(params.NameContains)+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(name) LIKE LOWER($%d) ESCAPE '\'`, len(args)))
+	}
+
+	return conditions, args
+}
+
+// buildInKeysQuery fills the IN list of query with one placeholder per key, the tenant id is
+// bound to $1.
+func buildInKeysQuery(query string, tenantId string, keys []string) (string, []any) {
+	placeholders := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, tenantId)
+
+	for _, key := range keys {
+		args = append(args, key)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	return fmt.Sprintf(query, strings.Join(placeholders, ",")), args
+}
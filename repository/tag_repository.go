@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// TagRepository stores tags. Every method only sees the tags of the tenant carried by ctx,
+// see tenant.FromContext, and a tag can only be nested under a tag of the same tenant.
+type TagRepository interface {
+	Create(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError)
+	FindOneById(ctx context.Context, db DBTX, tagId uint) (*domain.Tag, errs.CustomError)
+	// FindByIds returns the tags with the given ids that exist, in no particular order.
+	FindByIds(ctx context.Context, db DBTX, tagIds []uint) ([]*domain.Tag, errs.CustomError)
+	// FindByNames returns the tags named by names ignoring case, in no particular order.
+	FindByNames(ctx context.Context, db DBTX, names []string) ([]*domain.Tag, errs.CustomError)
+	FindAll(ctx context.Context, db DBTX, params *domain.TagListParams) ([]*domain.Tag, errs.CustomError)
+	Count(ctx context.Context, db DBTX, params *domain.TagListParams) (uint, errs.CustomError)
+	// FindSubtreeIds returns the ids of the given tags and of every tag nested under them.
+	FindSubtreeIds(ctx context.Context, db DBTX, tagIds []uint) ([]uint, errs.CustomError)
+	Update(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError)
+	// Delete fails with ErrForeignKeyViolation while other tags are nested under the tag.
+	Delete(ctx context.Context, db DBTX, tagId uint) errs.CustomError
+}
+
+// NewTagRepository picks the repository matching the configured database driver.
+func NewTagRepository(cfg *config.Config) (TagRepository, error) {
+	switch cfg.Database.Driver {
+	case "postgres":
+		return NewTagRepositoryImpl(cfg), nil
+	case "sqlite":
+		return NewSQLiteTagRepositoryImpl(cfg), nil
+	case "memory":
+		return NewMemoryTagRepositoryImpl(), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+}
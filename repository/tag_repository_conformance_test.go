@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// conformanceTagRepositorySuite runs the same scenarios against every TagRepository
+// implementation, paired with the book repository of the same backend which holds the links.
+type conformanceTagRepositorySuite struct {
+	suite.Suite
+	setup func(cfg *config.Config) (TagRepository, BookRepository, *sql.DB)
+	tr    TagRepository
+	br    BookRepository
+	db    *sql.DB
+	ctx   context.Context
+}
+
+func TestConformanceMemoryTagRepository(t *testing.T) {
+	suite.Run(t, &conformanceTagRepositorySuite{
+		setup: func(cfg *config.Config) (TagRepository, BookRepository, *sql.DB) {
+			return NewMemoryTagRepositoryImpl(), NewMemoryBookRepositoryImpl(cfg), nil
+		},
+	})
+}
+
+func TestConformanceSQLiteTagRepository(t *testing.T) {
+	s := &conformanceTagRepositorySuite{}
+	s.setup = func(cfg *config.Config) (TagRepository, BookRepository, *sql.DB) {
+		db := openSQLite(s.T())
+
+		return NewSQLiteTagRepositoryImpl(cfg), NewSQLiteBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestConformancePostgresTagRepository needs a disposable database, its tables are
+// truncated before every test.
+func TestConformancePostgresTagRepository(t *testing.T) {
+	dsn := testDatabaseURL(t)
+
+	s := &conformanceTagRepositorySuite{}
+	s.setup = func(cfg *config.Config) (TagRepository, BookRepository, *sql.DB) {
+		db := openPostgres(s.T(), dsn)
+
+		return NewTagRepositoryImpl(cfg), NewBookRepositoryImpl(cfg), db
+	}
+
+	suite.Run(t, s)
+}
+
+func (c *conformanceTagRepositorySuite) SetupTest() {
+	c.tr, c.br, c.db = c.setup(config.Default())
+	c.ctx = context.Background()
+}
+
+func (c *conformanceTagRepositorySuite) TearDownTest() {
+	if c.db != nil {
+		c.db.Close()
+	}
+}
+
+// createTag creates the tag named name, nested under parent unless it is nil.
+func (c *conformanceTagRepositorySuite) createTag(name string, parent *domain.Tag) *domain.Tag {
+	tag := &domain.Tag{Name: name}
+	if parent != nil {
+		tag.ParentId = &parent.Id
+	}
+
+	result, err := c.tr.Create(c.ctx, c.db, tag)
+	c.Require().Nil(err)
+
+	return result
+}
+
+func (c *conformanceTagRepositorySuite) createBook(title string, tags ...*domain.Tag) *domain.Book {
+	book, err := c.br.Create(c.ctx, c.db, &domain.Book{Title: title, Author: "Anonymous " + title})
+	c.Require().Nil(err)
+
+	for _, tag := range tags {
+		c.Require().Nil(c.br.AddTag(c.ctx, c.db, book.Id, tag.Id))
+	}
+
+	return book
+}
+
+func (c *conformanceTagRepositorySuite) TestCreate_NamesIgnoreCase() {
+	fiction := c.createTag("Fiction", nil)
+
+	_, err := c.tr.Create(c.ctx, c.db, &domain.Tag{Name: "FICTION"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+
+	found, err := c.tr.FindByNames(c.ctx, c.db, []string{"fiction", "unknown"})
+	c.Nil(err)
+	c.Equal([]*domain.Tag{fiction}, found)
+}
+
+func (c *conformanceTagRepositorySuite) TestFindAll_FilterSortAndPage() {
+	fiction := c.createTag("Fiction", nil)
+	c.createTag("Science Fiction", fiction)
+	c.createTag("Non-fiction", nil)
+	c.createTag("History", nil)
+
+	params := &domain.TagListParams{NameContains: "fic", Limit: 2, Offset: 1}
+
+	result, err := c.tr.FindAll(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal([]string{"Non-fiction", "Science Fiction"}, tagNames(result))
+	c.Equal(&fiction.Id, result[1].ParentId)
+
+	total, err := c.tr.Count(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal(uint(3), total)
+}
+
+func (c *conformanceTagRepositorySuite) TestFindSubtreeIds_NestedLevels() {
+	fiction := c.createTag("Fiction", nil)
+	fantasy := c.createTag("Fantasy", fiction)
+	epic := c.createTag("Epic Fantasy", fantasy)
+	history := c.createTag("History", nil)
+
+	subtree, err := c.tr.FindSubtreeIds(c.ctx, c.db, []uint{fiction.Id})
+	c.Nil(err)
+	c.Equal([]uint{fiction.Id, fantasy.Id, epic.Id}, subtree)
+
+	subtree, err = c.tr.FindSubtreeIds(c.ctx, c.db, []uint{epic.Id, history.Id, 99})
+	c.Nil(err)
+	c.Equal([]uint{epic.Id, history.Id}, subtree)
+}
+
+func (c *conformanceTagRepositorySuite) TestUpdate_MovesTag() {
+	fiction := c.createTag("Fiction", nil)
+	fantasy := c.createTag("Fantasy", nil)
+
+	_, err := c.tr.Update(c.ctx, c.db, &domain.Tag{Id: fantasy.Id, Name: "Fantasy", ParentId: &fiction.Id})
+	c.Nil(err)
+
+	found, err := c.tr.FindOneById(c.ctx, c.db, fantasy.Id)
+	c.Nil(err)
+	c.Equal(&fiction.Id, found.ParentId)
+
+	_, err = c.tr.Update(c.ctx, c.db, &domain.Tag{Id: fantasy.Id, Name: "fiction"})
+	c.Equal(ErrUniqueViolation, kindOf(err))
+}
+
+func (c *conformanceTagRepositorySuite) TestDelete_OnlyLeaves() {
+	fiction := c.createTag("Fiction", nil)
+	fantasy := c.createTag("Fantasy", fiction)
+
+	c.Equal(ErrForeignKeyViolation, kindOf(c.tr.Delete(c.ctx, c.db, fiction.Id)))
+
+	c.Nil(c.tr.Delete(c.ctx, c.db, fantasy.Id))
+	c.Nil(c.tr.Delete(c.ctx, c.db, fiction.Id))
+
+	c.Equal(ErrNotFound, kindOf(c.tr.Delete(c.ctx, c.db, fiction.Id)))
+}
+
+func (c *conformanceTagRepositorySuite) TestBookTags_AddIsIdempotent() {
+	classic := c.createTag("Classic", nil)
+	fantasy := c.createTag("Fantasy", nil)
+	hobbit := c.createBook("The Hobbit", fantasy, classic)
+	untagged := c.createBook("Untagged")
+
+	c.Nil(c.br.AddTag(c.ctx, c.db, hobbit.Id, classic.Id))
+
+	tagIds, err := c.br.FindTagIds(c.ctx, c.db, []uint{hobbit.Id, untagged.Id})
+	c.Nil(err)
+	c.Equal(map[uint][]uint{hobbit.Id: {classic.Id, fantasy.Id}}, tagIds)
+
+	c.Nil(c.br.RemoveTag(c.ctx, c.db, hobbit.Id, classic.Id))
+	c.Nil(c.br.RemoveTag(c.ctx, c.db, hobbit.Id, classic.Id))
+
+	tagIds, err = c.br.FindTagIds(c.ctx, c.db, []uint{hobbit.Id})
+	c.Nil(err)
+	c.Equal(map[uint][]uint{hobbit.Id: {fantasy.Id}}, tagIds)
+}
+
+func (c *conformanceTagRepositorySuite) TestBookTags_FilterAndCount() {
+	classic := c.createTag("Classic", nil)
+	fantasy := c.createTag("Fantasy", nil)
+	scifi := c.createTag("Science Fiction", nil)
+	hobbit := c.createBook("The Hobbit", fantasy, classic)
+	dune := c.createBook("Dune", scifi, classic)
+	c.createBook("Mistborn", fantasy)
+
+	// every group must match: classic and (fantasy or science fiction)
+	params := &domain.BookListParams{TagIds: [][]uint{{classic.Id}, {fantasy.Id, scifi.Id}}}
+
+	books, err := c.br.FindAll(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal([]uint{hobbit.Id, dune.Id}, bookIdsOf(books))
+
+	counts, err := c.br.CountTags(c.ctx, c.db, params)
+	c.Nil(err)
+	c.Equal(map[uint]uint{classic.Id: 2, fantasy.Id: 1, scifi.Id: 1}, counts)
+
+	counts, err = c.br.CountTags(c.ctx, c.db, &domain.BookListParams{})
+	c.Nil(err)
+	c.Equal(map[uint]uint{classic.Id: 2, fantasy.Id: 2, scifi.Id: 1}, counts)
+
+	total, err := c.br.Count(c.ctx, c.db, &domain.BookListParams{TagIds: [][]uint{{}}})
+	c.Nil(err)
+	c.Zero(total)
+}
+
+func (c *conformanceTagRepositorySuite) TestBookTags_GoneWithBookOrTag() {
+	classic := c.createTag("Classic", nil)
+	dune := c.createBook("Dune", classic)
+	hobbit := c.createBook("The Hobbit", classic)
+
+	c.Nil(c.br.Delete(c.ctx, c.db, dune.Id))
+
+	counts, err := c.br.CountTags(c.ctx, c.db, &domain.BookListParams{})
+	c.Nil(err)
+	c.Equal(map[uint]uint{classic.Id: 1}, counts)
+
+	c.Nil(c.tr.Delete(c.ctx, c.db, classic.Id))
+	c.Nil(c.br.RemoveTagFromBooks(c.ctx, c.db, classic.Id))
+
+	tagIds, err := c.br.FindTagIds(c.ctx, c.db, []uint{hobbit.Id})
+	c.Nil(err)
+	c.Empty(tagIds)
+}
+
+func (c *conformanceTagRepositorySuite) TestTenants_Isolated() {
+	fiction := c.createTag("Fiction", nil)
+	fantasy := c.createTag("Fantasy", fiction)
+	hobbit := c.createBook("The Hobbit", fantasy)
+
+	c.ctx = tenant.WithContext(c.ctx, "acme")
+
+	// tag names are only unique within a tenant
+	own := c.createTag("Fiction", nil)
+	book := c.createBook("Dune", own)
+
+	_, err := c.tr.FindOneById(c.ctx, c.db, fiction.Id)
+	c.Equal(ErrNotFound, kindOf(err))
+
+	found, err := c.tr.FindByNames(c.ctx, c.db, []string{"fiction", "fantasy"})
+	c.Nil(err)
+	c.Equal([]*domain.Tag{own}, found)
+
+	subtree, err := c.tr.FindSubtreeIds(c.ctx, c.db, []uint{fiction.Id, own.Id})
+	c.Nil(err)
+	c.Equal([]uint{own.Id}, subtree)
+
+	// the books of another tenant are never tagged
+	c.Nil(c.br.AddTag(c.ctx, c.db, hobbit.Id, own.Id))
+
+	tagIds, err := c.br.FindTagIds(c.ctx, c.db, []uint{book.Id, hobbit.Id})
+	c.Nil(err)
+	c.Equal(map[uint][]uint{book.Id: {own.Id}}, tagIds)
+
+	c.Equal(ErrNotFound, kindOf(c.tr.Delete(c.ctx, c.db, fantasy.Id)))
+}
+
+func tagNames(tags []*domain.Tag) []string {
+	names := []string{}
+
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+func bookIdsOf(books []*domain.Book) []uint {
+	ids := make([]uint, 0, len(books))
+
+	for _, book := range books {
+		ids = append(ids, book.Id)
+	}
+
+	return ids
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type tagRepositoryImpl struct {
+	dialect      *dialect
+	queryTimeout time.Duration
+}
+
+// NewTagRepositoryImpl creates the Postgres repository.
+func NewTagRepositoryImpl(cfg *config.Config) TagRepository {
+	return &tagRepositoryImpl{dialect: postgresDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+// NewSQLiteTagRepositoryImpl creates the SQLite repository.
+func NewSQLiteTagRepositoryImpl(cfg *config.Config) TagRepository {
+	return &tagRepositoryImpl{dialect: sqliteDialect, queryTimeout: cfg.Database.QueryTimeout}
+}
+
+func (t *tagRepositoryImpl) Create(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	id, err := t.dialect.insert(queryCtx, db, createTagQuery, tag.Name, tagKey(tag.Name), tag.ParentId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, newError(ctx, "CreateTag", err)
+	}
+
+	tag.Id = id
+
+	return tag, nil
+}
+
+func (t *tagRepositoryImpl) FindOneById(ctx context.Context, db DBTX, tagId uint) (*domain.Tag, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	tag := new(domain.Tag)
+
+	err := db.QueryRowContext(queryCtx, t.dialect.rebind(findTagByIdQuery), tagId, tenant.FromContext(ctx)).Scan(&tag.Id, &tag.Name, &tag.ParentId)
+	if err != nil {
+		return nil, newError(ctx, "FindOneTagById", err)
+	}
+
+	return tag, nil
+}
+
+func (t *tagRepositoryImpl) FindByIds(ctx context.Context, db DBTX, tagIds []uint) ([]*domain.Tag, errs.CustomError) {
+	if len(tagIds) == 0 {
+		return []*domain.Tag{}, nil
+	}
+
+	query, args := buildInQuery(findTagsByIdsQuery, tenant.FromContext(ctx), tagIds)
+
+	return t.findTags(ctx, db, "FindTagsByIds", query, args)
+}
+
+func (t *tagRepositoryImpl) FindByNames(ctx context.Context, db DBTX, names []string) ([]*domain.Tag, errs.CustomError) {
+	if len(names) == 0 {
+		return []*domain.Tag{}, nil
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, tagKey(name))
+	}
+
+	query, args := buildInKeysQuery(findTagsByNameKeysQuery, tenant.FromContext(ctx), keys)
+
+	return t.findTags(ctx, db, "FindTagsByNames", query, args)
+}
+
+func (t *tagRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.TagListParams) ([]*domain.Tag, errs.CustomError) {
+	query, args := buildFindAllTagsQuery(tenant.FromContext(ctx), params)
+
+	return t.findTags(ctx, db, "FindAllTag", query, args)
+}
+
+func (t *tagRepositoryImpl) findTags(ctx context.Context, db DBTX, op string, query string, args []any) ([]*domain.Tag, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(queryCtx, t.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, op, err)
+	}
+	defer rows.Close()
+
+	tags := []*domain.Tag{}
+
+	for rows.Next() {
+		tag := &domain.Tag{}
+
+		if err := rows.Scan(&tag.Id, &tag.Name, &tag.ParentId); err != nil {
+			return nil, newError(ctx, op, err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, op, err)
+	}
+
+	return tags, nil
+}
+
+func (t *tagRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.TagListParams) (uint, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	var total uint
+
+	query, args := buildCountTagsQuery(tenant.FromContext(ctx), params)
+
+	if err := db.QueryRowContext(queryCtx, t.dialect.rebind(query), args...).Scan(&total); err != nil {
+		return 0, newError(ctx, "CountTag", err)
+	}
+
+	return total, nil
+}
+
+func (t *tagRepositoryImpl) FindSubtreeIds(ctx context.Context, db DBTX, tagIds []uint) ([]uint, errs.CustomError) {
+	if len(tagIds) == 0 {
+		return []uint{}, nil
+	}
+
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	query, args := buildInQuery(findTagSubtreeQuery, tenant.FromContext(ctx), tagIds)
+
+	rows, err := db.QueryContext(queryCtx, t.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, newError(ctx, "FindTagSubtreeIds", err)
+	}
+	defer rows.Close()
+
+	ids := []uint{}
+
+	for rows.Next() {
+		var id uint
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, newError(ctx, "FindTagSubtreeIds", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newError(ctx, "FindTagSubtreeIds", err)
+	}
+
+	return ids, nil
+}
+
+func (t *tagRepositoryImpl) Update(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	result, err := db.ExecContext(queryCtx, t.dialect.rebind(updateTagQuery), tag.Name, tagKey(tag.Name), tag.ParentId, tag.Id, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, newError(ctx, "UpdateTag", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, newError(ctx, "UpdateTag", err)
+	}
+
+	if affected == 0 {
+		return nil, notFound("UpdateTag")
+	}
+
+	return tag, nil
+}
+
+func (t *tagRepositoryImpl) Delete(ctx context.Context, db DBTX, tagId uint) errs.CustomError {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+
+	// SQLite only enforces foreign keys when asked to, so the nested tags are checked here too
+	var children uint
+
+	if err := db.QueryRowContext(queryCtx, t.dialect.rebind(countTagChildrenQuery), tagId).Scan(&children); err != nil {
+		return newError(ctx, "DeleteTag", err)
+	}
+
+	if children > 0 {
+		return &Error{Op: "DeleteTag", Kind: ErrForeignKeyViolation}
+	}
+
+	result, err := db.ExecContext(queryCtx, t.dialect.rebind(deleteTagQuery), tagId, tenant.FromContext(ctx))
+	if err != nil {
+		return newError(ctx, "DeleteTag", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return newError(ctx, "DeleteTag", err)
+	}
+
+	if affected == 0 {
+		return notFound("DeleteTag")
+	}
+
+	return nil
+}
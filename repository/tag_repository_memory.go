@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/tenant"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type memoryTagRepositoryImpl struct {
+	mu     sync.RWMutex
+	tags   map[uint]*domain.Tag
+	lastId uint
+	// tenants holds the tenant of each tag, the in-memory tenant_id column
+	tenants map[uint]string
+	// keys indexes the ids of tags by tenant and tagKey, the in-memory unique index
+	keys map[tenantKey]uint
+}
+
+// NewMemoryTagRepositoryImpl creates a thread-safe repository that keeps tags in memory, the
+// companion of the in-memory book repository. The db argument of its methods is ignored.
+func NewMemoryTagRepositoryImpl() TagRepository {
+	return &memoryTagRepositoryImpl{
+		tags:    map[uint]*domain.Tag{},
+		tenants: map[uint]string{},
+		keys:    map[tenantKey]uint{},
+	}
+}
+
+func (m *memoryTagRepositoryImpl) Create(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenantId := tenant.FromContext(ctx)
+
+	if _, ok := m.keys[tenantKey{tenantId, tagKey(tag.Name)}]; ok {
+		return nil, &Error{Op: "CreateTag", Kind: ErrUniqueViolation}
+	}
+
+	if _, ok := m.parent(ctx, tag); !ok {
+		return nil, &Error{Op: "CreateTag", Kind: ErrForeignKeyViolation}
+	}
+
+	m.lastId++
+	tag.Id = m.lastId
+
+	m.store(tenantId, copyTag(tag))
+
+	return tag, nil
+}
+
+func (m *memoryTagRepositoryImpl) FindOneById(ctx context.Context, db DBTX, tagId uint) (*domain.Tag, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tag, ok := m.owned(ctx, tagId)
+	if !ok {
+		return nil, notFound("FindOneTagById")
+	}
+
+	return copyTag(tag), nil
+}
+
+func (m *memoryTagRepositoryImpl) FindByIds(ctx context.Context, db DBTX, tagIds []uint) ([]*domain.Tag, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tags := []*domain.Tag{}
+
+	for _, id := range tagIds {
+		if tag, ok := m.owned(ctx, id); ok {
+			tags = append(tags, copyTag(tag))
+		}
+	}
+
+	return tags, nil
+}
+
+func (m *memoryTagRepositoryImpl) FindByNames(ctx context.Context, db DBTX, names []string) ([]*domain.Tag, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tags := []*domain.Tag{}
+	seen := map[uint]bool{}
+
+	tenantId := tenant.FromContext(ctx)
+
+	for _, name := range names {
+		if id, ok := m.keys[tenantKey{tenantId, tagKey(name)}]; ok && !seen[id] {
+			seen[id] = true
+			tags = append(tags, copyTag(m.tags[id]))
+		}
+	}
+
+	return tags, nil
+}
+
+func (m *memoryTagRepositoryImpl) FindAll(ctx context.Context, db DBTX, params *domain.TagListParams) ([]*domain.Tag, errs.CustomError) {
+	m.mu.RLock()
+	tags := m.filter(tenant.FromContext(ctx), params)
+	m.mu.RUnlock()
+
+	slices.SortFunc(tags, func(a, b *domain.Tag) int {
+		if order := strings.Compare(a.Name, b.Name); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	tags = tags[min(params.Offset, uint(len(tags))):]
+
+	if params.Limit > 0 {
+		tags = tags[:min(params.Limit, uint(len(tags)))]
+	}
+
+	return tags, nil
+}
+
+func (m *memoryTagRepositoryImpl) Count(ctx context.Context, db DBTX, params *domain.TagListParams) (uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return uint(len(m.filter(tenant.FromContext(ctx), params))), nil
+}
+
+func (m *memoryTagRepositoryImpl) FindSubtreeIds(ctx context.Context, db DBTX, tagIds []uint) ([]uint, errs.CustomError) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subtree := map[uint]bool{}
+	pending := []uint{}
+
+	for _, id := range tagIds {
+		if _, ok := m.owned(ctx, id); ok && !subtree[id] {
+			subtree[id] = true
+			pending = append(pending, id)
+		}
+	}
+
+	for len(pending) > 0 {
+		parentId := pending[0]
+		pending = pending[1:]
+
+		for id, tag := range m.tags {
+			if tag.ParentId != nil && *tag.ParentId == parentId && !subtree[id] {
+				subtree[id] = true
+				pending = append(pending, id)
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(subtree))
+	for id := range subtree {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+func (m *memoryTagRepositoryImpl) Update(ctx context.Context, db DBTX, tag *domain.Tag) (*domain.Tag, errs.CustomError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.owned(ctx, tag.Id); !ok {
+		return nil, notFound("UpdateTag")
+	}
+
+	tenantId := tenant.FromContext(ctx)
+
+	if id, ok := m.keys[tenantKey{tenantId, tagKey(tag.Name)}]; ok && id != tag.Id {
+		return nil, &Error{Op: "UpdateTag", Kind: ErrUniqueViolation}
+	}
+
+	if _, ok := m.parent(ctx, tag); !ok {
+		return nil, &Error{Op: "UpdateTag", Kind: ErrForeignKeyViolation}
+	}
+
+	m.store(tenantId, copyTag(tag))
+
+	return tag, nil
+}
+
+func (m *memoryTagRepositoryImpl) Delete(ctx context.Context, db DBTX, tagId uint) errs.CustomError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.owned(ctx, tagId)
+	if !ok {
+		return notFound("DeleteTag")
+	}
+
+	for _, tag := range m.tags {
+		if tag.ParentId != nil && *tag.ParentId == tagId {
+			return &Error{Op: "DeleteTag", Kind: ErrForeignKeyViolation}
+		}
+	}
+
+	delete(m.keys, tenantKey{m.tenants[tagId], tagKey(stored.Name)})
+	delete(m.tags, tagId)
+	delete(m.tenants, tagId)
+
+	return nil
+}
+
+// owned returns the stored tag with tagId when it belongs to the tenant of ctx, the caller
+// must hold the lock.
+func (m *memoryTagRepositoryImpl) owned(ctx context.Context, tagId uint) (*domain.Tag, bool) {
+	tag, ok := m.tags[tagId]
+
+	return tag, ok && m.tenants[tagId] == tenant.FromContext(ctx)
+}
+
+// parent returns the parent of tag, false when it has one that is missing or belongs to
+// another tenant. The caller must hold the lock.
+func (m *memoryTagRepositoryImpl) parent(ctx context.Context, tag *domain.Tag) (*domain.Tag, bool) {
+	if tag.ParentId == nil {
+		return nil, true
+	}
+
+	return m.owned(ctx, *tag.ParentId)
+}
+
+// store saves tag for the tenant and reindexes its key, the caller must hold the write lock.
+func (m *memoryTagRepositoryImpl) store(tenantId string, tag *domain.Tag) {
+	if previous, ok := m.tags[tag.Id]; ok {
+		delete(m.keys, tenantKey{tenantId, tagKey(previous.Name)})
+	}
+
+	m.keys[tenantKey{tenantId, tagKey(tag.Name)}] = tag.Id
+	m.tags[tag.Id] = tag
+	m.tenants[tag.Id] = tenantId
+}
+
+// filter returns copies of the tags of the tenant matching params, the caller must hold the
+// lock.
+func (m *memoryTagRepositoryImpl) filter(tenantId string, params *domain.TagListParams) []*domain.Tag {
+	tags := []*domain.Tag{}
+	nameContains := strings.ToLower(params.NameContains)
+
+	for _, tag := range m.tags {
+		if m.tenants[tag.Id] != tenantId {
+			continue
+		}
+
+		if nameContains != "" && !strings.Contains(strings.ToLower(tag.Name), nameContains) {
+			continue
+		}
+
+		tags = append(tags, copyTag(tag))
+	}
+
+	return tags
+}
+
+// copyTag copies tag along with its parent id, so callers cannot change the stored one.
+func copyTag(tag *domain.Tag) *domain.Tag {
+	copied := *tag
+
+	if tag.ParentId != nil {
+		parentId := *tag.ParentId
+		copied.ParentId = &parentId
+	}
+
+	return &copied
+}
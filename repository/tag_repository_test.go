@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTagRepositorySuite struct {
+	suite.Suite
+	tr   TagRepository
+	mock sqlmock.Sqlmock
+	db   *sql.DB
+	ctx  context.Context
+}
+
+func TestUnitTestTagRepository(t *testing.T) {
+	suite.Run(t, &unitTestTagRepositorySuite{})
+}
+
+func (u *unitTestTagRepositorySuite) SetupTest() {
+	u.tr = NewTagRepositoryImpl(config.Default())
+
+	u.ctx = context.Background()
+	db, mock, _ := sqlmock.New()
+
+	u.mock = mock
+	u.db = db
+}
+
+func (u *unitTestTagRepositorySuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestTagRepositorySuite) TestCreate_Success() {
+	parentId := uint(1)
+	row := sqlmock.NewRows([]string{"id"}).AddRow(4)
+	u.mock.ExpectQuery(`INSERT INTO tags\(name, name_key, parent_id, tenant_id\) VALUES\(\$1,\$2,\$3,\$4\) RETURNING id`).WithArgs("Science Fiction", "science fiction", 1, "default").WillReturnRows(row)
+
+	result, err := u.tr.Create(u.ctx, u.db, &domain.Tag{Name: "Science Fiction", ParentId: &parentId})
+
+	u.Nil(err)
+	u.Equal(&domain.Tag{Id: 4, Name: "Science Fiction", ParentId: &parentId}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestCreate_UniqueViolation() {
+	u.mock.ExpectQuery(`INSERT INTO tags`).WithArgs("Fiction", "fiction", nil, "default").WillReturnError(&pq.Error{Code: "23505"})
+
+	result, err := u.tr.Create(u.ctx, u.db, &domain.Tag{Name: "Fiction"})
+
+	u.Nil(result)
+	u.Equal(ErrUniqueViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestFindOneById_NotFound() {
+	u.mock.ExpectQuery(`SELECT id, name, parent_id FROM tags WHERE id=\$1 AND tenant_id=\$2`).WithArgs(9, "default").WillReturnError(sql.ErrNoRows)
+
+	result, err := u.tr.FindOneById(u.ctx, u.db, 9)
+
+	u.Nil(result)
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestFindByNames_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "parent_id"}).AddRow(1, "Fiction", nil).AddRow(4, "Science Fiction", 1)
+	u.mock.ExpectQuery(`SELECT id, name, parent_id FROM tags WHERE tenant_id=\$1 AND name_key IN \(\$2,\$3\)`).WithArgs("default", "fiction", "science fiction").WillReturnRows(rows)
+
+	result, err := u.tr.FindByNames(u.ctx, u.db, []string{"FICTION", " science  fiction"})
+
+	parentId := uint(1)
+	u.Nil(err)
+	u.Equal([]*domain.Tag{{Id: 1, Name: "Fiction"}, {Id: 4, Name: "Science Fiction", ParentId: &parentId}}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestFindAll_FilterAndPage() {
+	rows := sqlmock.NewRows([]string{"id", "name", "parent_id"}).AddRow(1, "Fiction", nil)
+	u.mock.ExpectQuery(`SELECT id, name, parent_id FROM tags WHERE tenant_id=\$1 AND LOWER\(name\) LIKE LOWER\(\$2\) ESCAPE '\\' ORDER BY name ASC, id ASC LIMIT \$3 OFFSET \$4`).
+		WithArgs("default", `%fic%`, 10, 20).WillReturnRows(rows)
+
+	result, err := u.tr.FindAll(u.ctx, u.db, &domain.TagListParams{Limit: 10, Offset: 20, NameContains: "fic"})
+
+	u.Nil(err)
+	u.Equal([]*domain.Tag{{Id: 1, Name: "Fiction"}}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestFindSubtreeIds_Success() {
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4).AddRow(6)
+	u.mock.ExpectQuery(`WITH RECURSIVE subtree\(id\) AS \(SELECT id FROM tags WHERE tenant_id=\$1 AND id IN \(\$2\) UNION SELECT tags.id FROM tags JOIN subtree ON tags.parent_id = subtree.id\) SELECT id FROM subtree ORDER BY id`).
+		WithArgs("default", 1).WillReturnRows(rows)
+
+	result, err := u.tr.FindSubtreeIds(u.ctx, u.db, []uint{1})
+
+	u.Nil(err)
+	u.Equal([]uint{1, 4, 6}, result)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestUpdate_NotFound() {
+	u.mock.ExpectExec(`UPDATE tags SET name=\$1, name_key=\$2, parent_id=\$3 WHERE id=\$4 AND tenant_id=\$5`).WithArgs("Fiction", "fiction", nil, 9, "default").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	result, err := u.tr.Update(u.ctx, u.db, &domain.Tag{Id: 9, Name: "Fiction"})
+
+	u.Nil(result)
+	u.Equal(ErrNotFound, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestDelete_Success() {
+	u.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tags WHERE parent_id=\$1`).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	u.mock.ExpectExec(`DELETE FROM tags WHERE id=\$1 AND tenant_id=\$2`).WithArgs(4, "default").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := u.tr.Delete(u.ctx, u.db, 4)
+
+	u.Nil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTagRepositorySuite) TestDelete_HasChildren() {
+	u.mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tags WHERE parent_id=\$1`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	err := u.tr.Delete(u.ctx, u.db, 1)
+
+	u.Equal(ErrForeignKeyViolation, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
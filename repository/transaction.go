@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"gin-go-testing/logger"
+	"time"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// DBTX is what repositories need to run queries, satisfied by both *sql.DB and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type TxManager interface {
+	// WithinTx runs fn in a transaction, committed when fn returns nil and rolled back when
+	// it returns an error or panics. Nil opts use the configured isolation level.
+	WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(tx DBTX) errs.CustomError) errs.CustomError
+}
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read_committed":   sql.LevelReadCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"serializable":     sql.LevelSerializable,
+	"read_uncommitted": sql.LevelReadUncommitted,
+}
+
+type sqlTxManager struct {
+	db        *sql.DB
+	isolation sql.IsolationLevel
+}
+
+// NewTxManager creates a TxManager running transactions on db.
+func NewTxManager(db *sql.DB, cfg *config.Config) TxManager {
+	return &sqlTxManager{db: db, isolation: isolationLevels[cfg.Database.TxIsolation]}
+}
+
+func (s *sqlTxManager) WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(tx DBTX) errs.CustomError) errs.CustomError {
+	if opts == nil {
+		opts = &sql.TxOptions{Isolation: s.isolation}
+	}
+
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return newError(ctx, "BeginTx", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(ctx, tx)
+			panic(p)
+		}
+	}()
+
+	if errTx := fn(tx); errTx != nil {
+		rollback(ctx, tx)
+		return errTx
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newError(ctx, "CommitTx", err)
+	}
+
+	return nil
+}
+
+// withTimeout bounds ctx by timeout, when it is positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "rollback transaction failed", "err", err)
+	}
+}
+
+type memoryTxManager struct{}
+
+// NewMemoryTxManager creates the TxManager paired with the in-memory repository. It simply
+// calls fn, every repository call is atomic on its own but there is no rollback.
+func NewMemoryTxManager() TxManager {
+	return memoryTxManager{}
+}
+
+func (memoryTxManager) WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(tx DBTX) errs.CustomError) errs.CustomError {
+	return fn(nil)
+}
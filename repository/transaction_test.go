@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/config"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/rulyadhika/go-custom-err/errs"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTxManagerSuite struct {
+	suite.Suite
+	tm   TxManager
+	mock sqlmock.Sqlmock
+	db   *sql.DB
+	ctx  context.Context
+}
+
+func TestUnitTestTxManager(t *testing.T) {
+	suite.Run(t, &unitTestTxManagerSuite{})
+}
+
+func (u *unitTestTxManagerSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+
+	u.db = db
+	u.mock = mock
+	u.tm = NewTxManager(db, config.Default())
+	u.ctx = context.Background()
+}
+
+func (u *unitTestTxManagerSuite) TearDownTest() {
+	u.db.Close()
+}
+
+func (u *unitTestTxManagerSuite) TestWithinTx_Commit() {
+	u.mock.ExpectBegin()
+	u.mock.ExpectExec(`DELETE FROM books WHERE id=\$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	u.mock.ExpectCommit()
+
+	err := u.tm.WithinTx(u.ctx, nil, func(tx DBTX) errs.CustomError {
+		_, errExec := tx.ExecContext(u.ctx, deleteQuery, 1)
+		u.NoError(errExec)
+
+		return nil
+	})
+
+	u.Nil(err)
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTxManagerSuite) TestWithinTx_RollbackOnError() {
+	u.mock.ExpectBegin()
+	u.mock.ExpectRollback()
+
+	err := u.tm.WithinTx(u.ctx, nil, func(tx DBTX) errs.CustomError {
+		return errs.NewNotFoundError("data not found")
+	})
+
+	u.Equal(http.StatusNotFound, err.StatusCode())
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTxManagerSuite) TestWithinTx_RollbackOnPanic() {
+	u.mock.ExpectBegin()
+	u.mock.ExpectRollback()
+
+	u.PanicsWithValue("boom", func() {
+		u.tm.WithinTx(u.ctx, nil, func(tx DBTX) errs.CustomError {
+			panic("boom")
+		})
+	})
+
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTxManagerSuite) TestWithinTx_BeginFailed() {
+	u.mock.ExpectBegin().WillReturnError(&pq.Error{Code: "08006"})
+
+	called := false
+	err := u.tm.WithinTx(u.ctx, nil, func(tx DBTX) errs.CustomError {
+		called = true
+		return nil
+	})
+
+	u.False(called)
+	u.Equal(ErrConnectionLost, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
+
+func (u *unitTestTxManagerSuite) TestWithinTx_CommitFailed() {
+	u.mock.ExpectBegin()
+	u.mock.ExpectCommit().WillReturnError(&pq.Error{Code: "40001"})
+
+	err := u.tm.WithinTx(u.ctx, nil, func(tx DBTX) errs.CustomError {
+		return nil
+	})
+
+	u.Equal(ErrSerializationFailure, kindOf(err))
+	u.NoError(u.mock.ExpectationsWereMet())
+}
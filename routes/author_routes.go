@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"gin-go-testing/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewAuthorRoutes(r *gin.RouterGroup, ah handler.AuthorHandler) {
+	authors := r.Group("/authors")
+
+	authors.POST("", ah.Create)
+	authors.GET("", ah.FindAll)
+	authors.GET("/:authorId", ah.FindOneById)
+	authors.PUT("/:authorId", ah.Update)
+	authors.DELETE("/:authorId", ah.Delete)
+}
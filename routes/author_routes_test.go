@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"gin-go-testing/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAuthorRoutesSuite struct {
+	suite.Suite
+	ahm    *mocks.AuthorHandler
+	router *gin.Engine
+}
+
+func TestUnitTestAuthorRoutes(t *testing.T) {
+	suite.Run(t, &unitTestAuthorRoutesSuite{})
+}
+
+func (u *unitTestAuthorRoutesSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.ahm = mocks.NewAuthorHandler(u.T())
+
+	u.router = gin.New()
+	NewAuthorRoutes(&u.router.RouterGroup, u.ahm)
+}
+
+func (u *unitTestAuthorRoutesSuite) TestRoutes_Registered() {
+	tests := []struct {
+		method  string
+		target  string
+		handler string
+	}{
+		{http.MethodPost, "/authors", "Create"},
+		{http.MethodGet, "/authors", "FindAll"},
+		{http.MethodGet, "/authors/1", "FindOneById"},
+		{http.MethodPut, "/authors/1", "Update"},
+		{http.MethodDelete, "/authors/1", "Delete"},
+	}
+
+	for _, test := range tests {
+		u.ahm.On(test.handler, mock.Anything).Return().Once()
+
+		writer := httptest.NewRecorder()
+		u.router.ServeHTTP(writer, httptest.NewRequest(test.method, test.target, nil))
+	}
+
+	u.ahm.AssertExpectations(u.T())
+}
+
+func (u *unitTestAuthorRoutesSuite) TestFindOneById_PassesParam() {
+	u.ahm.On("FindOneById", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("authorId") == "7"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/authors/7", nil))
+
+	u.ahm.AssertExpectations(u.T())
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"gin-go-testing/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewBookRoutes(r *gin.RouterGroup, bh handler.BookHandler) {
+	books := r.Group("/books")
+
+	books.POST("", bh.Create)
+	books.GET("", bh.FindAll)
+	books.GET("/search", bh.Search)
+	books.GET("/:bookId", bh.FindOneById)
+	books.PUT("/:bookId", bh.Update)
+	books.PATCH("/:bookId", bh.Patch)
+	books.DELETE("/:bookId", bh.Delete)
+	books.PUT("/:bookId/tags/:tagId", bh.AddTag)
+	books.DELETE("/:bookId/tags/:tagId", bh.RemoveTag)
+}
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"gin-go-testing/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestBookRoutesSuite struct {
+	suite.Suite
+	bhm    *mocks.BookHandler
+	router *gin.Engine
+}
+
+func TestUnitTestBookRoutes(t *testing.T) {
+	suite.Run(t, &unitTestBookRoutesSuite{})
+}
+
+func (u *unitTestBookRoutesSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.bhm = mocks.NewBookHandler(u.T())
+
+	u.router = gin.New()
+	NewBookRoutes(&u.router.RouterGroup, u.bhm)
+}
+
+func (u *unitTestBookRoutesSuite) TestCreate_Registered() {
+	u.bhm.On("Create", mock.Anything).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestFindAll_Registered() {
+	u.bhm.On("FindAll", mock.Anything).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+// TestSearch_Registered makes sure /books/search is not taken for a book id.
+func (u *unitTestBookRoutesSuite) TestSearch_Registered() {
+	u.bhm.On("Search", mock.Anything).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/books/search?q=dune", nil))
+
+	u.bhm.AssertExpectations(u.T())
+	u.bhm.AssertNotCalled(u.T(), "FindOneById", mock.Anything)
+}
+
+func (u *unitTestBookRoutesSuite) TestFindOneById_Registered() {
+	u.bhm.On("FindOneById", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/books/1", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestUpdate_Registered() {
+	u.bhm.On("Update", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodPut, "/books/1", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestPatch_Registered() {
+	u.bhm.On("Patch", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodPatch, "/books/1", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestDelete_Registered() {
+	u.bhm.On("Delete", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodDelete, "/books/1", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestUnknownRoute_NotFound() {
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	u.Equal(http.StatusNotFound, writer.Code)
+}
+
+func (u *unitTestBookRoutesSuite) TestAddTag_Registered() {
+	u.bhm.On("AddTag", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1" && ctx.Param("tagId") == "9"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodPut, "/books/1/tags/9", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookRoutesSuite) TestRemoveTag_Registered() {
+	u.bhm.On("RemoveTag", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("bookId") == "1" && ctx.Param("tagId") == "9"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodDelete, "/books/1/tags/9", nil))
+
+	u.bhm.AssertExpectations(u.T())
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"encoding/json"
+	"gin-go-testing/auth"
+	"gin-go-testing/auth/authtest"
+	"gin-go-testing/config"
+	"gin-go-testing/handler"
+	"gin-go-testing/middleware"
+	"gin-go-testing/repository"
+	"gin-go-testing/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// authorizationTestBooksSuite serves the book endpoints from the real services on the
+// in-memory repositories, running every request as a principal with the roles of the test.
+type authorizationTestBooksSuite struct {
+	suite.Suite
+	bh handler.BookHandler
+}
+
+func TestAuthorizationTestBooks(t *testing.T) {
+	suite.Run(t, &authorizationTestBooksSuite{})
+}
+
+func (a *authorizationTestBooksSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+
+	policy, err := auth.NewPolicyImpl(cfg)
+	a.Require().NoError(err)
+
+	bs := service.NewBookServiceImpl(
+		repository.NewMemoryBookRepositoryImpl(cfg),
+		repository.NewMemoryAuthorRepositoryImpl(),
+		repository.NewMemoryTagRepositoryImpl(),
+		repository.NewMemoryBookSearcherImpl(),
+		nil,
+		repository.NewMemoryTxManager(),
+		policy,
+		cfg,
+	)
+
+	a.bh = handler.NewBookHandlerImpl(bs, cfg)
+}
+
+// serveAs sends a request to the book routes as a principal with roles.
+func (a *authorizationTestBooksSuite) serveAs(roles []string, method string, target string, body string) (int, map[string]any) {
+	router := gin.New()
+	router.Use(middleware.Errors(), authtest.AsRoles(roles...))
+	NewBookRoutes(&router.RouterGroup, a.bh)
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, request)
+
+	var response map[string]any
+	if writer.Body.Len() > 0 {
+		a.Require().NoError(json.Unmarshal(writer.Body.Bytes(), &response))
+	}
+
+	return writer.Code, response
+}
+
+func (a *authorizationTestBooksSuite) TestPermissionsOfRoles() {
+	admin := []string{"admin"}
+	editor := []string{"editor"}
+	reader := []string{"reader"}
+
+	status, _ := a.serveAs(editor, http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`)
+	a.Require().Equal(http.StatusCreated, status)
+
+	tests := []struct {
+		roles  []string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{reader, http.MethodGet, "/books", "", http.StatusOK},
+		{reader, http.MethodGet, "/books/1", "", http.StatusOK},
+		{reader, http.MethodGet, "/books/search?q=dune", "", http.StatusOK},
+		{reader, http.MethodPost, "/books", `{"title":"Emma","author":"Jane Austen"}`, http.StatusForbidden},
+		{reader, http.MethodPut, "/books/1", `{"title":"Dune","author":"Frank Herbert"}`, http.StatusForbidden},
+		{editor, http.MethodPut, "/books/1", `{"title":"Dune","author":"Frank Herbert"}`, http.StatusOK},
+		{editor, http.MethodDelete, "/books/1", "", http.StatusForbidden},
+		{[]string{"unknown"}, http.MethodGet, "/books", "", http.StatusForbidden},
+		{admin, http.MethodDelete, "/books/1", "", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		status, response := a.serveAs(test.roles, test.method, test.target, test.body)
+
+		a.Equal(test.status, status, "%v %s %s", test.roles, test.method, test.target)
+
+		if test.status == http.StatusForbidden {
+			a.Equal("forbidden", response["code"])
+		}
+	}
+}
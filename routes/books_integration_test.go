@@ -0,0 +1,276 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/handler"
+	"gin-go-testing/migration"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/ratelimit"
+	"gin-go-testing/repository"
+	"gin-go-testing/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+// integrationTestBooksSuite serves the book endpoints from the real repositories and
+// services on top of a migrated database.
+type integrationTestBooksSuite struct {
+	suite.Suite
+	driver string
+	// open returns a migrated database without any rows
+	open   func() *sql.DB
+	db     *sql.DB
+	router *gin.Engine
+	// apiKey authenticates the requests of serve, as an admin
+	apiKey    string
+	readerKey string
+}
+
+func TestIntegrationTestSQLiteBooks(t *testing.T) {
+	s := &integrationTestBooksSuite{driver: "sqlite"}
+	s.open = func() *sql.DB {
+		db, err := sql.Open("sqlite3", filepath.Join(s.T().TempDir(), "books.db"))
+		s.Require().NoError(err)
+
+		s.migrate(db)
+
+		return db
+	}
+
+	suite.Run(t, s)
+}
+
+// TestIntegrationTestPostgresBooks needs a disposable database, its tables are truncated
+// before every test.
+func TestIntegrationTestPostgresBooks(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	s := &integrationTestBooksSuite{driver: "postgres"}
+	s.open = func() *sql.DB {
+		db, err := sql.Open("postgres", dsn)
+		s.Require().NoError(err)
+
+		s.migrate(db)
+
+		_, err = db.Exec("TRUNCATE books, book_authors, authors, book_tags, tags, api_keys RESTART IDENTITY")
+		s.Require().NoError(err)
+
+		return db
+	}
+
+	suite.Run(t, s)
+}
+
+func (i *integrationTestBooksSuite) migrate(db *sql.DB) {
+	source, err := migration.Source(i.driver)
+	i.Require().NoError(err)
+
+	migrator, err := migration.New(db, i.driver, source)
+	i.Require().NoError(err)
+	i.Require().NoError(migrator.Up(context.Background()))
+}
+
+func (i *integrationTestBooksSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Database.Driver = i.driver
+
+	i.db = i.open()
+
+	br, err := repository.NewBookRepository(cfg)
+	i.Require().NoError(err)
+	ar, err := repository.NewAuthorRepository(cfg)
+	i.Require().NoError(err)
+	tr, err := repository.NewTagRepository(cfg)
+	i.Require().NoError(err)
+	s, err := repository.NewBookSearcher(cfg)
+	i.Require().NoError(err)
+
+	tm := repository.NewTxManager(i.db, cfg)
+
+	akr, err := repository.NewApiKeyRepository(cfg)
+	i.Require().NoError(err)
+
+	i.apiKey = i.createApiKey(akr, "admin")
+	i.readerKey = i.createApiKey(akr, "reader")
+
+	policy, err := auth.NewPolicyImpl(cfg)
+	i.Require().NoError(err)
+
+	i.router = NewRouter(
+		handler.NewBookHandlerImpl(service.NewBookServiceImpl(br, ar, tr, s, i.db, tm, policy, cfg), cfg),
+		handler.NewAuthorHandlerImpl(service.NewAuthorServiceImpl(ar, br, s, i.db, tm, policy, cfg), cfg),
+		handler.NewTagHandlerImpl(service.NewTagServiceImpl(tr, br, i.db, tm, policy, cfg), cfg),
+		auth.NewAuthenticatorImpl(akr, i.db, nil, cfg),
+		ratelimit.NewMemoryStoreImpl(),
+		cfg,
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+}
+
+// createApiKey stores a new API key with roles and returns it.
+func (i *integrationTestBooksSuite) createApiKey(akr repository.ApiKeyRepository, roles ...string) string {
+	key, prefix, hash, err := auth.GenerateApiKey()
+	i.Require().NoError(err)
+
+	_, errCreate := akr.Create(context.Background(), i.db, &domain.ApiKey{Name: "integration", Prefix: prefix, Hash: hash, Roles: roles, CreatedAt: time.Now()})
+	i.Require().Nil(errCreate)
+
+	return key
+}
+
+func (i *integrationTestBooksSuite) TearDownTest() {
+	i.db.Close()
+}
+
+// serve sends a request to the router and decodes the envelope it answers with.
+func (i *integrationTestBooksSuite) serve(method string, target string, body string) (int, map[string]any) {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Api-Key", i.apiKey)
+
+	writer := httptest.NewRecorder()
+	i.router.ServeHTTP(writer, request)
+
+	var response map[string]any
+	i.Require().NoError(json.Unmarshal(writer.Body.Bytes(), &response))
+
+	return writer.Code, response
+}
+
+func (i *integrationTestBooksSuite) TestFindAll_EmptyCatalogue() {
+	status, response := i.serve(http.MethodGet, "/books", "")
+
+	i.Equal(http.StatusOK, status)
+	i.Equal([]any{}, response["data"])
+	i.Equal(map[string]any{
+		"page":        float64(1),
+		"page_size":   float64(20),
+		"total_count": float64(0),
+		"total_pages": float64(0),
+		"links":       map[string]any{},
+		"facets":      map[string]any{"tags": []any{}},
+	}, response["meta"])
+
+	status, response = i.serve(http.MethodGet, "/books?cursor=", "")
+
+	i.Equal(http.StatusOK, status)
+	i.Equal([]any{}, response["data"])
+}
+
+func (i *integrationTestBooksSuite) TestFindAll_ListsCreatedBooks() {
+	for _, title := range []string{"Emma", "Dune"} {
+		status, _ := i.serve(http.MethodPost, "/books", `{"title":"`+title+`","author":"Anonymous"}`)
+		i.Require().Equal(http.StatusCreated, status)
+	}
+
+	status, response := i.serve(http.MethodGet, "/books?sort=title", "")
+
+	i.Equal(http.StatusOK, status)
+	i.Len(response["data"], 2)
+	i.Equal("Dune", response["data"].([]any)[0].(map[string]any)["title"])
+
+	// a filter matching nothing and a page past the end are empty listings, not missing ones
+	for _, target := range []string{"/books?title_contains=habits", "/books?page=3"} {
+		status, response = i.serve(http.MethodGet, target, "")
+
+		i.Equal(http.StatusOK, status, target)
+		i.Equal([]any{}, response["data"], target)
+	}
+}
+
+func (i *integrationTestBooksSuite) TestFindAll_UnknownSortField() {
+	status, response := i.serve(http.MethodGet, "/books?sort=price", "")
+
+	i.Equal(http.StatusBadRequest, status)
+	i.Equal("invalid_sort", response["code"])
+}
+
+func (i *integrationTestBooksSuite) TestCreate_RequiresCredentials() {
+	for _, apiKey := range []string{"", "bk_unknown", i.apiKey + "x"} {
+		i.apiKey = apiKey
+
+		status, response := i.serve(http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`)
+
+		i.Equal(http.StatusUnauthorized, status, apiKey)
+		i.Contains([]any{"unauthorized", "invalid_credentials"}, response["code"], apiKey)
+	}
+}
+
+func (i *integrationTestBooksSuite) TestCreate_ReaderForbidden() {
+	i.apiKey = i.readerKey
+
+	status, response := i.serve(http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`)
+
+	i.Equal(http.StatusForbidden, status)
+	i.Equal("forbidden", response["code"])
+	i.Equal("books:write", response["permission"])
+
+	status, _ = i.serve(http.MethodGet, "/books", "")
+
+	i.Equal(http.StatusOK, status)
+}
+
+func (i *integrationTestBooksSuite) TestAuthors_ReaderForbidden() {
+	status, _ := i.serve(http.MethodPost, "/authors", `{"name":"Frank Herbert"}`)
+	i.Require().Equal(http.StatusCreated, status)
+
+	i.apiKey = i.readerKey
+
+	status, response := i.serve(http.MethodPut, "/authors/1", `{"name":"Brian Herbert"}`)
+
+	i.Equal(http.StatusForbidden, status)
+	i.Equal("books:write", response["permission"])
+
+	status, response = i.serve(http.MethodDelete, "/authors/1", "")
+
+	i.Equal(http.StatusForbidden, status)
+	i.Equal("books:delete", response["permission"])
+
+	status, response = i.serve(http.MethodGet, "/authors/1", "")
+
+	i.Equal(http.StatusOK, status)
+	i.Equal("Frank Herbert", response["data"].(map[string]any)["name"])
+}
+
+func (i *integrationTestBooksSuite) TestTags_ReaderForbidden() {
+	status, _ := i.serve(http.MethodPost, "/tags", `{"name":"Classic"}`)
+	i.Require().Equal(http.StatusCreated, status)
+
+	i.apiKey = i.readerKey
+
+	status, response := i.serve(http.MethodPost, "/tags", `{"name":"Fiction"}`)
+
+	i.Equal(http.StatusForbidden, status)
+	i.Equal("books:write", response["permission"])
+
+	status, response = i.serve(http.MethodDelete, "/tags/1", "")
+
+	i.Equal(http.StatusForbidden, status)
+	i.Equal("books:delete", response["permission"])
+
+	status, response = i.serve(http.MethodGet, "/tags/1", "")
+
+	i.Equal(http.StatusOK, status)
+	i.Equal("Classic", response["data"].(map[string]any)["name"])
+}
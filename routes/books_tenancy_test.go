@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"context"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/handler"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/ratelimit"
+	"gin-go-testing/repository"
+	"gin-go-testing/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// tenancyTestBooksSuite serves the endpoints from the real services on the in-memory
+// repositories, taking the tenant of every request from its X-Tenant-Id header. Requests are
+// authenticated with API keys bound to the tenants.
+type tenancyTestBooksSuite struct {
+	suite.Suite
+	router *gin.Engine
+	// keys are the admin API keys of each tenant, the one of "" is bound to none
+	keys map[string]string
+}
+
+func TestTenancyTestBooks(t *testing.T) {
+	suite.Run(t, &tenancyTestBooksSuite{})
+}
+
+func (s *tenancyTestBooksSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Tenancy.Source = "header"
+
+	policy, err := auth.NewPolicyImpl(cfg)
+	s.Require().NoError(err)
+
+	br := repository.NewMemoryBookRepositoryImpl(cfg)
+	ar := repository.NewMemoryAuthorRepositoryImpl()
+	tr := repository.NewMemoryTagRepositoryImpl()
+	sr := repository.NewMemoryBookSearcherImpl()
+	tm := repository.NewMemoryTxManager()
+	akr := repository.NewMemoryApiKeyRepositoryImpl()
+
+	s.keys = map[string]string{}
+	for _, tenantId := range []string{"acme", "globex", ""} {
+		key, prefix, hash, err := auth.GenerateApiKey()
+		s.Require().NoError(err)
+
+		_, errCreate := akr.Create(context.Background(), nil, &domain.ApiKey{Name: "tenancy", Prefix: prefix, Hash: hash, Roles: []string{"admin"}, Tenant: tenantId, CreatedAt: time.Now()})
+		s.Require().Nil(errCreate)
+
+		s.keys[tenantId] = key
+	}
+
+	s.router = NewRouter(
+		handler.NewBookHandlerImpl(service.NewBookServiceImpl(br, ar, tr, sr, nil, tm, policy, cfg), cfg),
+		handler.NewAuthorHandlerImpl(service.NewAuthorServiceImpl(ar, br, sr, nil, tm, policy, cfg), cfg),
+		handler.NewTagHandlerImpl(service.NewTagServiceImpl(tr, br, nil, tm, policy, cfg), cfg),
+		auth.NewAuthenticatorImpl(akr, nil, nil, cfg),
+		ratelimit.NewMemoryStoreImpl(),
+		cfg,
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+}
+
+// serve sends a request naming tenantId with the API key of keyTenant.
+func (s *tenancyTestBooksSuite) serve(keyTenant string, tenantId string, method string, target string, body string) int {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Api-Key", s.keys[keyTenant])
+	request.Header.Set("X-Tenant-Id", tenantId)
+
+	writer := httptest.NewRecorder()
+	s.router.ServeHTTP(writer, request)
+
+	return writer.Code
+}
+
+// serveFor sends a request on behalf of tenantId, with its own API key.
+func (s *tenancyTestBooksSuite) serveFor(tenantId string, method string, target string, body string) int {
+	return s.serve(tenantId, tenantId, method, target, body)
+}
+
+func (s *tenancyTestBooksSuite) TestBooksOfAnotherTenant() {
+	s.Require().Equal(http.StatusCreated, s.serveFor("acme", http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`))
+
+	s.Equal(http.StatusOK, s.serveFor("acme", http.MethodGet, "/books/1", ""))
+
+	s.Equal(http.StatusNotFound, s.serveFor("globex", http.MethodGet, "/books/1", ""))
+	s.Equal(http.StatusNotFound, s.serveFor("globex", http.MethodPut, "/books/1", `{"title":"Emma","author":"Jane Austen"}`))
+	s.Equal(http.StatusNotFound, s.serveFor("globex", http.MethodDelete, "/books/1", ""))
+
+	// the same book is no duplicate in another tenant
+	s.Equal(http.StatusCreated, s.serveFor("globex", http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`))
+
+	s.Equal(http.StatusBadRequest, s.serve("acme", "", http.MethodGet, "/books", ""))
+}
+
+func (s *tenancyTestBooksSuite) TestKeyOfAnotherTenant() {
+	s.Require().Equal(http.StatusCreated, s.serveFor("globex", http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert"}`))
+
+	// the key of acme cannot reach the catalogue of globex by naming it
+	s.Equal(http.StatusForbidden, s.serve("acme", "globex", http.MethodGet, "/books/1", ""))
+	s.Equal(http.StatusForbidden, s.serve("acme", "globex", http.MethodDelete, "/books/1", ""))
+	s.Equal(http.StatusForbidden, s.serve("acme", "globex", http.MethodPost, "/tags", `{"name":"Classic"}`))
+
+	s.Equal(http.StatusOK, s.serveFor("globex", http.MethodGet, "/books/1", ""))
+}
+
+func (s *tenancyTestBooksSuite) TestUnboundKey() {
+	s.Equal(http.StatusForbidden, s.serve("", "acme", http.MethodGet, "/books", ""))
+}
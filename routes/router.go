@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/handler"
+	"gin-go-testing/middleware"
+	"gin-go-testing/ratelimit"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewRouter(bh handler.BookHandler, ah handler.AuthorHandler, th handler.TagHandler, a auth.Authenticator, rs ratelimit.Store, cfg *config.Config, l *slog.Logger) *gin.Engine {
+	router := gin.New()
+	// the proxies were validated with the config
+	_ = router.SetTrustedProxies(cfg.App.TrustedProxies)
+
+	router.Use(middleware.RequestId(), middleware.Logger(l), middleware.Errors(), middleware.RateLimitRejected(rs, &cfg.RateLimit), middleware.Authenticate(a, &cfg.Auth), middleware.RateLimit(rs, &cfg.RateLimit), middleware.Tenant(&cfg.Tenancy))
+	router.NoRoute(middleware.NoRoute())
+
+	NewBookRoutes(&router.RouterGroup, bh)
+	NewAuthorRoutes(&router.RouterGroup, ah)
+	NewTagRoutes(&router.RouterGroup, th)
+
+	return router
+}
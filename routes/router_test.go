@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"encoding/json"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/ratelimit"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestRouterSuite struct {
+	suite.Suite
+	bhm    *mocks.BookHandler
+	am     *mocks.Authenticator
+	cfg    *config.Config
+	router *gin.Engine
+}
+
+func TestUnitTestRouter(t *testing.T) {
+	suite.Run(t, &unitTestRouterSuite{})
+}
+
+func (u *unitTestRouterSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.bhm = mocks.NewBookHandler(u.T())
+	u.am = mocks.NewAuthenticator(u.T())
+	u.cfg = config.Default()
+	u.cfg.Auth.AllowAnonymous = true
+	u.router = NewRouter(u.bhm, mocks.NewAuthorHandler(u.T()), mocks.NewTagHandler(u.T()), u.am, ratelimit.NewMemoryStoreImpl(), u.cfg, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func (u *unitTestRouterSuite) TestUnknownRoute_Problem() {
+	request := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	request.Header.Set("X-Request-Id", "req-1")
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(writer.Body.Bytes(), &problem))
+
+	u.Equal(http.StatusNotFound, writer.Code)
+	u.Equal("application/problem+json", writer.Header().Get("Content-Type"))
+	u.Equal(dto.ProblemDetails{
+		Type:      "urn:problem-type:route_not_found",
+		Title:     http.StatusText(http.StatusNotFound),
+		Status:    http.StatusNotFound,
+		Detail:    "route not found",
+		Instance:  "/unknown",
+		Code:      "route_not_found",
+		RequestId: "req-1",
+	}, problem)
+}
+
+func (u *unitTestRouterSuite) TestPanic_Problem() {
+	u.bhm.On("FindAll", mock.Anything).Run(func(args mock.Arguments) {
+		panic("boom")
+	}).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(writer.Body.Bytes(), &problem))
+
+	u.Equal(http.StatusInternalServerError, writer.Code)
+	u.Equal("internal_error", problem.Code)
+	u.NotEmpty(problem.RequestId)
+}
+
+func (u *unitTestRouterSuite) TestAnonymous_Refused() {
+	u.cfg.Auth.AllowAnonymous = false
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	var problem dto.ProblemDetails
+	u.NoError(json.Unmarshal(writer.Body.Bytes(), &problem))
+
+	u.Equal(http.StatusUnauthorized, writer.Code)
+	u.Equal("unauthorized", problem.Code)
+	u.bhm.AssertNotCalled(u.T(), "Create", mock.Anything)
+}
+
+func (u *unitTestRouterSuite) TestApiKey_ReachesHandler() {
+	principal := &domain.Principal{Subject: "api_key:1", Method: domain.AuthMethodApiKey}
+	u.am.On("ApiKey", mock.Anything, "bk_secret").Return(principal, nil)
+	u.bhm.On("Create", mock.MatchedBy(func(ctx *gin.Context) bool {
+		found, ok := auth.PrincipalFrom(ctx.Request.Context())
+		return ok && found == principal
+	})).Return()
+
+	request := httptest.NewRequest(http.MethodPost, "/books", nil)
+	request.Header.Set("X-Api-Key", "bk_secret")
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, request)
+
+	u.bhm.AssertExpectations(u.T())
+}
+
+func (u *unitTestRouterSuite) TestBadApiKey_RateLimited() {
+	u.cfg.RateLimit.Default = config.RateLimit{Requests: 3, Period: time.Minute}
+	u.am.On("ApiKey", mock.Anything, "bk_guess").Return(nil, apperror.New(http.StatusUnauthorized, apperror.CodeInvalidCredentials, "invalid API key"))
+
+	codes := []int{}
+
+	for i := 0; i < 4; i++ {
+		request := httptest.NewRequest(http.MethodGet, "/books", nil)
+		request.Header.Set("X-Api-Key", "bk_guess")
+
+		writer := httptest.NewRecorder()
+		u.router.ServeHTTP(writer, request)
+
+		codes = append(codes, writer.Code)
+	}
+
+	u.Equal([]int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	u.bhm.AssertNotCalled(u.T(), "FindAll", mock.Anything)
+}
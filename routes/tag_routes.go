@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"gin-go-testing/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewTagRoutes(r *gin.RouterGroup, th handler.TagHandler) {
+	tags := r.Group("/tags")
+
+	tags.POST("", th.Create)
+	tags.GET("", th.FindAll)
+	tags.GET("/:tagId", th.FindOneById)
+	tags.PUT("/:tagId", th.Update)
+	tags.DELETE("/:tagId", th.Delete)
+}
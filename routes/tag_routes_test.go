@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"gin-go-testing/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTagRoutesSuite struct {
+	suite.Suite
+	thm    *mocks.TagHandler
+	router *gin.Engine
+}
+
+func TestUnitTestTagRoutes(t *testing.T) {
+	suite.Run(t, &unitTestTagRoutesSuite{})
+}
+
+func (u *unitTestTagRoutesSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	u.thm = mocks.NewTagHandler(u.T())
+
+	u.router = gin.New()
+	NewTagRoutes(&u.router.RouterGroup, u.thm)
+}
+
+func (u *unitTestTagRoutesSuite) TestRoutes_Registered() {
+	tests := []struct {
+		method  string
+		target  string
+		handler string
+	}{
+		{http.MethodPost, "/tags", "Create"},
+		{http.MethodGet, "/tags", "FindAll"},
+		{http.MethodGet, "/tags/1", "FindOneById"},
+		{http.MethodPut, "/tags/1", "Update"},
+		{http.MethodDelete, "/tags/1", "Delete"},
+	}
+
+	for _, test := range tests {
+		u.thm.On(test.handler, mock.Anything).Return().Once()
+
+		writer := httptest.NewRecorder()
+		u.router.ServeHTTP(writer, httptest.NewRequest(test.method, test.target, nil))
+	}
+
+	u.thm.AssertExpectations(u.T())
+}
+
+func (u *unitTestTagRoutesSuite) TestFindOneById_PassesParam() {
+	u.thm.On("FindOneById", mock.MatchedBy(func(ctx *gin.Context) bool {
+		return ctx.Param("tagId") == "7"
+	})).Return()
+
+	writer := httptest.NewRecorder()
+	u.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/tags/7", nil))
+
+	u.thm.AssertExpectations(u.T())
+}
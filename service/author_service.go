@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"gin-go-testing/model/dto"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type AuthorService interface {
+	Create(ctx context.Context, authorDto *dto.NewAuthorRequest) (*dto.AuthorResponse, errs.CustomError)
+	FindOneById(ctx context.Context, authorId uint) (*dto.AuthorResponse, errs.CustomError)
+	FindAll(ctx context.Context, req *dto.FindAllAuthorRequest) ([]*dto.AuthorResponse, *dto.PaginationMeta, errs.CustomError)
+	// Update renames an author, rewriting the byline of the books they are credited on.
+	Update(ctx context.Context, authorId uint, authorDto *dto.NewAuthorRequest) (*dto.AuthorResponse, errs.CustomError)
+	// Delete removes an author, refused while they are credited on a book.
+	Delete(ctx context.Context, authorId uint) errs.CustomError
+}
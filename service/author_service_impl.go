@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type authorServiceImpl struct {
+	ar  repository.AuthorRepository
+	br  repository.BookRepository
+	s   repository.BookSearcher
+	db  *sql.DB
+	tm  repository.TxManager
+	p   auth.Policy
+	cfg *config.Config
+}
+
+func NewAuthorServiceImpl(ar repository.AuthorRepository, br repository.BookRepository, s repository.BookSearcher, db *sql.DB, tm repository.TxManager, p auth.Policy, cfg *config.Config) AuthorService {
+	return &authorServiceImpl{ar, br, s, db, tm, p, cfg}
+}
+
+func (a *authorServiceImpl) Create(ctx context.Context, authorDto *dto.NewAuthorRequest) (*dto.AuthorResponse, errs.CustomError) {
+	if err := a.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	author := &domain.Author{Name: authorDto.Name}
+
+	result, err := a.ar.Create(ctx, a.db, author)
+
+	if err != nil {
+		return nil, withExistingId(err, "author", a.namesakeOf(ctx, author))
+	}
+
+	return newAuthorResponse(result), nil
+}
+
+func (a *authorServiceImpl) FindOneById(ctx context.Context, authorId uint) (*dto.AuthorResponse, errs.CustomError) {
+	if err := a.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+
+	result, err := a.ar.FindOneById(ctx, a.db, authorId)
+
+	if err != nil {
+		return nil, fromRepository(err, "author")
+	}
+
+	return newAuthorResponse(result), nil
+}
+
+func (a *authorServiceImpl) FindAll(ctx context.Context, req *dto.FindAllAuthorRequest) ([]*dto.AuthorResponse, *dto.PaginationMeta, errs.CustomError) {
+	if err := a.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, nil, err
+	}
+
+	params := &domain.AuthorListParams{NameContains: strings.TrimSpace(req.NameContains)}
+
+	var meta *dto.PaginationMeta
+	params.Limit, params.Offset, meta = newPage(req.Page, req.PageSize, &a.cfg.Pagination)
+
+	var result []*domain.Author
+	var total uint
+
+	err := a.tm.WithinTx(ctx, listTxOptions, func(tx repository.DBTX) errs.CustomError {
+		var err errs.CustomError
+
+		result, err = a.ar.FindAll(ctx, tx, params)
+
+		if err != nil {
+			return err
+		}
+
+		total, err = a.ar.Count(ctx, tx, params)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, fromRepository(err, "author")
+	}
+
+	setTotal(meta, total)
+
+	authorsDto := []*dto.AuthorResponse{}
+
+	for _, e := range result {
+		authorsDto = append(authorsDto, newAuthorResponse(e))
+	}
+
+	return authorsDto, meta, nil
+}
+
+func (a *authorServiceImpl) Update(ctx context.Context, authorId uint, authorDto *dto.NewAuthorRequest) (*dto.AuthorResponse, errs.CustomError) {
+	if err := a.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	author := &domain.Author{Id: authorId, Name: authorDto.Name}
+
+	var rewritten []*domain.Book
+
+	// the bylines are rewritten in the same transaction, so no book shows the old name
+	// once the new one is visible
+	err := a.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if _, err := a.ar.Update(ctx, tx, author); err != nil {
+			return err
+		}
+
+		bookIds, err := a.ar.FindBookIds(ctx, tx, authorId)
+		if err != nil {
+			return err
+		}
+
+		authors, err := a.ar.FindByBookIds(ctx, tx, bookIds)
+		if err != nil {
+			return err
+		}
+
+		for _, bookId := range bookIds {
+			book, err := a.rewriteByline(ctx, tx, bookId, authors[bookId])
+			if err != nil {
+				return err
+			}
+
+			if book != nil {
+				rewritten = append(rewritten, book)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, withExistingId(err, "author", a.namesakeOf(ctx, author))
+	}
+
+	for _, book := range rewritten {
+		a.s.Index(ctx, book)
+	}
+
+	return newAuthorResponse(author), nil
+}
+
+// rewriteByline stores the byline of the book with bookId credited to authors, and returns
+// the book when its byline changed.
+func (a *authorServiceImpl) rewriteByline(ctx context.Context, tx repository.DBTX, bookId uint, authors []*domain.Author) (*domain.Book, errs.CustomError) {
+	book, err := a.br.FindOneById(ctx, tx, bookId)
+	if err != nil {
+		return nil, err
+	}
+
+	byline := domain.Byline(authors)
+	if byline == book.Author {
+		return nil, nil
+	}
+
+	if utf8.RuneCountInString(byline) > maxBylineLength {
+		return nil, apperror.New(http.StatusUnprocessableEntity, apperror.CodeUnprocessableEntity,
+			fmt.Sprintf("the byline of book %d would be longer than %d characters", bookId, maxBylineLength))
+	}
+
+	book.Author = byline
+
+	if _, err := a.br.Patch(ctx, tx, book, []string{"author"}); err != nil {
+		// the new byline makes the book a duplicate of another one
+		if isUniqueViolation(err) {
+			return nil, apperror.From(fromRepository(err, "book")).With("book_id", bookId)
+		}
+
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (a *authorServiceImpl) Delete(ctx context.Context, authorId uint) errs.CustomError {
+	if err := a.p.Authorize(ctx, auth.PermissionDeleteBooks); err != nil {
+		return err
+	}
+
+	err := a.ar.Delete(ctx, a.db, authorId)
+
+	if repoErr, ok := err.(*repository.Error); ok && errors.Is(repoErr, repository.ErrForeignKeyViolation) {
+		return apperror.Wrap(http.StatusConflict, apperror.CodeInUse, "the author is credited on books, credit them to someone else first", repoErr)
+	}
+
+	if err != nil {
+		return fromRepository(err, "author")
+	}
+
+	return nil
+}
+
+// namesakeOf looks up the id of the author with the same name as author.
+func (a *authorServiceImpl) namesakeOf(ctx context.Context, author *domain.Author) func() (uint, bool) {
+	return func() (uint, bool) {
+		existing, err := a.ar.FindByName(ctx, a.db, author.Name)
+		if err != nil {
+			return 0, false
+		}
+
+		return existing.Id, true
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/auth/authtest"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestAuthorServiceSuite struct {
+	serviceSuite
+	arm *mocks.AuthorRepository
+	brm *mocks.BookRepository
+	sm  *mocks.BookSearcher
+	as  AuthorService
+}
+
+func TestUnitTestAuthorService(t *testing.T) {
+	suite.Run(t, &unitTestAuthorServiceSuite{})
+}
+
+func (u *unitTestAuthorServiceSuite) SetupTest() {
+	db, _, _ := sqlmock.New()
+
+	u.arm = mocks.NewAuthorRepository(u.T())
+	u.brm = mocks.NewBookRepository(u.T())
+	u.sm = mocks.NewBookSearcher(u.T())
+	u.tmm = mocks.NewTxManager(u.T())
+	u.tx = &sql.Tx{}
+
+	policy, err := auth.NewPolicyImpl(config.Default())
+	u.Require().NoError(err)
+
+	u.as = NewAuthorServiceImpl(u.arm, u.brm, u.sm, db, u.tmm, policy, config.Default())
+
+	// admins may do anything, the tests of the policy run as other roles
+	u.ctx = authtest.WithRoles(context.Background(), "admin")
+}
+
+func (u *unitTestAuthorServiceSuite) TestCreate_Success() {
+	u.arm.On("Create", u.ctx, mock.Anything, &domain.Author{Name: "James Clear"}).Return(&domain.Author{Id: 3, Name: "James Clear"}, nil)
+
+	result, err := u.as.Create(u.ctx, &dto.NewAuthorRequest{Name: "James Clear"})
+
+	u.Nil(err)
+	u.Equal(&dto.AuthorResponse{Id: 3, Name: "James Clear"}, result)
+}
+
+func (u *unitTestAuthorServiceSuite) TestCreate_Duplicate() {
+	u.arm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "CreateAuthor", Kind: repository.ErrUniqueViolation})
+	u.arm.On("FindByName", u.ctx, mock.Anything, "James  Clear").Return(&domain.Author{Id: 3, Name: "James Clear"}, nil)
+
+	result, err := u.as.Create(u.ctx, &dto.NewAuthorRequest{Name: "James  Clear"})
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(map[string]any{"existing_id": uint(3)}, apperror.From(err).Extensions())
+}
+
+func (u *unitTestAuthorServiceSuite) TestFindOneById_NotFound() {
+	u.arm.On("FindOneById", u.ctx, mock.Anything, uint(9)).Return(nil, &repository.Error{Op: "FindOneAuthorById", Kind: repository.ErrNotFound})
+
+	result, err := u.as.FindOneById(u.ctx, 9)
+
+	u.Nil(result)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+}
+
+func (u *unitTestAuthorServiceSuite) TestFindAll_Pages() {
+	params := &domain.AuthorListParams{Limit: 2, Offset: 2, NameContains: "ne"}
+
+	u.expectTx(listTxOptions)
+	u.arm.On("FindAll", u.ctx, u.tx, params).Return([]*domain.Author{{Id: 2, Name: "Neil Gaiman"}}, nil)
+	u.arm.On("Count", u.ctx, u.tx, params).Return(uint(3), nil)
+
+	result, meta, err := u.as.FindAll(u.ctx, &dto.FindAllAuthorRequest{Page: 2, PageSize: 2, NameContains: " ne "})
+
+	u.Nil(err)
+	u.Equal([]*dto.AuthorResponse{{Id: 2, Name: "Neil Gaiman"}}, result)
+	u.Equal(&dto.PaginationMeta{Page: 2, PageSize: 2, TotalCount: 3, TotalPages: 2}, meta)
+}
+
+func (u *unitTestAuthorServiceSuite) TestUpdate_RewritesBylines() {
+	author := &domain.Author{Id: 2, Name: "Neil R. Gaiman"}
+
+	u.expectTx(nil)
+	u.arm.On("Update", u.ctx, u.tx, author).Return(author, nil)
+	u.arm.On("FindBookIds", u.ctx, u.tx, uint(2)).Return([]uint{4, 5}, nil)
+	u.arm.On("FindByBookIds", u.ctx, u.tx, []uint{4, 5}).Return(map[uint][]*domain.Author{
+		4: {{Id: 3, Name: "Terry Pratchett"}, author},
+		5: {author},
+	}, nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(4)).Return(&domain.Book{Id: 4, Title: "Good Omens", Author: "Terry Pratchett, Neil Gaiman"}, nil)
+	u.brm.On("Patch", u.ctx, u.tx, &domain.Book{Id: 4, Title: "Good Omens", Author: "Terry Pratchett, Neil R. Gaiman"}, []string{"author"}).Return(nil, nil)
+	// the byline of book 5 already reads the new name
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(5)).Return(&domain.Book{Id: 5, Title: "The Sandman", Author: "Neil R. Gaiman"}, nil)
+
+	// only book 4 changed, so only it is reindexed
+	u.sm.On("Index", u.ctx, &domain.Book{Id: 4, Title: "Good Omens", Author: "Terry Pratchett, Neil R. Gaiman"}).Return().Once()
+	result, err := u.as.Update(u.ctx, 2, &dto.NewAuthorRequest{Name: "Neil R. Gaiman"})
+
+	u.Nil(err)
+	u.Equal(&dto.AuthorResponse{Id: 2, Name: "Neil R. Gaiman"}, result)
+}
+
+func (u *unitTestAuthorServiceSuite) TestUpdate_BylineTooLong() {
+	name := strings.Repeat("a", 250)
+	author := &domain.Author{Id: 2, Name: name}
+
+	u.expectTx(nil)
+	u.arm.On("Update", u.ctx, u.tx, author).Return(author, nil)
+	u.arm.On("FindBookIds", u.ctx, u.tx, uint(2)).Return([]uint{4}, nil)
+	u.arm.On("FindByBookIds", u.ctx, u.tx, []uint{4}).Return(map[uint][]*domain.Author{4: {{Id: 3, Name: "Terry Pratchett"}, author}}, nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(4)).Return(&domain.Book{Id: 4, Title: "Good Omens", Author: "Terry Pratchett, Neil Gaiman"}, nil)
+
+	result, err := u.as.Update(u.ctx, 2, &dto.NewAuthorRequest{Name: name})
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+}
+
+func (u *unitTestAuthorServiceSuite) TestUpdate_BookBecomesDuplicate() {
+	author := &domain.Author{Id: 2, Name: "Frank Herbert"}
+
+	u.expectTx(nil)
+	u.arm.On("Update", u.ctx, u.tx, author).Return(author, nil)
+	u.arm.On("FindBookIds", u.ctx, u.tx, uint(2)).Return([]uint{4}, nil)
+	u.arm.On("FindByBookIds", u.ctx, u.tx, []uint{4}).Return(map[uint][]*domain.Author{4: {author}}, nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(4)).Return(&domain.Book{Id: 4, Title: "Dune", Author: "F. Herbert"}, nil)
+	u.brm.On("Patch", u.ctx, u.tx, mock.Anything, []string{"author"}).Return(nil, &repository.Error{Op: "PatchBook", Kind: repository.ErrUniqueViolation})
+
+	result, err := u.as.Update(u.ctx, 2, &dto.NewAuthorRequest{Name: "Frank Herbert"})
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(map[string]any{"book_id": uint(4)}, apperror.From(err).Extensions())
+}
+
+func (u *unitTestAuthorServiceSuite) TestDelete_StillCredited() {
+	u.arm.On("Delete", u.ctx, mock.Anything, uint(2)).Return(&repository.Error{Op: "DeleteAuthor", Kind: repository.ErrForeignKeyViolation})
+
+	err := u.as.Delete(u.ctx, 2)
+
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(apperror.CodeInUse, apperror.CodeOf(err))
+}
+
+func (u *unitTestAuthorServiceSuite) TestDelete_NotFound() {
+	u.arm.On("Delete", u.ctx, mock.Anything, uint(9)).Return(&repository.Error{Op: "DeleteAuthor", Kind: repository.ErrNotFound})
+
+	err := u.as.Delete(u.ctx, 9)
+
+	u.Equal(http.StatusNotFound, err.StatusCode())
+}
+
+func (u *unitTestAuthorServiceSuite) TestPolicy_Reader() {
+	ctx := authtest.WithRoles(context.Background(), "reader")
+
+	_, err := u.as.Update(ctx, 1, &dto.NewAuthorRequest{Name: "Brian Herbert"})
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.Equal(map[string]any{"permission": auth.PermissionWriteBooks}, apperror.From(err).Extensions())
+	u.tmm.AssertNotCalled(u.T(), "WithinTx", mock.Anything, mock.Anything, mock.Anything)
+
+	u.arm.On("FindOneById", ctx, mock.Anything, uint(1)).Return(&domain.Author{Id: 1, Name: "Frank Herbert"}, nil)
+
+	result, err := u.as.FindOneById(ctx, 1)
+	u.Nil(err)
+	u.Equal("Frank Herbert", result.Name)
+}
+
+func (u *unitTestAuthorServiceSuite) TestPolicy_EditorCannotDelete() {
+	err := u.as.Delete(authtest.WithRoles(context.Background(), "editor"), 1)
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.arm.AssertNotCalled(u.T(), "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
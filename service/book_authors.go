@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"unicode/utf8"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// maxBylineLength is the size of the books.author column the byline is stored in.
+const maxBylineLength = 255
+
+// resolveAuthors credits book to the existing authors with authorIds or, when there are none,
+// to the author named name, created when needed. It sets the byline from their names.
+func resolveAuthors(ctx context.Context, ar repository.AuthorRepository, tx repository.DBTX, book *domain.Book, name string, authorIds []uint) errs.CustomError {
+	if authorIds == nil {
+		author, err := ar.Ensure(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		book.Authors = []*domain.Author{author}
+	} else {
+		authors, err := ar.FindByIds(ctx, tx, authorIds)
+		if err != nil {
+			return err
+		}
+
+		found := map[uint]*domain.Author{}
+		for _, author := range authors {
+			found[author.Id] = author
+		}
+
+		book.Authors = make([]*domain.Author, 0, len(authorIds))
+
+		for i, authorId := range authorIds {
+			author, ok := found[authorId]
+			if !ok {
+				return apperror.NewValidationError([]*dto.FieldError{{
+					Field:   fmt.Sprintf("author_ids[%d]", i),
+					Rule:    "exists",
+					Message: fmt.Sprintf("author %d does not exist", authorId),
+				}})
+			}
+
+			book.Authors = append(book.Authors, author)
+		}
+	}
+
+	book.Author = domain.Byline(book.Authors)
+
+	if utf8.RuneCountInString(book.Author) > maxBylineLength {
+		return apperror.NewValidationError([]*dto.FieldError{{
+			Field:   "author_ids",
+			Rule:    "byline",
+			Message: fmt.Sprintf("the names of the authors must be at most %d characters long together", maxBylineLength),
+		}})
+	}
+
+	return nil
+}
+
+func authorIdsOf(authors []*domain.Author) []uint {
+	ids := make([]uint, 0, len(authors))
+
+	for _, author := range authors {
+		ids = append(ids, author.Id)
+	}
+
+	return ids
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"strings"
+)
+
+// newBookListParams translates a listing request into repository params and the
+// paging part of the response meta. limit/offset take precedence over page/page_size.
+func newBookListParams(req *dto.FindAllBookRequest, cfg *config.PaginationConfig) (*domain.BookListParams, *dto.PaginationMeta) {
+	params := &domain.BookListParams{
+		Sort:          parseSort(req.Sort),
+		Author:        strings.TrimSpace(req.Author),
+		TitleContains: strings.TrimSpace(req.TitleContains),
+	}
+
+	if req.Limit > 0 {
+		params.Limit = min(req.Limit, cfg.MaxPageSize)
+		params.Offset = req.Offset
+
+		return params, &dto.PaginationMeta{Page: params.Offset/params.Limit + 1, PageSize: params.Limit}
+	}
+
+	var meta *dto.PaginationMeta
+	params.Limit, params.Offset, meta = newPage(req.Page, req.PageSize, cfg)
+
+	return params, meta
+}
+
+// newPage returns the limit and offset reading the page a page/page_size request asks for,
+// and the paging part of the response meta. Every listing pages the same way: from page 1,
+// the default page size when there is none and no more than the maximum.
+func newPage(page uint, pageSize uint, cfg *config.PaginationConfig) (uint, uint, *dto.PaginationMeta) {
+	page = max(page, 1)
+
+	if pageSize == 0 {
+		pageSize = cfg.DefaultPageSize
+	}
+
+	limit := min(pageSize, cfg.MaxPageSize)
+
+	return limit, (page - 1) * limit, &dto.PaginationMeta{Page: page, PageSize: limit}
+}
+
+// setTotal completes meta with the number of items of the whole listing.
+func setTotal(meta *dto.PaginationMeta, total uint) {
+	meta.TotalCount = total
+	meta.TotalPages = (total + meta.PageSize - 1) / meta.PageSize
+}
+
+// parseSort parses a comma separated list such as "title,-id", where a leading
+// minus sorts that field in descending order.
+func parseSort(sort string) []domain.SortField {
+	fields := []domain.SortField{}
+
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if strings.HasPrefix(field, "-") {
+			fields = append(fields, domain.SortField{Field: strings.TrimPrefix(field, "-"), Desc: true})
+			continue
+		}
+
+		fields = append(fields, domain.SortField{Field: strings.TrimPrefix(field, "+")})
+	}
+
+	return fields
+}
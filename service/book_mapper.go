@@ -0,0 +1,82 @@
+package service
+
+import (
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"slices"
+)
+
+func newBook(bookId uint, bookDto *dto.NewBookRequest) *domain.Book {
+	return &domain.Book{
+		Id:              bookId,
+		Title:           bookDto.Title,
+		Author:          bookDto.Author,
+		Isbn:            bookDto.Isbn,
+		PublicationYear: bookDto.PublicationYear,
+		Publisher:       bookDto.Publisher,
+		Language:        bookDto.Language,
+		PageCount:       bookDto.PageCount,
+		Description:     bookDto.Description,
+		CoverUrl:        bookDto.CoverUrl,
+	}
+}
+
+func newAuthorResponse(author *domain.Author) *dto.AuthorResponse {
+	return &dto.AuthorResponse{Id: author.Id, Name: author.Name}
+}
+
+func newTagResponse(tag *domain.Tag) *dto.TagResponse {
+	return &dto.TagResponse{Id: tag.Id, Name: tag.Name, ParentId: tag.ParentId}
+}
+
+func newBookResponse(book *domain.Book) *dto.BookResponse {
+	authors := make([]*dto.AuthorResponse, 0, len(book.Authors))
+
+	for _, author := range book.Authors {
+		authors = append(authors, newAuthorResponse(author))
+	}
+
+	tags := make([]*dto.TagResponse, 0, len(book.Tags))
+
+	for _, tag := range book.Tags {
+		tags = append(tags, newTagResponse(tag))
+	}
+
+	return &dto.BookResponse{
+		Id:              book.Id,
+		Title:           book.Title,
+		Author:          book.Author,
+		Authors:         authors,
+		Tags:            tags,
+		Isbn:            book.Isbn,
+		PublicationYear: book.PublicationYear,
+		Publisher:       book.Publisher,
+		Language:        book.Language,
+		PageCount:       book.PageCount,
+		Description:     book.Description,
+		CoverUrl:        book.CoverUrl,
+	}
+}
+
+// patchDetail applies the merge patch member of an optional detail, removed when the member
+// is listed in removed, and tells whether the detail changed.
+func patchDetail[T comparable](detail **T, value *T, removed []string, member string) bool {
+	switch {
+	case slices.Contains(removed, member):
+		if *detail == nil {
+			return false
+		}
+
+		*detail = nil
+	case value != nil:
+		if *detail != nil && **detail == *value {
+			return false
+		}
+
+		*detail = value
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"gin-go-testing/auth"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+func (b *bookServiceImpl) Search(ctx context.Context, req *dto.SearchBookRequest) ([]*dto.BookSearchHitResponse, *dto.PaginationMeta, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, nil, err
+	}
+
+	params := &domain.BookSearchParams{Query: req.Q}
+
+	var meta *dto.PaginationMeta
+	params.Limit, params.Offset, meta = newPage(req.Page, req.PageSize, &b.cfg.Pagination)
+
+	hits, total, err := b.s.Search(ctx, b.db, params)
+	if err != nil {
+		return nil, nil, fromRepository(err, "book")
+	}
+
+	books := make([]*domain.Book, 0, len(hits))
+	for _, hit := range hits {
+		books = append(books, hit.Book)
+	}
+
+	if err := b.loadRelations(ctx, b.db, books...); err != nil {
+		return nil, nil, fromRepository(err, "book")
+	}
+
+	setTotal(meta, total)
+
+	hitsDto := make([]*dto.BookSearchHitResponse, 0, len(hits))
+
+	for _, hit := range hits {
+		hitsDto = append(hitsDto, &dto.BookSearchHitResponse{
+			Book:  newBookResponse(hit.Book),
+			Score: hit.Score,
+			Highlight: &dto.BookHighlightResponse{
+				Title:       hit.Highlight.Title,
+				Author:      hit.Highlight.Author,
+				Description: hit.Highlight.Description,
+			},
+		})
+	}
+
+	return hitsDto, meta, nil
+}
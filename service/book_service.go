@@ -1,14 +1,25 @@
 package service
 
 import (
+	"context"
 	"gin-go-testing/model/dto"
 
-	"github.com/gin-gonic/gin"
 	"github.com/rulyadhika/go-custom-err/errs"
 )
 
 type BookService interface {
-	Create(ctx *gin.Context, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError)
-	FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResponse, errs.CustomError)
-	FindAll(ctx *gin.Context) ([]*dto.BookResponse, errs.CustomError)
+	// Create stores a new book. When the book already exists it fails with a conflict or, with
+	// upsert, returns the existing book. The bool tells whether the book was created.
+	Create(ctx context.Context, bookDto *dto.NewBookRequest, upsert bool) (*dto.BookResponse, bool, errs.CustomError)
+	FindOneById(ctx context.Context, bookId uint) (*dto.BookResponse, errs.CustomError)
+	FindAll(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.PaginationMeta, errs.CustomError)
+	FindAllByCursor(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.CursorMeta, errs.CustomError)
+	// Search pages through the books matching the words of a query, best matches first.
+	Search(ctx context.Context, req *dto.SearchBookRequest) ([]*dto.BookSearchHitResponse, *dto.PaginationMeta, errs.CustomError)
+	Update(ctx context.Context, bookId uint, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError)
+	Patch(ctx context.Context, bookId uint, patchDto *dto.PatchBookRequest) (*dto.BookResponse, errs.CustomError)
+	Delete(ctx context.Context, bookId uint) errs.CustomError
+	// AddTag and RemoveTag tag and untag a book, returning it with its tags. Both are idempotent.
+	AddTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError)
+	RemoveTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError)
 }
@@ -1,58 +1,395 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
 	"gin-go-testing/model/domain"
 	"gin-go-testing/model/dto"
 	"gin-go-testing/repository"
+	"slices"
 
-	"github.com/gin-gonic/gin"
 	"github.com/rulyadhika/go-custom-err/errs"
 )
 
 type bookServiceImpl struct {
-	br repository.BookRepository
-	db *sql.DB
+	br  repository.BookRepository
+	ar  repository.AuthorRepository
+	tr  repository.TagRepository
+	s   repository.BookSearcher
+	db  *sql.DB
+	tm  repository.TxManager
+	p   auth.Policy
+	cfg *config.Config
 }
 
-func NewBookServiceImpl(br repository.BookRepository, db *sql.DB) BookService {
-	return &bookServiceImpl{br, db}
+func NewBookServiceImpl(br repository.BookRepository, ar repository.AuthorRepository, tr repository.TagRepository, s repository.BookSearcher, db *sql.DB, tm repository.TxManager, p auth.Policy, cfg *config.Config) BookService {
+	return &bookServiceImpl{br, ar, tr, s, db, tm, p, cfg}
 }
 
-func (b *bookServiceImpl) Create(ctx *gin.Context, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError) {
-	book := &domain.Book{Title: bookDto.Title, Author: bookDto.Author}
+// listTxOptions gives the page and its total count the same snapshot.
+var listTxOptions = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
 
-	result, err := b.br.Create(ctx, b.db, book)
+func (b *bookServiceImpl) Create(ctx context.Context, bookDto *dto.NewBookRequest, upsert bool) (*dto.BookResponse, bool, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, false, err
+	}
+
+	book := newBook(0, bookDto)
+
+	err := b.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := resolveAuthors(ctx, b.ar, tx, book, bookDto.Author, bookDto.AuthorIds); err != nil {
+			return err
+		}
+
+		if _, err := b.br.Create(ctx, tx, book); err != nil {
+			return err
+		}
+
+		return b.ar.SetBookAuthors(ctx, tx, book.Id, authorIdsOf(book.Authors))
+	})
 
 	if err != nil {
-		return nil, err
+		if !upsert || !isUniqueViolation(err) {
+			return nil, false, withExistingId(err, "book", b.duplicateOf(ctx, book))
+		}
+
+		// the insert lost to the stored book, a concurrent delete of it may leave none
+		existing, errFind := b.br.FindDuplicate(ctx, b.db, book)
+		if errFind != nil {
+			return nil, false, fromRepository(err, "book")
+		}
+
+		if errLoad := b.loadRelations(ctx, b.db, existing); errLoad != nil {
+			return nil, false, fromRepository(errLoad, "book")
+		}
+
+		return newBookResponse(existing), false, nil
 	}
 
-	return &dto.BookResponse{Id: result.Id, Title: result.Title, Author: result.Author}, nil
+	b.s.Index(ctx, book)
+
+	return newBookResponse(book), true, nil
 }
 
-func (b *bookServiceImpl) FindOneById(ctx *gin.Context, bookId uint) (*dto.BookResponse, errs.CustomError) {
+func (b *bookServiceImpl) FindOneById(ctx context.Context, bookId uint) (*dto.BookResponse, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+
 	result, err := b.br.FindOneById(ctx, b.db, bookId)
 
 	if err != nil {
-		return nil, err
+		return nil, fromRepository(err, "book")
+	}
+
+	if err := b.loadRelations(ctx, b.db, result); err != nil {
+		return nil, fromRepository(err, "book")
 	}
 
-	return &dto.BookResponse{Id: result.Id, Title: result.Title, Author: result.Author}, nil
+	return newBookResponse(result), nil
 }
 
-func (b *bookServiceImpl) FindAll(ctx *gin.Context) ([]*dto.BookResponse, errs.CustomError) {
-	result, err := b.br.FindAll(ctx, b.db)
+func (b *bookServiceImpl) FindAll(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.PaginationMeta, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, nil, err
+	}
+
+	params, meta := newBookListParams(req, &b.cfg.Pagination)
+
+	var result []*domain.Book
+	var total uint
+	var facets []*dto.TagFacet
+
+	err := b.tm.WithinTx(ctx, listTxOptions, func(tx repository.DBTX) errs.CustomError {
+		var err errs.CustomError
+
+		if params.TagIds, err = b.tagFilter(ctx, tx, req); err != nil {
+			return err
+		}
+
+		result, err = b.br.FindAll(ctx, tx, params)
+
+		if err != nil {
+			return err
+		}
+
+		if err = b.loadRelations(ctx, tx, result...); err != nil {
+			return err
+		}
+
+		if total, err = b.br.Count(ctx, tx, params); err != nil {
+			return err
+		}
+
+		facets, err = b.tagFacets(ctx, tx, params)
+
+		return err
+	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, fromRepository(err, "book")
 	}
 
+	setTotal(meta, total)
+	meta.Facets = &dto.Facets{Tags: facets}
+
 	booksDto := []*dto.BookResponse{}
 
 	for _, e := range result {
-		booksDto = append(booksDto, &dto.BookResponse{Id: e.Id, Title: e.Title, Author: e.Author})
+		booksDto = append(booksDto, newBookResponse(e))
+	}
+
+	return booksDto, meta, nil
+}
+
+func (b *bookServiceImpl) FindAllByCursor(ctx context.Context, req *dto.FindAllBookRequest) ([]*dto.BookResponse, *dto.CursorMeta, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, nil, err
+	}
+
+	params, meta := newBookListParams(req, &b.cfg.Pagination)
+	params.Offset = 0
+
+	cursor := ""
+	if req.Cursor != nil {
+		cursor = *req.Cursor
+	}
+
+	tagIds, errFilter := b.tagFilter(ctx, b.db, req)
+	if errFilter != nil {
+		return nil, nil, fromRepository(errFilter, "book")
+	}
+
+	params.TagIds = tagIds
+
+	result, nextCursor, err := b.br.FindAllByCursor(ctx, b.db, params, cursor)
+
+	if err != nil {
+		return nil, nil, fromRepository(err, "book")
+	}
+
+	if err := b.loadRelations(ctx, b.db, result...); err != nil {
+		return nil, nil, fromRepository(err, "book")
+	}
+
+	booksDto := []*dto.BookResponse{}
+
+	for _, e := range result {
+		booksDto = append(booksDto, newBookResponse(e))
+	}
+
+	return booksDto, &dto.CursorMeta{PageSize: meta.PageSize, NextCursor: nextCursor}, nil
+}
+
+func (b *bookServiceImpl) Update(ctx context.Context, bookId uint, bookDto *dto.NewBookRequest) (*dto.BookResponse, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	book := newBook(bookId, bookDto)
+
+	err := b.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := resolveAuthors(ctx, b.ar, tx, book, bookDto.Author, bookDto.AuthorIds); err != nil {
+			return err
+		}
+
+		if _, err := b.br.Update(ctx, tx, book); err != nil {
+			return err
+		}
+
+		if err := b.ar.SetBookAuthors(ctx, tx, book.Id, authorIdsOf(book.Authors)); err != nil {
+			return err
+		}
+
+		// a replaced book keeps its tags
+		return b.loadTags(ctx, tx, book)
+	})
+
+	if err != nil {
+		return nil, withExistingId(err, "book", b.duplicateOf(ctx, book))
 	}
 
-	return booksDto, nil
+	b.s.Index(ctx, book)
+
+	return newBookResponse(book), nil
+}
+
+func (b *bookServiceImpl) Patch(ctx context.Context, bookId uint, patchDto *dto.PatchBookRequest) (*dto.BookResponse, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	var result, patched *domain.Book
+
+	// the book is read and written in one transaction, so concurrent patches of other
+	// fields are not lost
+	err := b.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		book, err := b.br.FindOneById(ctx, tx, bookId)
+
+		if err != nil {
+			return err
+		}
+
+		if err := b.loadRelations(ctx, tx, book); err != nil {
+			return err
+		}
+
+		columns := []string{}
+
+		if patchDto.Title != nil && *patchDto.Title != book.Title {
+			book.Title = *patchDto.Title
+			columns = append(columns, "title")
+		}
+
+		authorsChanged := false
+
+		if patchDto.Author != nil || patchDto.AuthorIds != nil {
+			name, previous := "", authorIdsOf(book.Authors)
+			if patchDto.Author != nil {
+				name = *patchDto.Author
+			}
+
+			byline := book.Author
+
+			if err := resolveAuthors(ctx, b.ar, tx, book, name, patchDto.AuthorIds); err != nil {
+				return err
+			}
+
+			authorsChanged = !slices.Equal(previous, authorIdsOf(book.Authors))
+
+			if book.Author != byline {
+				columns = append(columns, "author")
+			}
+		}
+
+		details := []struct {
+			column  string
+			changed bool
+		}{
+			{"isbn", patchDetail(&book.Isbn, patchDto.Isbn, patchDto.Removed, "isbn")},
+			{"publication_year", patchDetail(&book.PublicationYear, patchDto.PublicationYear, patchDto.Removed, "publication_year")},
+			{"publisher", patchDetail(&book.Publisher, patchDto.Publisher, patchDto.Removed, "publisher")},
+			{"language", patchDetail(&book.Language, patchDto.Language, patchDto.Removed, "language")},
+			{"page_count", patchDetail(&book.PageCount, patchDto.PageCount, patchDto.Removed, "page_count")},
+			{"description", patchDetail(&book.Description, patchDto.Description, patchDto.Removed, "description")},
+			{"cover_url", patchDetail(&book.CoverUrl, patchDto.CoverUrl, patchDto.Removed, "cover_url")},
+		}
+
+		for _, detail := range details {
+			if detail.changed {
+				columns = append(columns, detail.column)
+			}
+		}
+
+		fieldErrors := []*dto.FieldError{}
+
+		for _, field := range []struct{ name, value string }{{"title", book.Title}, {"author", book.Author}} {
+			if field.value == "" {
+				fieldErrors = append(fieldErrors, &dto.FieldError{Field: field.name, Rule: "required", Message: field.name + " must not be empty"})
+			}
+		}
+
+		if len(fieldErrors) > 0 {
+			return apperror.NewValidationError(fieldErrors)
+		}
+
+		result = book
+
+		if authorsChanged {
+			if err := b.ar.SetBookAuthors(ctx, tx, book.Id, authorIdsOf(book.Authors)); err != nil {
+				return err
+			}
+		}
+
+		// nothing else changed, so there is no need to touch the book
+		if len(columns) == 0 {
+			return nil
+		}
+
+		patched = book
+		_, err = b.br.Patch(ctx, tx, book, columns)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, withExistingId(err, "book", b.duplicateOf(ctx, patched))
+	}
+
+	if patched != nil {
+		b.s.Index(ctx, patched)
+	}
+
+	return newBookResponse(result), nil
+}
+
+func (b *bookServiceImpl) Delete(ctx context.Context, bookId uint) errs.CustomError {
+	if err := b.p.Authorize(ctx, auth.PermissionDeleteBooks); err != nil {
+		return err
+	}
+
+	err := b.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := b.ar.SetBookAuthors(ctx, tx, bookId, nil); err != nil {
+			return err
+		}
+
+		return b.br.Delete(ctx, tx, bookId)
+	})
+
+	if err != nil {
+		return fromRepository(err, "book")
+	}
+
+	b.s.Remove(ctx, bookId)
+
+	return nil
+}
+
+// loadRelations fills in the authors and tags of books.
+func (b *bookServiceImpl) loadRelations(ctx context.Context, db repository.DBTX, books ...*domain.Book) errs.CustomError {
+	if err := b.loadAuthors(ctx, db, books...); err != nil {
+		return err
+	}
+
+	return b.loadTags(ctx, db, books...)
+}
+
+// loadAuthors fills in the authors of books.
+func (b *bookServiceImpl) loadAuthors(ctx context.Context, db repository.DBTX, books ...*domain.Book) errs.CustomError {
+	bookIds := make([]uint, 0, len(books))
+
+	for _, book := range books {
+		bookIds = append(bookIds, book.Id)
+	}
+
+	authors, err := b.ar.FindByBookIds(ctx, db, bookIds)
+	if err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		book.Authors = authors[book.Id]
+	}
+
+	return nil
+}
+
+// duplicateOf looks up the id of the book book is a duplicate of. It looks the book up
+// outside of any transaction, as Postgres aborts the one a statement failed in.
+func (b *bookServiceImpl) duplicateOf(ctx context.Context, book *domain.Book) func() (uint, bool) {
+	return func() (uint, bool) {
+		if book == nil {
+			return 0, false
+		}
+
+		existing, err := b.br.FindDuplicate(ctx, b.db, book)
+		if err != nil {
+			return 0, false
+		}
+
+		return existing.Id, true
+	}
 }
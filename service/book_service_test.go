@@ -1,22 +1,34 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/auth/authtest"
+	"gin-go-testing/config"
 	"gin-go-testing/mocks"
 	"gin-go-testing/model/domain"
 	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/gin-gonic/gin"
 	"github.com/rulyadhika/go-custom-err/errs"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type unitTestBookServiceSuite struct {
-	suite.Suite
-	ctx *gin.Context
+	serviceSuite
 	brm *mocks.BookRepository
+	arm *mocks.AuthorRepository
+	trm *mocks.TagRepository
+	sm  *mocks.BookSearcher
+	p   auth.Policy
 	bs  BookService
 }
 
@@ -30,33 +42,124 @@ func (u *unitTestBookServiceSuite) SetupTest() {
 	db, _, _ := sqlmock.New()
 
 	u.brm = bookRepoMock
-	u.bs = NewBookServiceImpl(bookRepoMock, db)
+	u.arm = mocks.NewAuthorRepository(u.T())
+	u.trm = mocks.NewTagRepository(u.T())
+	u.sm = mocks.NewBookSearcher(u.T())
+	u.tmm = mocks.NewTxManager(u.T())
+	u.tx = &sql.Tx{}
 
-	u.ctx = &gin.Context{}
+	policy, err := auth.NewPolicyImpl(config.Default())
+	u.Require().NoError(err)
+
+	u.p = policy
+	u.bs = NewBookServiceImpl(bookRepoMock, u.arm, u.trm, u.sm, db, u.tmm, u.p, config.Default())
+
+	// admins may do anything, the tests of the policy run as other roles
+	u.ctx = authtest.WithRoles(context.Background(), "admin")
+}
+
+// expectAuthor makes the mocked AuthorRepository find or create the author named name.
+func (u *unitTestBookServiceSuite) expectAuthor(id uint, name string) *domain.Author {
+	author := &domain.Author{Id: id, Name: name}
+	u.arm.On("Ensure", u.ctx, u.tx, name).Return(author, nil)
+
+	return author
+}
+
+// expectAuthors makes the mocked AuthorRepository load authors as the authors of books.
+func (u *unitTestBookServiceSuite) expectAuthors(authors map[uint][]*domain.Author) {
+	u.arm.On("FindByBookIds", u.ctx, mock.Anything, mock.Anything).Return(authors, nil)
+}
+
+// expectTags makes the mocked repositories load tags as the tags of books.
+func (u *unitTestBookServiceSuite) expectTags(tags map[uint][]*domain.Tag) {
+	tagIds := map[uint][]uint{}
+	found := []*domain.Tag{}
+
+	for bookId, bookTags := range tags {
+		for _, tag := range bookTags {
+			tagIds[bookId] = append(tagIds[bookId], tag.Id)
+			found = append(found, tag)
+		}
+	}
+
+	u.brm.On("FindTagIds", u.ctx, mock.Anything, mock.Anything).Return(tagIds, nil)
+
+	if len(found) > 0 {
+		u.trm.On("FindByIds", u.ctx, mock.Anything, mock.Anything).Return(found, nil)
+	}
+}
+
+// createdWithId is a mocked BookRepository.Create, which gives the book it stores an id.
+func createdWithId(id uint) func(ctx context.Context, db repository.DBTX, book *domain.Book) *domain.Book {
+	return func(ctx context.Context, db repository.DBTX, book *domain.Book) *domain.Book {
+		book.Id = id
+		return book
+	}
 }
 
 func (u *unitTestBookServiceSuite) TestCreate_Success() {
-	data := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
-	reqDto := &dto.NewBookRequest{Title: data.Title, Author: data.Author}
-	expected := &dto.BookResponse{Id: data.Id, Title: data.Title, Author: data.Author}
+	reqDto := &dto.NewBookRequest{Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+	expected := &dto.BookResponse{
+		Id:      2,
+		Title:   reqDto.Title,
+		Author:  reqDto.Author,
+		Authors: []*dto.AuthorResponse{{Id: 4, Name: reqDto.Author}},
+		Tags:    []*dto.TagResponse{},
+	}
 
-	u.brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(data, nil)
+	u.expectTx(nil)
+	author := u.expectAuthor(4, reqDto.Author)
+	u.brm.On("Create", u.ctx, u.tx, &domain.Book{Title: reqDto.Title, Author: reqDto.Author, Authors: []*domain.Author{author}}).Return(createdWithId(2), nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(2), []uint{4}).Return(nil)
+	// the created book becomes searchable
+	u.sm.On("Index", u.ctx, mock.MatchedBy(func(book *domain.Book) bool { return book.Id == 2 })).Return()
 
-	result, err := u.bs.Create(u.ctx, reqDto)
+	result, created, err := u.bs.Create(u.ctx, reqDto, false)
 	u.Nil(err)
+	u.True(created)
 	u.NotNil(result)
 	u.Equal(expected, result)
 
 	u.brm.AssertExpectations(u.T())
 }
 
+func (u *unitTestBookServiceSuite) TestCreate_Details() {
+	isbn, year, language := "9780441013593", 1965, "en"
+	reqDto := &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert", Isbn: &isbn, PublicationYear: &year, Language: &language}
+
+	u.expectTx(nil)
+	author := u.expectAuthor(1, "Frank Herbert")
+	book := &domain.Book{Title: "Dune", Author: "Frank Herbert", Authors: []*domain.Author{author}, Isbn: &isbn, PublicationYear: &year, Language: &language}
+	u.brm.On("Create", u.ctx, u.tx, book).Return(createdWithId(1), nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(1), []uint{1}).Return(nil)
+	u.sm.On("Index", u.ctx, mock.Anything).Return()
+
+	result, _, err := u.bs.Create(u.ctx, reqDto, false)
+	u.Nil(err)
+	u.Equal(&dto.BookResponse{
+		Id:              1,
+		Title:           "Dune",
+		Author:          "Frank Herbert",
+		Authors:         []*dto.AuthorResponse{{Id: 1, Name: "Frank Herbert"}},
+		Tags:            []*dto.TagResponse{},
+		Isbn:            &isbn,
+		PublicationYear: &year,
+		Language:        &language,
+	}, result)
+
+	u.brm.AssertExpectations(u.T())
+}
+
 func (u *unitTestBookServiceSuite) TestCreate_Failed() {
 	data := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
 	reqDto := &dto.NewBookRequest{Title: data.Title, Author: data.Author}
 
+	u.expectTx(nil)
+	u.expectAuthor(4, data.Author)
 	u.brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(nil, errs.NewInternalServerError("something went wrong"))
 
-	result, err := u.bs.Create(u.ctx, reqDto)
+	result, _, err := u.bs.Create(u.ctx, reqDto, false)
 	u.NotNil(err)
 	u.Nil(result)
 
@@ -65,9 +168,11 @@ func (u *unitTestBookServiceSuite) TestCreate_Failed() {
 
 func (u *unitTestBookServiceSuite) TestFindOneById_Success() {
 	data := &domain.Book{Id: 1, Title: "Atomic Habits: An Easy & Proven Way to Build Good Habits & Break Bad Ones", Author: "James Clear"}
-	expected := &dto.BookResponse{Id: data.Id, Title: data.Title, Author: data.Author}
+	expected := &dto.BookResponse{Id: data.Id, Title: data.Title, Author: data.Author, Authors: []*dto.AuthorResponse{{Id: 3, Name: "James Clear"}}, Tags: []*dto.TagResponse{}}
 
 	u.brm.On("FindOneById", u.ctx, mock.Anything, mock.Anything).Return(data, nil)
+	u.expectAuthors(map[uint][]*domain.Author{1: {{Id: 3, Name: "James Clear"}}})
+	u.expectTags(nil)
 
 	result, err := u.bs.FindOneById(u.ctx, 1)
 
@@ -79,12 +184,13 @@ func (u *unitTestBookServiceSuite) TestFindOneById_Success() {
 }
 
 func (u *unitTestBookServiceSuite) TestFindOneById_NotFound() {
-	u.brm.On("FindOneById", u.ctx, mock.Anything, mock.Anything).Return(nil, errs.NewNotFoundError("data not found"))
+	u.brm.On("FindOneById", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "FindOneBookById", Kind: repository.ErrNotFound})
 
 	result, err := u.bs.FindOneById(u.ctx, 2)
 
-	u.NotNil(err)
 	u.Nil(result)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+	u.Equal(apperror.CodeNotFound, apperror.CodeOf(err))
 
 	u.brm.AssertExpectations(u.T())
 }
@@ -106,27 +212,649 @@ func (u *unitTestBookServiceSuite) TestFindAll_Success() {
 	var expected []*dto.BookResponse
 
 	for _, e := range data {
-		expected = append(expected, &dto.BookResponse{Id: e.Id, Title: e.Title, Author: e.Author})
+		expected = append(expected, &dto.BookResponse{Id: e.Id, Title: e.Title, Author: e.Author, Authors: []*dto.AuthorResponse{}, Tags: []*dto.TagResponse{}})
 	}
 
-	u.brm.On("FindAll", u.ctx, mock.Anything).Return(data, nil)
+	u.expectTx(listTxOptions)
+	u.brm.On("FindAll", u.ctx, u.tx, &domain.BookListParams{Limit: 20, Sort: []domain.SortField{}}).Return(data, nil)
+	u.arm.On("FindByBookIds", u.ctx, u.tx, []uint{1, 2}).Return(map[uint][]*domain.Author{}, nil)
+	u.expectTags(nil)
+	u.brm.On("Count", u.ctx, u.tx, mock.Anything).Return(uint(2), nil)
+	u.brm.On("CountTags", u.ctx, u.tx, mock.Anything).Return(map[uint]uint{}, nil)
 
-	result, err := u.bs.FindAll(u.ctx)
+	result, meta, err := u.bs.FindAll(u.ctx, &dto.FindAllBookRequest{})
 
 	u.NotNil(result)
 	u.Nil(err)
 	u.Equal(expected, result)
+	u.Equal(&dto.PaginationMeta{Page: 1, PageSize: 20, TotalCount: 2, TotalPages: 1, Facets: &dto.Facets{Tags: []*dto.TagFacet{}}}, meta)
 
 	u.brm.AssertExpectations(u.T())
 }
 
 func (u *unitTestBookServiceSuite) TestFindAll_Failed() {
-	u.brm.On("FindAll", u.ctx, mock.Anything).Return(nil, errs.NewInternalServerError("something went wrong"))
+	u.expectTx(listTxOptions)
+	u.brm.On("FindAll", u.ctx, mock.Anything, mock.Anything).Return(nil, errs.NewInternalServerError("something went wrong"))
+
+	result, meta, err := u.bs.FindAll(u.ctx, &dto.FindAllBookRequest{})
+
+	u.Nil(result)
+	u.Nil(meta)
+	u.NotNil(err)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestFindAll_PageParams() {
+	req := &dto.FindAllBookRequest{Page: 3, PageSize: 500, Sort: "title,-id", Author: " James Clear ", TitleContains: "habits"}
+	params := &domain.BookListParams{
+		Limit:         100,
+		Offset:        200,
+		Sort:          []domain.SortField{{Field: "title"}, {Field: "id", Desc: true}},
+		Author:        "James Clear",
+		TitleContains: "habits",
+	}
+
+	u.expectTx(listTxOptions)
+	u.brm.On("FindAll", u.ctx, mock.Anything, params).Return([]*domain.Book{{Id: 201, Title: "Atomic Habits", Author: "James Clear"}}, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+	u.brm.On("Count", u.ctx, mock.Anything, params).Return(uint(201), nil)
+	u.brm.On("CountTags", u.ctx, mock.Anything, params).Return(map[uint]uint{}, nil)
+
+	result, meta, err := u.bs.FindAll(u.ctx, req)
+
+	u.Nil(err)
+	u.Len(result, 1)
+	u.Equal(&dto.PaginationMeta{Page: 3, PageSize: 100, TotalCount: 201, TotalPages: 3, Facets: &dto.Facets{Tags: []*dto.TagFacet{}}}, meta)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestFindAll_LimitOffsetParams() {
+	req := &dto.FindAllBookRequest{Page: 7, Limit: 5, Offset: 10}
+	params := &domain.BookListParams{Limit: 5, Offset: 10, Sort: []domain.SortField{}}
+
+	u.expectTx(listTxOptions)
+	u.brm.On("FindAll", u.ctx, mock.Anything, params).Return([]*domain.Book{{Id: 11, Title: "Atomic Habits", Author: "James Clear"}}, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+	u.brm.On("Count", u.ctx, mock.Anything, params).Return(uint(11), nil)
+	u.brm.On("CountTags", u.ctx, mock.Anything, params).Return(map[uint]uint{}, nil)
+
+	_, meta, err := u.bs.FindAll(u.ctx, req)
+
+	u.Nil(err)
+	u.Equal(&dto.PaginationMeta{Page: 3, PageSize: 5, TotalCount: 11, TotalPages: 3, Facets: &dto.Facets{Tags: []*dto.TagFacet{}}}, meta)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestUpdate_Success() {
+	reqDto := &dto.NewBookRequest{Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+	expected := &dto.BookResponse{Id: 2, Title: reqDto.Title, Author: reqDto.Author, Authors: []*dto.AuthorResponse{{Id: 4, Name: reqDto.Author}}, Tags: []*dto.TagResponse{{Id: 7, Name: "self-help"}}}
+
+	u.expectTx(nil)
+	author := u.expectAuthor(4, reqDto.Author)
+	data := &domain.Book{Id: 2, Title: reqDto.Title, Author: reqDto.Author, Authors: []*domain.Author{author}}
+	u.brm.On("Update", u.ctx, u.tx, data).Return(data, nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(2), []uint{4}).Return(nil)
+	// replacing a book keeps its tags
+	u.expectTags(map[uint][]*domain.Tag{2: {{Id: 7, Name: "self-help"}}})
+	u.sm.On("Index", u.ctx, mock.MatchedBy(func(book *domain.Book) bool { return book.Id == data.Id })).Return()
+
+	result, err := u.bs.Update(u.ctx, data.Id, reqDto)
+	u.Nil(err)
+	u.NotNil(result)
+	u.Equal(expected, result)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestUpdate_NotFound() {
+	reqDto := &dto.NewBookRequest{Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+
+	u.expectTx(nil)
+	u.expectAuthor(4, reqDto.Author)
+	u.brm.On("Update", u.ctx, mock.Anything, mock.Anything).Return(nil, errs.NewNotFoundError("data not found"))
+
+	result, err := u.bs.Update(u.ctx, 3, reqDto)
+	u.NotNil(err)
+	u.Nil(result)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestDelete_Success() {
+	u.expectTx(nil)
+	// the book is no longer credited to its authors once it is gone
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(1), []uint(nil)).Return(nil)
+	u.brm.On("Delete", u.ctx, u.tx, uint(1)).Return(nil)
+	u.sm.On("Remove", u.ctx, uint(1)).Return()
 
-	result, err := u.bs.FindAll(u.ctx)
+	err := u.bs.Delete(u.ctx, 1)
+	u.Nil(err)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestDelete_NotFound() {
+	u.expectTx(nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(3), []uint(nil)).Return(nil)
+	u.brm.On("Delete", u.ctx, mock.Anything, uint(3)).Return(errs.NewNotFoundError("data not found"))
+
+	err := u.bs.Delete(u.ctx, 3)
+	u.NotNil(err)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_Success() {
+	existing := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen Covey"}
+	author := "Stephen R. Covey"
+	expected := &dto.BookResponse{Id: existing.Id, Title: existing.Title, Author: author, Authors: []*dto.AuthorResponse{{Id: 6, Name: author}}, Tags: []*dto.TagResponse{}}
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{2: {{Id: 5, Name: "Stephen Covey"}}})
+	u.expectTags(nil)
+	newAuthor := u.expectAuthor(6, author)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(2), []uint{6}).Return(nil)
+	patched := &domain.Book{Id: existing.Id, Title: existing.Title, Author: author, Authors: []*domain.Author{newAuthor}, Tags: []*domain.Tag{}}
+	u.brm.On("Patch", u.ctx, u.tx, patched, []string{"author"}).Return(patched, nil)
+	u.sm.On("Index", u.ctx, patched).Return()
+
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{Author: &author})
+	u.Nil(err)
+	u.Equal(expected, result)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_Details() {
+	isbn, publisher, pages, newPages := "9780441013593", "Chilton Books", 412, 896
+	existing := &domain.Book{Id: 1, Title: "Dune", Author: "Frank Herbert", Isbn: &isbn, Publisher: &publisher, PageCount: &pages}
+	patched := &domain.Book{Id: 1, Title: "Dune", Author: "Frank Herbert", Tags: []*domain.Tag{}, Isbn: &isbn, PageCount: &newPages}
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+	// the unchanged isbn and the removal of an absent language are not written
+	u.brm.On("Patch", u.ctx, u.tx, patched, []string{"publisher", "page_count"}).Return(patched, nil)
+	u.sm.On("Index", u.ctx, patched).Return()
+
+	sameIsbn := isbn
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{
+		Isbn:      &sameIsbn,
+		PageCount: &newPages,
+		Removed:   []string{"publisher", "language"},
+	})
+	u.Nil(err)
+	u.Nil(result.Publisher)
+	u.Equal(newPages, *result.PageCount)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_NoChanges() {
+	existing := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+	title := existing.Title
+	expected := &dto.BookResponse{Id: existing.Id, Title: existing.Title, Author: existing.Author, Authors: []*dto.AuthorResponse{}, Tags: []*dto.TagResponse{}}
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, mock.Anything, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{Title: &title})
+	u.Nil(err)
+	u.Equal(expected, result)
+
+	u.brm.AssertNotCalled(u.T(), "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_EmptyTitle() {
+	existing := &domain.Book{Id: 2, Title: "The 7 Habits of Highly Effective People", Author: "Stephen R. Covey"}
+	title := ""
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, mock.Anything, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{Title: &title})
+	u.Nil(result)
+	u.NotNil(err)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal(apperror.CodeValidationFailed, apperror.CodeOf(err))
+	u.Equal([]*dto.FieldError{{Field: "title", Rule: "required", Message: "title must not be empty"}}, apperror.From(err).FieldErrors())
+
+	u.brm.AssertNotCalled(u.T(), "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_NotFound() {
+	title := "Atomic Habits"
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, mock.Anything, uint(3)).Return(nil, errs.NewNotFoundError("data not found"))
+
+	result, err := u.bs.Patch(u.ctx, 3, &dto.PatchBookRequest{Title: &title})
+	u.Nil(result)
+	u.NotNil(err)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestFindAllByCursor_Success() {
+	cursor := "abc.def"
+	data := []*domain.Book{{Id: 3, Title: "Deep Work", Author: "Cal Newport"}}
+	params := &domain.BookListParams{Limit: 1, Sort: []domain.SortField{{Field: "title"}}}
+
+	u.brm.On("FindAllByCursor", u.ctx, mock.Anything, params, cursor).Return(data, "ghi.jkl", nil)
+	u.expectAuthors(map[uint][]*domain.Author{3: {{Id: 2, Name: "Cal Newport"}}})
+	u.expectTags(nil)
+
+	result, meta, err := u.bs.FindAllByCursor(u.ctx, &dto.FindAllBookRequest{PageSize: 1, Offset: 5, Sort: "title", Cursor: &cursor})
+
+	u.Nil(err)
+	u.Equal([]*dto.BookResponse{{Id: 3, Title: "Deep Work", Author: "Cal Newport", Authors: []*dto.AuthorResponse{{Id: 2, Name: "Cal Newport"}}, Tags: []*dto.TagResponse{}}}, result)
+	u.Equal(&dto.CursorMeta{PageSize: 1, NextCursor: "ghi.jkl"}, meta)
+
+	u.brm.AssertExpectations(u.T())
+}
+
+func (u *unitTestBookServiceSuite) TestFindAllByCursor_InvalidCursor() {
+	cursor := "forged"
+
+	u.brm.On("FindAllByCursor", u.ctx, mock.Anything, mock.Anything, cursor).Return(nil, "", errs.NewBadRequestError("invalid cursor"))
+
+	result, meta, err := u.bs.FindAllByCursor(u.ctx, &dto.FindAllBookRequest{Cursor: &cursor})
 
 	u.Nil(result)
+	u.Nil(meta)
 	u.NotNil(err)
 
 	u.brm.AssertExpectations(u.T())
 }
+
+func (u *unitTestBookServiceSuite) TestCreate_RepositoryErrors() {
+	tests := []struct {
+		kind   error
+		status int
+		code   apperror.Code
+	}{
+		{repository.ErrUniqueViolation, http.StatusConflict, apperror.CodeAlreadyExists},
+		{repository.ErrForeignKeyViolation, http.StatusConflict, apperror.CodeInvalidReference},
+		{repository.ErrSerializationFailure, http.StatusConflict, apperror.CodeConcurrentUpdate},
+		{repository.ErrConnectionLost, http.StatusServiceUnavailable, apperror.CodeServiceUnavailable},
+		{repository.ErrTimeout, http.StatusGatewayTimeout, apperror.CodeTimeout},
+		{nil, http.StatusInternalServerError, apperror.CodeInternal},
+	}
+
+	for _, test := range tests {
+		repoErr := &repository.Error{Op: "CreateBook", Kind: test.kind, Err: errors.New("driver error")}
+
+		brm := mocks.NewBookRepository(u.T())
+		brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(nil, repoErr)
+
+		arm := mocks.NewAuthorRepository(u.T())
+		arm.On("Ensure", u.ctx, mock.Anything, "Frank Herbert").Return(&domain.Author{Id: 1, Name: "Frank Herbert"}, nil)
+
+		if test.kind == repository.ErrUniqueViolation {
+			brm.On("FindDuplicate", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "FindDuplicateBook", Kind: repository.ErrNotFound})
+		}
+
+		_, _, err := NewBookServiceImpl(brm, arm, mocks.NewTagRepository(u.T()), mocks.NewBookSearcher(u.T()), nil, repository.NewMemoryTxManager(), u.p, config.Default()).Create(u.ctx, &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"}, false)
+
+		u.Equal(test.status, err.StatusCode())
+		u.Equal(test.code, apperror.CodeOf(err))
+		u.ErrorIs(err.(error), repoErr)
+	}
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_CommitFailed() {
+	title := "Atomic Habits"
+
+	u.tmm.On("WithinTx", u.ctx, (*sql.TxOptions)(nil), mock.Anything).Return(&repository.Error{Op: "CommitTx", Kind: repository.ErrSerializationFailure})
+
+	result, err := u.bs.Patch(u.ctx, 3, &dto.PatchBookRequest{Title: &title})
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(apperror.CodeConcurrentUpdate, apperror.CodeOf(err))
+}
+
+func (u *unitTestBookServiceSuite) TestDelete_OtherErrorsUnchanged() {
+	notFound := errs.NewNotFoundError("data not found")
+
+	u.expectTx(nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(3), []uint(nil)).Return(nil)
+	u.brm.On("Delete", u.ctx, mock.Anything, uint(3)).Return(notFound)
+
+	u.Same(notFound, u.bs.Delete(u.ctx, 3))
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_Duplicate() {
+	existing := &domain.Book{Id: 7, Title: "Dune", Author: "Frank Herbert"}
+
+	u.expectTx(nil)
+	author := u.expectAuthor(1, "Frank Herbert")
+	u.brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "CreateBook", Kind: repository.ErrUniqueViolation})
+	u.brm.On("FindDuplicate", u.ctx, mock.Anything, &domain.Book{Title: "dune", Author: "Frank Herbert", Authors: []*domain.Author{author}}).Return(existing, nil)
+
+	result, created, err := u.bs.Create(u.ctx, &dto.NewBookRequest{Title: "dune", Author: "Frank Herbert"}, false)
+
+	u.Nil(result)
+	u.False(created)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(apperror.CodeAlreadyExists, apperror.CodeOf(err))
+	u.Equal(map[string]any{"existing_id": uint(7)}, apperror.From(err).Extensions())
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_Upsert() {
+	existing := &domain.Book{Id: 7, Title: "Dune", Author: "Frank Herbert"}
+
+	u.expectTx(nil)
+	u.expectAuthor(1, "Frank Herbert")
+	u.brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(nil, &repository.Error{Op: "CreateBook", Kind: repository.ErrUniqueViolation})
+	u.brm.On("FindDuplicate", u.ctx, mock.Anything, mock.Anything).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{7: {{Id: 1, Name: "Frank Herbert"}}})
+	u.expectTags(nil)
+
+	result, created, err := u.bs.Create(u.ctx, &dto.NewBookRequest{Title: "dune", Author: "Frank Herbert"}, true)
+
+	u.Nil(err)
+	u.False(created)
+	u.Equal(&dto.BookResponse{Id: 7, Title: "Dune", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{{Id: 1, Name: "Frank Herbert"}}, Tags: []*dto.TagResponse{}}, result)
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_UpsertCreates() {
+	u.expectTx(nil)
+	u.expectAuthor(2, "Jane Austen")
+	u.brm.On("Create", u.ctx, mock.Anything, mock.Anything).Return(createdWithId(8), nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(8), []uint{2}).Return(nil)
+	u.sm.On("Index", u.ctx, mock.Anything).Return()
+
+	result, created, err := u.bs.Create(u.ctx, &dto.NewBookRequest{Title: "Emma", Author: "Jane Austen"}, true)
+
+	u.Nil(err)
+	u.True(created)
+	u.Equal(uint(8), result.Id)
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_Duplicate() {
+	title := "Emma"
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(3)).Return(&domain.Book{Id: 3, Title: "Dune", Author: "Jane Austen"}, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+	u.brm.On("Patch", u.ctx, u.tx, mock.Anything, []string{"title"}).Return(nil, &repository.Error{Op: "PatchBook", Kind: repository.ErrUniqueViolation})
+	u.brm.On("FindDuplicate", u.ctx, mock.Anything, &domain.Book{Id: 3, Title: "Emma", Author: "Jane Austen", Tags: []*domain.Tag{}}).Return(&domain.Book{Id: 5, Title: "Emma", Author: "Jane Austen"}, nil)
+
+	result, err := u.bs.Patch(u.ctx, 3, &dto.PatchBookRequest{Title: &title})
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(map[string]any{"existing_id": uint(5)}, apperror.From(err).Extensions())
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_AuthorIds() {
+	reqDto := &dto.NewBookRequest{Title: "Good Omens", AuthorIds: []uint{3, 2}}
+	authors := []*domain.Author{{Id: 2, Name: "Neil Gaiman"}, {Id: 3, Name: "Terry Pratchett"}}
+
+	u.expectTx(nil)
+	u.arm.On("FindByIds", u.ctx, u.tx, []uint{3, 2}).Return(authors, nil)
+	u.brm.On("Create", u.ctx, u.tx, &domain.Book{Title: "Good Omens", Author: "Terry Pratchett, Neil Gaiman", Authors: []*domain.Author{authors[1], authors[0]}}).Return(createdWithId(9), nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(9), []uint{3, 2}).Return(nil)
+	u.sm.On("Index", u.ctx, mock.Anything).Return()
+
+	result, _, err := u.bs.Create(u.ctx, reqDto, false)
+
+	u.Nil(err)
+	u.Equal(&dto.BookResponse{
+		Id:      9,
+		Title:   "Good Omens",
+		Author:  "Terry Pratchett, Neil Gaiman",
+		Authors: []*dto.AuthorResponse{{Id: 3, Name: "Terry Pratchett"}, {Id: 2, Name: "Neil Gaiman"}},
+		Tags:    []*dto.TagResponse{},
+	}, result)
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_UnknownAuthorId() {
+	u.expectTx(nil)
+	u.arm.On("FindByIds", u.ctx, u.tx, []uint{2, 8}).Return([]*domain.Author{{Id: 2, Name: "Neil Gaiman"}}, nil)
+
+	result, _, err := u.bs.Create(u.ctx, &dto.NewBookRequest{Title: "Good Omens", AuthorIds: []uint{2, 8}}, false)
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal([]*dto.FieldError{{Field: "author_ids[1]", Rule: "exists", Message: "author 8 does not exist"}}, apperror.From(err).FieldErrors())
+}
+
+func (u *unitTestBookServiceSuite) TestCreate_BylineTooLong() {
+	authors := []*domain.Author{{Id: 1, Name: strings.Repeat("a", 200)}, {Id: 2, Name: strings.Repeat("b", 200)}}
+
+	u.expectTx(nil)
+	u.arm.On("FindByIds", u.ctx, u.tx, []uint{1, 2}).Return(authors, nil)
+
+	result, _, err := u.bs.Create(u.ctx, &dto.NewBookRequest{Title: "Anthology", AuthorIds: []uint{1, 2}}, false)
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_AuthorIds() {
+	existing := &domain.Book{Id: 4, Title: "Good Omens", Author: "Terry Pratchett"}
+	authors := []*domain.Author{{Id: 2, Name: "Neil Gaiman"}, {Id: 3, Name: "Terry Pratchett"}}
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{4: {authors[1]}})
+	u.expectTags(nil)
+	u.arm.On("FindByIds", u.ctx, u.tx, []uint{3, 2}).Return(authors, nil)
+	u.arm.On("SetBookAuthors", u.ctx, u.tx, uint(4), []uint{3, 2}).Return(nil)
+	u.brm.On("Patch", u.ctx, u.tx, mock.Anything, []string{"author"}).Return(existing, nil)
+	u.sm.On("Index", u.ctx, mock.Anything).Return()
+
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{AuthorIds: []uint{3, 2}})
+
+	u.Nil(err)
+	u.Equal("Terry Pratchett, Neil Gaiman", result.Author)
+	u.Equal([]*dto.AuthorResponse{{Id: 3, Name: "Terry Pratchett"}, {Id: 2, Name: "Neil Gaiman"}}, result.Authors)
+}
+
+func (u *unitTestBookServiceSuite) TestPatch_SameAuthors() {
+	existing := &domain.Book{Id: 4, Title: "Dune", Author: "Frank Herbert"}
+	author := &domain.Author{Id: 1, Name: "Frank Herbert"}
+	name := "frank herbert"
+
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, existing.Id).Return(existing, nil)
+	u.expectAuthors(map[uint][]*domain.Author{4: {author}})
+	u.expectTags(nil)
+	// the name matches the stored author, so neither the links nor the byline change
+	u.arm.On("Ensure", u.ctx, u.tx, name).Return(author, nil)
+
+	result, err := u.bs.Patch(u.ctx, existing.Id, &dto.PatchBookRequest{Author: &name})
+
+	u.Nil(err)
+	u.Equal("Frank Herbert", result.Author)
+}
+
+func (u *unitTestBookServiceSuite) TestFindAll_TagFilterAll() {
+	params := &domain.BookListParams{Limit: 20, Sort: []domain.SortField{}, TagIds: [][]uint{{1, 4}, {9}}}
+
+	u.expectTx(listTxOptions)
+	u.trm.On("FindByNames", u.ctx, u.tx, []string{"fiction"}).Return([]*domain.Tag{{Id: 1, Name: "Fiction"}}, nil)
+	// fantasy is nested under fiction, so books tagged fantasy match too
+	u.trm.On("FindSubtreeIds", u.ctx, u.tx, []uint{1}).Return([]uint{1, 4}, nil)
+	u.trm.On("FindByNames", u.ctx, u.tx, []string{"classic"}).Return([]*domain.Tag{{Id: 9, Name: "Classic"}}, nil)
+	u.trm.On("FindSubtreeIds", u.ctx, u.tx, []uint{9}).Return([]uint{9}, nil)
+	u.brm.On("FindAll", u.ctx, u.tx, params).Return([]*domain.Book{{Id: 3, Title: "The Hobbit", Author: "J.R.R. Tolkien"}}, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.brm.On("FindTagIds", u.ctx, u.tx, []uint{3}).Return(map[uint][]uint{3: {4, 9}}, nil)
+	u.trm.On("FindByIds", u.ctx, u.tx, []uint{4, 9}).Return([]*domain.Tag{{Id: 9, Name: "Classic"}, {Id: 4, Name: "Fantasy", ParentId: &[]uint{1}[0]}}, nil)
+	u.brm.On("Count", u.ctx, u.tx, params).Return(uint(1), nil)
+	u.brm.On("CountTags", u.ctx, u.tx, params).Return(map[uint]uint{4: 1, 9: 1}, nil)
+
+	result, meta, err := u.bs.FindAll(u.ctx, &dto.FindAllBookRequest{Tag: "fiction, ,classic"})
+
+	u.Nil(err)
+	u.Len(result, 1)
+	u.Equal([]string{"Classic", "Fantasy"}, []string{result[0].Tags[0].Name, result[0].Tags[1].Name})
+	u.Equal(&dto.Facets{Tags: []*dto.TagFacet{{Id: 9, Name: "Classic", Count: 1}, {Id: 4, Name: "Fantasy", Count: 1}}}, meta.Facets)
+}
+
+func (u *unitTestBookServiceSuite) TestFindAll_TagFilterAny() {
+	params := &domain.BookListParams{Limit: 20, Sort: []domain.SortField{}, TagIds: [][]uint{{9}}}
+
+	u.expectTx(listTxOptions)
+	// unknown tags match nothing, any of the others is enough
+	u.trm.On("FindByNames", u.ctx, u.tx, []string{"classic", "unknown"}).Return([]*domain.Tag{{Id: 9, Name: "Classic"}}, nil)
+	u.trm.On("FindSubtreeIds", u.ctx, u.tx, []uint{9}).Return([]uint{9}, nil)
+	u.brm.On("FindAll", u.ctx, u.tx, params).Return([]*domain.Book{}, nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+	u.brm.On("Count", u.ctx, u.tx, params).Return(uint(0), nil)
+	u.brm.On("CountTags", u.ctx, u.tx, params).Return(map[uint]uint{}, nil)
+
+	result, meta, err := u.bs.FindAll(u.ctx, &dto.FindAllBookRequest{Tag: "classic,unknown", TagMode: "any"})
+
+	u.Nil(err)
+	u.Empty(result)
+	u.Equal(&dto.Facets{Tags: []*dto.TagFacet{}}, meta.Facets)
+}
+
+func (u *unitTestBookServiceSuite) TestFindAll_TooManyTags() {
+	u.expectTx(listTxOptions)
+
+	result, _, err := u.bs.FindAll(u.ctx, &dto.FindAllBookRequest{Tag: strings.Repeat("a,", maxTagFilters+1)})
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal(apperror.CodeInvalidQuery, apperror.CodeOf(err))
+}
+
+func (u *unitTestBookServiceSuite) TestFindAllByCursor_TagFilter() {
+	params := &domain.BookListParams{Limit: 20, Sort: []domain.SortField{}, TagIds: [][]uint{{}}}
+
+	u.trm.On("FindByNames", u.ctx, mock.Anything, []string{"unknown"}).Return([]*domain.Tag{}, nil)
+	u.trm.On("FindSubtreeIds", u.ctx, mock.Anything, []uint{}).Return([]uint{}, nil)
+	u.brm.On("FindAllByCursor", u.ctx, mock.Anything, params, "").Return([]*domain.Book{}, "", nil)
+	u.expectAuthors(map[uint][]*domain.Author{})
+	u.expectTags(nil)
+
+	result, _, err := u.bs.FindAllByCursor(u.ctx, &dto.FindAllBookRequest{Tag: "unknown"})
+
+	u.Nil(err)
+	u.Empty(result)
+}
+
+func (u *unitTestBookServiceSuite) TestAddTag_Success() {
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(3)).Return(&domain.Book{Id: 3, Title: "Dune", Author: "Frank Herbert"}, nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(9)).Return(&domain.Tag{Id: 9, Name: "Classic"}, nil)
+	u.brm.On("AddTag", u.ctx, u.tx, uint(3), uint(9)).Return(nil)
+	u.expectAuthors(map[uint][]*domain.Author{3: {{Id: 1, Name: "Frank Herbert"}}})
+	u.expectTags(map[uint][]*domain.Tag{3: {{Id: 9, Name: "Classic"}}})
+
+	result, err := u.bs.AddTag(u.ctx, 3, 9)
+
+	u.Nil(err)
+	u.Equal([]*dto.TagResponse{{Id: 9, Name: "Classic"}}, result.Tags)
+}
+
+func (u *unitTestBookServiceSuite) TestRemoveTag_UnknownTag() {
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(3)).Return(&domain.Book{Id: 3, Title: "Dune", Author: "Frank Herbert"}, nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(9)).Return(nil, &repository.Error{Op: "FindOneTagById", Kind: repository.ErrNotFound})
+
+	result, err := u.bs.RemoveTag(u.ctx, 3, 9)
+
+	u.Nil(result)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+	u.brm.AssertNotCalled(u.T(), "RemoveTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookServiceSuite) TestAddTag_TagLookupFailed() {
+	u.expectTx(nil)
+	u.brm.On("FindOneById", u.ctx, u.tx, uint(3)).Return(&domain.Book{Id: 3, Title: "Dune", Author: "Frank Herbert"}, nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(9)).Return(nil, &repository.Error{Op: "FindOneTagById", Kind: repository.ErrSerializationFailure})
+
+	result, err := u.bs.AddTag(u.ctx, 3, 9)
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal("the tag was changed concurrently, retry the request", err.Message())
+}
+
+func (u *unitTestBookServiceSuite) TestSearch_Success() {
+	params := &domain.BookSearchParams{Query: "dune herbrt", Limit: 1, Offset: 1}
+
+	u.sm.On("Search", u.ctx, mock.Anything, params).Return([]*domain.BookSearchHit{{
+		Book:      &domain.Book{Id: 3, Title: "Dune Messiah", Author: "Frank Herbert"},
+		Score:     1.5,
+		Highlight: domain.BookHighlight{Title: "<mark>Dune</mark> Messiah", Author: "Frank <mark>Herbert</mark>"},
+	}}, uint(2), nil)
+	u.expectAuthors(map[uint][]*domain.Author{3: {{Id: 1, Name: "Frank Herbert"}}})
+	u.expectTags(nil)
+
+	result, meta, err := u.bs.Search(u.ctx, &dto.SearchBookRequest{Q: "dune herbrt", Page: 2, PageSize: 1})
+
+	u.Nil(err)
+	u.Equal([]*dto.BookSearchHitResponse{{
+		Book:      &dto.BookResponse{Id: 3, Title: "Dune Messiah", Author: "Frank Herbert", Authors: []*dto.AuthorResponse{{Id: 1, Name: "Frank Herbert"}}, Tags: []*dto.TagResponse{}},
+		Score:     1.5,
+		Highlight: &dto.BookHighlightResponse{Title: "<mark>Dune</mark> Messiah", Author: "Frank <mark>Herbert</mark>"},
+	}}, result)
+	u.Equal(&dto.PaginationMeta{Page: 2, PageSize: 1, TotalCount: 2, TotalPages: 2}, meta)
+}
+
+func (u *unitTestBookServiceSuite) TestSearch_NoWords() {
+	u.sm.On("Search", u.ctx, mock.Anything, mock.Anything).Return(nil, uint(0), apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, "q must contain at least one letter or digit"))
+
+	result, meta, err := u.bs.Search(u.ctx, &dto.SearchBookRequest{Q: "--"})
+
+	u.Nil(result)
+	u.Nil(meta)
+	u.Equal(apperror.CodeInvalidQuery, apperror.CodeOf(err))
+}
+
+func (u *unitTestBookServiceSuite) TestPolicy_Reader() {
+	ctx := authtest.WithRoles(context.Background(), "reader")
+
+	_, _, err := u.bs.Create(ctx, &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"}, false)
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.Equal(apperror.CodeForbidden, apperror.CodeOf(err))
+	u.Equal(map[string]any{"permission": auth.PermissionWriteBooks}, apperror.From(err).Extensions())
+
+	_, err = u.bs.AddTag(ctx, 1, 2)
+	u.Equal(http.StatusForbidden, err.StatusCode())
+
+	u.brm.On("FindOneById", ctx, mock.Anything, uint(1)).Return(nil, &repository.Error{Op: "FindOneBookById", Kind: repository.ErrNotFound})
+
+	// reading is allowed, the book just does not exist
+	_, err = u.bs.FindOneById(ctx, 1)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+}
+
+func (u *unitTestBookServiceSuite) TestPolicy_EditorCannotDelete() {
+	err := u.bs.Delete(authtest.WithRoles(context.Background(), "editor"), 1)
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.tmm.AssertNotCalled(u.T(), "WithinTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestBookServiceSuite) TestPolicy_Anonymous() {
+	// anonymous requests act as reader by default
+	u.brm.On("FindOneById", context.Background(), mock.Anything, uint(1)).Return(nil, &repository.Error{Op: "FindOneBookById", Kind: repository.ErrNotFound})
+
+	_, err := u.bs.FindOneById(context.Background(), 1)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+
+	_, err = u.bs.Update(context.Background(), 1, &dto.NewBookRequest{Title: "Dune", Author: "Frank Herbert"})
+	u.Equal(http.StatusUnauthorized, err.StatusCode())
+}
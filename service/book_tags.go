@@ -0,0 +1,222 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// maxTagFilters bounds the tags a listing can be filtered on, each one costs a subquery.
+const maxTagFilters = 10
+
+// parseTagNames splits the comma separated tag filter, dropping empty names.
+func parseTagNames(tag string) []string {
+	names := []string{}
+
+	for _, name := range strings.Split(tag, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// tagFilter turns the tag filter of req into groups of tag ids for the repository. A book
+// matches a tag when it carries the tag or one nested under it, so each group holds a whole
+// subtree. Unknown tags match no book.
+func (b *bookServiceImpl) tagFilter(ctx context.Context, db repository.DBTX, req *dto.FindAllBookRequest) ([][]uint, errs.CustomError) {
+	names := parseTagNames(req.Tag)
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if len(names) > maxTagFilters {
+		return nil, apperror.New(http.StatusUnprocessableEntity, apperror.CodeInvalidQuery, fmt.Sprintf("tag accepts at most %d tags", maxTagFilters))
+	}
+
+	if req.TagMode == "any" {
+		subtree, err := b.tagSubtree(ctx, db, names)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]uint{subtree}, nil
+	}
+
+	groups := make([][]uint, 0, len(names))
+
+	for _, name := range names {
+		subtree, err := b.tagSubtree(ctx, db, []string{name})
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, subtree)
+	}
+
+	return groups, nil
+}
+
+// tagSubtree returns the ids of the tags named by names and of every tag nested under them.
+func (b *bookServiceImpl) tagSubtree(ctx context.Context, db repository.DBTX, names []string) ([]uint, errs.CustomError) {
+	tags, err := b.tr.FindByNames(ctx, db, names)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		tagIds = append(tagIds, tag.Id)
+	}
+
+	return b.tr.FindSubtreeIds(ctx, db, tagIds)
+}
+
+// loadTags fills in the tags of books, ordered by name.
+func (b *bookServiceImpl) loadTags(ctx context.Context, db repository.DBTX, books ...*domain.Book) errs.CustomError {
+	bookIds := make([]uint, 0, len(books))
+
+	for _, book := range books {
+		bookIds = append(bookIds, book.Id)
+	}
+
+	tagIds, err := b.br.FindTagIds(ctx, db, bookIds)
+	if err != nil {
+		return err
+	}
+
+	allTagIds := []uint{}
+	for _, ids := range tagIds {
+		allTagIds = append(allTagIds, ids...)
+	}
+
+	tags, err := b.findTags(ctx, db, allTagIds)
+	if err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		book.Tags = []*domain.Tag{}
+
+		for _, tagId := range tagIds[book.Id] {
+			if tag, ok := tags[tagId]; ok {
+				book.Tags = append(book.Tags, tag)
+			}
+		}
+
+		slices.SortFunc(book.Tags, func(a, b *domain.Tag) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+	}
+
+	return nil
+}
+
+// tagFacets counts the listed books carrying each tag, most used tags first.
+func (b *bookServiceImpl) tagFacets(ctx context.Context, db repository.DBTX, params *domain.BookListParams) ([]*dto.TagFacet, errs.CustomError) {
+	counts, err := b.br.CountTags(ctx, db, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]uint, 0, len(counts))
+	for tagId := range counts {
+		tagIds = append(tagIds, tagId)
+	}
+
+	tags, err := b.findTags(ctx, db, tagIds)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make([]*dto.TagFacet, 0, len(tags))
+
+	for tagId, tag := range tags {
+		facets = append(facets, &dto.TagFacet{Id: tagId, Name: tag.Name, Count: counts[tagId]})
+	}
+
+	slices.SortFunc(facets, func(a, b *dto.TagFacet) int {
+		if order := cmp.Compare(b.Count, a.Count); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return facets, nil
+}
+
+// findTags looks the tags with tagIds up by id, duplicates in tagIds are looked up once.
+func (b *bookServiceImpl) findTags(ctx context.Context, db repository.DBTX, tagIds []uint) (map[uint]*domain.Tag, errs.CustomError) {
+	if len(tagIds) == 0 {
+		return map[uint]*domain.Tag{}, nil
+	}
+
+	slices.Sort(tagIds)
+
+	found, err := b.tr.FindByIds(ctx, db, slices.Compact(tagIds))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[uint]*domain.Tag, len(found))
+	for _, tag := range found {
+		tags[tag.Id] = tag
+	}
+
+	return tags, nil
+}
+
+func (b *bookServiceImpl) AddTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError) {
+	return b.changeTag(ctx, bookId, tagId, b.br.AddTag)
+}
+
+func (b *bookServiceImpl) RemoveTag(ctx context.Context, bookId uint, tagId uint) (*dto.BookResponse, errs.CustomError) {
+	return b.changeTag(ctx, bookId, tagId, b.br.RemoveTag)
+}
+
+// changeTag tags or untags the book with change, and returns the book as it is afterwards.
+func (b *bookServiceImpl) changeTag(ctx context.Context, bookId uint, tagId uint, change func(ctx context.Context, db repository.DBTX, bookId uint, tagId uint) errs.CustomError) (*dto.BookResponse, errs.CustomError) {
+	if err := b.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	var result *domain.Book
+
+	err := b.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		book, err := b.br.FindOneById(ctx, tx, bookId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := b.tr.FindOneById(ctx, tx, tagId); err != nil {
+			return fromRepository(err, "tag")
+		}
+
+		if err := change(ctx, tx, bookId, tagId); err != nil {
+			return err
+		}
+
+		result = book
+
+		return b.loadRelations(ctx, tx, book)
+	})
+
+	if err != nil {
+		return nil, fromRepository(err, "book")
+	}
+
+	return newBookResponse(result), nil
+}
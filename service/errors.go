@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"gin-go-testing/apperror"
+	"gin-go-testing/repository"
+	"net/http"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+// fromRepository translates a repository error about resource, e.g. "book", into what the
+// client is told, keeping it as the cause. Any other error is returned as it is.
+func fromRepository(err errs.CustomError, resource string) errs.CustomError {
+	repoErr, ok := err.(*repository.Error)
+	if !ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(repoErr, repository.ErrNotFound):
+		return apperror.Wrap(http.StatusNotFound, apperror.CodeNotFound, "data not found", repoErr)
+	case errors.Is(repoErr, repository.ErrUniqueViolation):
+		return apperror.Wrap(http.StatusConflict, apperror.CodeAlreadyExists, "the "+resource+" already exists", repoErr)
+	case errors.Is(repoErr, repository.ErrForeignKeyViolation):
+		return apperror.Wrap(http.StatusConflict, apperror.CodeInvalidReference, "the "+resource+" refers to a resource that does not exist", repoErr)
+	case errors.Is(repoErr, repository.ErrSerializationFailure):
+		return apperror.Wrap(http.StatusConflict, apperror.CodeConcurrentUpdate, "the "+resource+" was changed concurrently, retry the request", repoErr)
+	case errors.Is(repoErr, repository.ErrConnectionLost):
+		return apperror.Wrap(http.StatusServiceUnavailable, apperror.CodeServiceUnavailable, "the database is unavailable, retry later", repoErr)
+	case errors.Is(repoErr, repository.ErrTimeout):
+		return apperror.Wrap(http.StatusGatewayTimeout, apperror.CodeTimeout, "the database did not answer in time", repoErr)
+	}
+
+	return apperror.Wrap(http.StatusInternalServerError, apperror.CodeInternal, "something went wrong", repoErr)
+}
+
+// withExistingId translates err like fromRepository, adding to a unique violation the id
+// of the resource that already exists, found by lookup. lookup only runs for unique
+// violations and reports false when it finds none.
+func withExistingId(err errs.CustomError, resource string, lookup func() (uint, bool)) errs.CustomError {
+	if !isUniqueViolation(err) {
+		return fromRepository(err, resource)
+	}
+
+	existingId, ok := lookup()
+	if !ok {
+		return fromRepository(err, resource)
+	}
+
+	return apperror.From(fromRepository(err, resource)).With("existing_id", existingId)
+}
+
+func isUniqueViolation(err errs.CustomError) bool {
+	repoErr, ok := err.(*repository.Error)
+
+	return ok && errors.Is(repoErr, repository.ErrUniqueViolation)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/mocks"
+	"gin-go-testing/repository"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// serviceSuite is embedded by the service suites, it holds the context of the call under test
+// and the mocked TxManager with the transaction it hands out.
+type serviceSuite struct {
+	suite.Suite
+	ctx context.Context
+	tmm *mocks.TxManager
+	tx  *sql.Tx
+}
+
+// expectTx makes the mocked TxManager run its callback with u.tx.
+func (u *serviceSuite) expectTx(opts *sql.TxOptions) {
+	u.tmm.On("WithinTx", u.ctx, opts, mock.Anything).Return(func(ctx context.Context, opts *sql.TxOptions, fn func(tx repository.DBTX) errs.CustomError) errs.CustomError {
+		return fn(u.tx)
+	})
+}
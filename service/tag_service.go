@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"gin-go-testing/model/dto"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type TagService interface {
+	Create(ctx context.Context, tagDto *dto.NewTagRequest) (*dto.TagResponse, errs.CustomError)
+	FindOneById(ctx context.Context, tagId uint) (*dto.TagResponse, errs.CustomError)
+	FindAll(ctx context.Context, req *dto.FindAllTagRequest) ([]*dto.TagResponse, *dto.PaginationMeta, errs.CustomError)
+	// Update renames a tag or moves it under another parent, never under itself or a tag
+	// nested under it.
+	Update(ctx context.Context, tagId uint, tagDto *dto.NewTagRequest) (*dto.TagResponse, errs.CustomError)
+	// Delete removes a tag from every book and then the tag, refused while tags are nested
+	// under it.
+	Delete(ctx context.Context, tagId uint) errs.CustomError
+}
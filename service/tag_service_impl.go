@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/config"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/rulyadhika/go-custom-err/errs"
+)
+
+type tagServiceImpl struct {
+	tr  repository.TagRepository
+	br  repository.BookRepository
+	db  *sql.DB
+	tm  repository.TxManager
+	p   auth.Policy
+	cfg *config.Config
+}
+
+func NewTagServiceImpl(tr repository.TagRepository, br repository.BookRepository, db *sql.DB, tm repository.TxManager, p auth.Policy, cfg *config.Config) TagService {
+	return &tagServiceImpl{tr, br, db, tm, p, cfg}
+}
+
+func (t *tagServiceImpl) Create(ctx context.Context, tagDto *dto.NewTagRequest) (*dto.TagResponse, errs.CustomError) {
+	if err := t.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{Name: tagDto.Name, ParentId: tagDto.ParentId}
+
+	err := t.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := t.checkParent(ctx, tx, tag); err != nil {
+			return err
+		}
+
+		_, err := t.tr.Create(ctx, tx, tag)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, withExistingId(err, "tag", t.namesakeOf(ctx, tag))
+	}
+
+	return newTagResponse(tag), nil
+}
+
+func (t *tagServiceImpl) FindOneById(ctx context.Context, tagId uint) (*dto.TagResponse, errs.CustomError) {
+	if err := t.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+
+	result, err := t.tr.FindOneById(ctx, t.db, tagId)
+
+	if err != nil {
+		return nil, fromRepository(err, "tag")
+	}
+
+	return newTagResponse(result), nil
+}
+
+func (t *tagServiceImpl) FindAll(ctx context.Context, req *dto.FindAllTagRequest) ([]*dto.TagResponse, *dto.PaginationMeta, errs.CustomError) {
+	if err := t.p.Authorize(ctx, auth.PermissionReadBooks); err != nil {
+		return nil, nil, err
+	}
+
+	params := &domain.TagListParams{NameContains: strings.TrimSpace(req.NameContains)}
+
+	var meta *dto.PaginationMeta
+	params.Limit, params.Offset, meta = newPage(req.Page, req.PageSize, &t.cfg.Pagination)
+
+	var result []*domain.Tag
+	var total uint
+
+	err := t.tm.WithinTx(ctx, listTxOptions, func(tx repository.DBTX) errs.CustomError {
+		var err errs.CustomError
+
+		result, err = t.tr.FindAll(ctx, tx, params)
+
+		if err != nil {
+			return err
+		}
+
+		total, err = t.tr.Count(ctx, tx, params)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, fromRepository(err, "tag")
+	}
+
+	setTotal(meta, total)
+
+	tagsDto := []*dto.TagResponse{}
+
+	for _, e := range result {
+		tagsDto = append(tagsDto, newTagResponse(e))
+	}
+
+	return tagsDto, meta, nil
+}
+
+func (t *tagServiceImpl) Update(ctx context.Context, tagId uint, tagDto *dto.NewTagRequest) (*dto.TagResponse, errs.CustomError) {
+	if err := t.p.Authorize(ctx, auth.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{Id: tagId, Name: tagDto.Name, ParentId: tagDto.ParentId}
+
+	err := t.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := t.checkParent(ctx, tx, tag); err != nil {
+			return err
+		}
+
+		_, err := t.tr.Update(ctx, tx, tag)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, withExistingId(err, "tag", t.namesakeOf(ctx, tag))
+	}
+
+	return newTagResponse(tag), nil
+}
+
+func (t *tagServiceImpl) Delete(ctx context.Context, tagId uint) errs.CustomError {
+	if err := t.p.Authorize(ctx, auth.PermissionDeleteBooks); err != nil {
+		return err
+	}
+
+	err := t.tm.WithinTx(ctx, nil, func(tx repository.DBTX) errs.CustomError {
+		if err := t.tr.Delete(ctx, tx, tagId); err != nil {
+			return err
+		}
+
+		// the database cascades the delete to book_tags, the in-memory repository has to be told
+		return t.br.RemoveTagFromBooks(ctx, tx, tagId)
+	})
+
+	if repoErr, ok := err.(*repository.Error); ok && errors.Is(repoErr, repository.ErrForeignKeyViolation) {
+		return apperror.Wrap(http.StatusConflict, apperror.CodeInUse, "other tags are nested under the tag, move or delete them first", repoErr)
+	}
+
+	if err != nil {
+		return fromRepository(err, "tag")
+	}
+
+	return nil
+}
+
+// checkParent makes sure the parent of tag exists and is neither the tag itself nor nested
+// under it, which would make a cycle.
+func (t *tagServiceImpl) checkParent(ctx context.Context, tx repository.DBTX, tag *domain.Tag) errs.CustomError {
+	if tag.ParentId == nil {
+		return nil
+	}
+
+	parentId := *tag.ParentId
+
+	if _, err := t.tr.FindOneById(ctx, tx, parentId); err != nil {
+		if repoErr, ok := err.(*repository.Error); ok && errors.Is(repoErr, repository.ErrNotFound) {
+			return apperror.NewValidationError([]*dto.FieldError{{
+				Field:   "parent_id",
+				Rule:    "exists",
+				Message: fmt.Sprintf("tag %d does not exist", parentId),
+			}})
+		}
+
+		return err
+	}
+
+	// a new tag has nothing nested under it yet
+	if tag.Id == 0 {
+		return nil
+	}
+
+	subtree, err := t.tr.FindSubtreeIds(ctx, tx, []uint{tag.Id})
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(subtree, parentId) {
+		return apperror.NewValidationError([]*dto.FieldError{{
+			Field:   "parent_id",
+			Rule:    "cycle",
+			Message: "a tag cannot be nested under itself or a tag nested under it",
+		}})
+	}
+
+	return nil
+}
+
+// namesakeOf looks up the id of the tag with the same name as tag.
+func (t *tagServiceImpl) namesakeOf(ctx context.Context, tag *domain.Tag) func() (uint, bool) {
+	return func() (uint, bool) {
+		existing, err := t.tr.FindByNames(ctx, t.db, []string{tag.Name})
+		if err != nil || len(existing) == 0 {
+			return 0, false
+		}
+
+		return existing[0].Id, true
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"gin-go-testing/apperror"
+	"gin-go-testing/auth"
+	"gin-go-testing/auth/authtest"
+	"gin-go-testing/config"
+	"gin-go-testing/mocks"
+	"gin-go-testing/model/domain"
+	"gin-go-testing/model/dto"
+	"gin-go-testing/repository"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTagServiceSuite struct {
+	serviceSuite
+	trm *mocks.TagRepository
+	brm *mocks.BookRepository
+	ts  TagService
+}
+
+func TestUnitTestTagService(t *testing.T) {
+	suite.Run(t, &unitTestTagServiceSuite{})
+}
+
+func (u *unitTestTagServiceSuite) SetupTest() {
+	db, _, _ := sqlmock.New()
+
+	u.trm = mocks.NewTagRepository(u.T())
+	u.brm = mocks.NewBookRepository(u.T())
+	u.tmm = mocks.NewTxManager(u.T())
+	u.tx = &sql.Tx{}
+
+	policy, err := auth.NewPolicyImpl(config.Default())
+	u.Require().NoError(err)
+
+	u.ts = NewTagServiceImpl(u.trm, u.brm, db, u.tmm, policy, config.Default())
+
+	// admins may do anything, the tests of the policy run as other roles
+	u.ctx = authtest.WithRoles(context.Background(), "admin")
+}
+
+func parentId(id uint) *uint {
+	return &id
+}
+
+func (u *unitTestTagServiceSuite) TestCreate_Success() {
+	tag := &domain.Tag{Name: "Fantasy", ParentId: parentId(1)}
+
+	u.expectTx(nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(1)).Return(&domain.Tag{Id: 1, Name: "Fiction"}, nil)
+	u.trm.On("Create", u.ctx, u.tx, tag).Return(func(ctx context.Context, db repository.DBTX, tag *domain.Tag) *domain.Tag {
+		tag.Id = 4
+		return tag
+	}, nil)
+
+	result, err := u.ts.Create(u.ctx, &dto.NewTagRequest{Name: "Fantasy", ParentId: parentId(1)})
+
+	u.Nil(err)
+	u.Equal(&dto.TagResponse{Id: 4, Name: "Fantasy", ParentId: parentId(1)}, result)
+}
+
+func (u *unitTestTagServiceSuite) TestCreate_UnknownParent() {
+	u.expectTx(nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(8)).Return(nil, &repository.Error{Op: "FindOneTagById", Kind: repository.ErrNotFound})
+
+	result, err := u.ts.Create(u.ctx, &dto.NewTagRequest{Name: "Fantasy", ParentId: parentId(8)})
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal([]*dto.FieldError{{Field: "parent_id", Rule: "exists", Message: "tag 8 does not exist"}}, apperror.From(err).FieldErrors())
+}
+
+func (u *unitTestTagServiceSuite) TestCreate_Duplicate() {
+	u.expectTx(nil)
+	u.trm.On("Create", u.ctx, u.tx, mock.Anything).Return(nil, &repository.Error{Op: "CreateTag", Kind: repository.ErrUniqueViolation})
+	u.trm.On("FindByNames", u.ctx, mock.Anything, []string{"fiction"}).Return([]*domain.Tag{{Id: 1, Name: "Fiction"}}, nil)
+
+	result, err := u.ts.Create(u.ctx, &dto.NewTagRequest{Name: "fiction"})
+
+	u.Nil(result)
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(map[string]any{"existing_id": uint(1)}, apperror.From(err).Extensions())
+}
+
+func (u *unitTestTagServiceSuite) TestFindAll_Pages() {
+	params := &domain.TagListParams{Limit: 2, Offset: 2, NameContains: "fi"}
+
+	u.expectTx(listTxOptions)
+	u.trm.On("FindAll", u.ctx, u.tx, params).Return([]*domain.Tag{{Id: 1, Name: "Fiction"}}, nil)
+	u.trm.On("Count", u.ctx, u.tx, params).Return(uint(3), nil)
+
+	result, meta, err := u.ts.FindAll(u.ctx, &dto.FindAllTagRequest{Page: 2, PageSize: 2, NameContains: " fi "})
+
+	u.Nil(err)
+	u.Equal([]*dto.TagResponse{{Id: 1, Name: "Fiction"}}, result)
+	u.Equal(&dto.PaginationMeta{Page: 2, PageSize: 2, TotalCount: 3, TotalPages: 2}, meta)
+}
+
+func (u *unitTestTagServiceSuite) TestUpdate_Cycle() {
+	u.expectTx(nil)
+	u.trm.On("FindOneById", u.ctx, u.tx, uint(4)).Return(&domain.Tag{Id: 4, Name: "Fantasy", ParentId: parentId(1)}, nil)
+	// fantasy is nested under fiction, so fiction cannot move under it
+	u.trm.On("FindSubtreeIds", u.ctx, u.tx, []uint{1}).Return([]uint{1, 4}, nil)
+
+	result, err := u.ts.Update(u.ctx, 1, &dto.NewTagRequest{Name: "Fiction", ParentId: parentId(4)})
+
+	u.Nil(result)
+	u.Equal(http.StatusUnprocessableEntity, err.StatusCode())
+	u.Equal("cycle", apperror.From(err).FieldErrors()[0].Rule)
+	u.trm.AssertNotCalled(u.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *unitTestTagServiceSuite) TestUpdate_NotFound() {
+	u.expectTx(nil)
+	u.trm.On("Update", u.ctx, u.tx, &domain.Tag{Id: 9, Name: "Fiction"}).Return(nil, &repository.Error{Op: "UpdateTag", Kind: repository.ErrNotFound})
+
+	result, err := u.ts.Update(u.ctx, 9, &dto.NewTagRequest{Name: "Fiction"})
+
+	u.Nil(result)
+	u.Equal(http.StatusNotFound, err.StatusCode())
+}
+
+func (u *unitTestTagServiceSuite) TestDelete_Success() {
+	u.expectTx(nil)
+	u.trm.On("Delete", u.ctx, u.tx, uint(4)).Return(nil)
+	u.brm.On("RemoveTagFromBooks", u.ctx, u.tx, uint(4)).Return(nil)
+
+	u.Nil(u.ts.Delete(u.ctx, 4))
+}
+
+func (u *unitTestTagServiceSuite) TestDelete_HasChildren() {
+	u.expectTx(nil)
+	u.trm.On("Delete", u.ctx, u.tx, uint(1)).Return(&repository.Error{Op: "DeleteTag", Kind: repository.ErrForeignKeyViolation})
+
+	err := u.ts.Delete(u.ctx, 1)
+
+	u.Equal(http.StatusConflict, err.StatusCode())
+	u.Equal(apperror.CodeInUse, apperror.CodeOf(err))
+}
+
+func (u *unitTestTagServiceSuite) TestPolicy_Reader() {
+	ctx := authtest.WithRoles(context.Background(), "reader")
+
+	_, err := u.ts.Create(ctx, &dto.NewTagRequest{Name: "Fiction"})
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.Equal(map[string]any{"permission": auth.PermissionWriteBooks}, apperror.From(err).Extensions())
+	u.tmm.AssertNotCalled(u.T(), "WithinTx", mock.Anything, mock.Anything, mock.Anything)
+
+	u.trm.On("FindOneById", ctx, mock.Anything, uint(1)).Return(&domain.Tag{Id: 1, Name: "Fiction"}, nil)
+
+	result, err := u.ts.FindOneById(ctx, 1)
+	u.Nil(err)
+	u.Equal("Fiction", result.Name)
+}
+
+func (u *unitTestTagServiceSuite) TestPolicy_EditorCannotDelete() {
+	err := u.ts.Delete(authtest.WithRoles(context.Background(), "editor"), 1)
+
+	u.Equal(http.StatusForbidden, err.StatusCode())
+	u.tmm.AssertNotCalled(u.T(), "WithinTx", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,37 @@
+// Package tenant carries the tenant a request acts for in the request context. Every team
+// sharing a deployment is a tenant with its own catalogue, the repositories only ever read
+// and write the rows of the tenant carried by their context.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of requests when tenancy is off, and of every row stored before it
+// was turned on.
+const Default = "default"
+
+// idPattern is what a tenant id looks like, a DNS label so it can also be a subdomain.
+var idPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Valid tells whether id can name a tenant.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying the tenant id.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant carried by ctx, Default when there is none.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Default
+}
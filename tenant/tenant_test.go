@@ -0,0 +1,32 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type unitTestTenantSuite struct {
+	suite.Suite
+}
+
+func TestUnitTestTenant(t *testing.T) {
+	suite.Run(t, &unitTestTenantSuite{})
+}
+
+func (u *unitTestTenantSuite) TestValid() {
+	for _, id := range []string{"acme", "a", "acme-corp", "2024", strings.Repeat("a", 63)} {
+		u.True(Valid(id), id)
+	}
+
+	for _, id := range []string{"", "Acme", "-acme", "acme-", "acme.corp", "acme_corp", "../acme", strings.Repeat("a", 64)} {
+		u.False(Valid(id), id)
+	}
+}
+
+func (u *unitTestTenantSuite) TestFromContext() {
+	u.Equal(Default, FromContext(context.Background()))
+	u.Equal("acme", FromContext(WithContext(context.Background(), "acme")))
+}